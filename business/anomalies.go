@@ -0,0 +1,157 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// anomalySignals lists the compared signals, in the order of the anomalies of an object
+var anomalySignals = []string{models.AnomalyRequestRate, models.AnomalyErrorRatio, models.AnomalyLatency}
+
+// GetAnomalies compares the request rate, the error ratio and the latency of the requests received by the objects
+// of the query to their values at the same time of each configured baseline (i.e. yesterday, last week).
+// It returns the comparisons by object name; objects without requests, now and in the baselines, are left out.
+func (in *MetricsService) GetAnomalies(q models.AnomaliesQuery) (map[string][]models.Anomaly, error) {
+	conf := config.Get().HealthConfig.Anomalies
+
+	var selector latencySelector
+	switch q.Kind {
+	case "app":
+		selector = itemLatencySelectors(q.Namespace, q.Name, "canonical_service")[0]
+	case "service":
+		selector = serviceLatencySelectors(q.Namespace, q.Name)[0]
+	case "workload":
+		selector = itemLatencySelectors(q.Namespace, q.Name, "workload")[0]
+	default:
+		return nil, fmt.Errorf("invalid kind %s, expecting app, service or workload", q.Kind)
+	}
+
+	signals, err := in.prom.GetRequestSignals(selector.labels, selector.nameLabel, conf.Quantile, q.RateInterval, "", q.QueryTime)
+	if err != nil {
+		return nil, err
+	}
+	current := signalValues(signals, selector.nameLabel)
+
+	anomalies := map[string][]models.Anomaly{}
+	for _, baseline := range conf.Baselines {
+		signals, err := in.prom.GetRequestSignals(selector.labels, selector.nameLabel, conf.Quantile, q.RateInterval, baseline, q.QueryTime)
+		if err != nil {
+			return anomalies, err
+		}
+		expected := signalValues(signals, selector.nameLabel)
+
+		names := map[string]bool{}
+		for name := range current {
+			names[name] = true
+		}
+		for name := range expected {
+			names[name] = true
+		}
+		for name := range names {
+			// An object without requests has a null request rate
+			for _, values := range []map[string]map[string]float64{current, expected} {
+				if values[name] == nil {
+					values[name] = map[string]float64{}
+				}
+				if _, ok := values[name][models.AnomalyRequestRate]; !ok {
+					values[name][models.AnomalyRequestRate] = 0
+				}
+				// Without errors, the error ratio query has no sample
+				if _, ok := values[name][models.AnomalyErrorRatio]; !ok && values[name][models.AnomalyRequestRate] > 0 {
+					values[name][models.AnomalyErrorRatio] = 0
+				}
+			}
+			for _, signal := range anomalySignals {
+				anomalies[name] = append(anomalies[name], compareSignal(signal, baseline, current[name], expected[name], conf))
+			}
+		}
+	}
+	return anomalies, nil
+}
+
+// signalValues indexes the values of the signals by object name then signal, leaving out the NaN ones
+func signalValues(signals prometheus.RequestSignals, nameLabel string) map[string]map[string]float64 {
+	values := map[string]map[string]float64{}
+	vectors := map[string]model.Vector{
+		models.AnomalyRequestRate: signals.Rates,
+		models.AnomalyErrorRatio:  signals.ErrorRatios,
+		models.AnomalyLatency:     signals.Latencies,
+	}
+	for signal, vector := range vectors {
+		for _, sample := range vector {
+			if math.IsNaN(float64(sample.Value)) {
+				continue
+			}
+			name := string(sample.Metric[model.LabelName(nameLabel)])
+			if values[name] == nil {
+				values[name] = map[string]float64{}
+			}
+			values[name][signal] = float64(sample.Value)
+		}
+	}
+	return values
+}
+
+// compareSignal scores the deviation of a signal from its baseline. Signals lower than their minimum, now and in the
+// baseline, are not compared since a small change of a small value is not significant.
+func compareSignal(signal, baseline string, current, expected map[string]float64, conf config.HealthAnomaliesConfig) models.Anomaly {
+	anomaly := models.Anomaly{Signal: signal, Baseline: baseline}
+	currentValue, currentOk := current[signal]
+	if currentOk {
+		anomaly.Current = &currentValue
+	}
+	expectedValue, expectedOk := expected[signal]
+	if expectedOk {
+		anomaly.Expected = &expectedValue
+	}
+	if !currentOk || !expectedOk {
+		return anomaly
+	}
+
+	minimum := map[string]float64{
+		models.AnomalyRequestRate: conf.MinRate,
+		models.AnomalyErrorRatio:  conf.MinErrorRatio,
+		models.AnomalyLatency:     conf.MinLatency,
+	}[signal]
+	if math.Max(currentValue, expectedValue) < minimum || math.Max(expectedValue, minimum) == 0 {
+		return anomaly
+	}
+
+	anomaly.Score = (currentValue - expectedValue) / math.Max(expectedValue, minimum)
+	if conf.Threshold > 0 {
+		if signal == models.AnomalyRequestRate {
+			anomaly.Anomalous = math.Abs(anomaly.Score) >= conf.Threshold
+		} else {
+			anomaly.Anomalous = anomaly.Score >= conf.Threshold
+		}
+	}
+	return anomaly
+}
+
+// getAnomalies compares the requests received by an object, or by all the objects of the kind in the namespace when
+// the name is empty, to their baselines when the anomaly detection is enabled.
+// Errors are logged, the health being still relevant without anomalies.
+func (in *HealthService) getAnomalies(namespace, kind, name, rateInterval string, queryTime time.Time) map[string][]models.Anomaly {
+	if !config.Get().HealthConfig.Anomalies.Enabled {
+		return nil
+	}
+	anomalies, err := NewMetricsService(in.prom).GetAnomalies(models.AnomaliesQuery{
+		Namespace:    namespace,
+		Kind:         kind,
+		Name:         name,
+		RateInterval: rateInterval,
+		QueryTime:    queryTime,
+	})
+	if err != nil {
+		log.Errorf("Error detecting anomalies of %s [%s] in namespace %s: %s", kind, name, namespace, err)
+	}
+	return anomalies
+}
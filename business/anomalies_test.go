@@ -0,0 +1,85 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func workloadSample(workload string, value float64) *model.Sample {
+	return &model.Sample{Metric: model.Metric{"destination_workload": model.LabelValue(workload)}, Value: model.SampleValue(value)}
+}
+
+func TestGetAnomalies(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.HealthConfig.Anomalies.Baselines = []string{"1w"}
+	config.Set(conf)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `reporter="destination",destination_workload_namespace="bookinfo"`
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetRequestSignals", labels, "destination_workload", 0.95, "5m", "", queryTime).Return(prometheus.RequestSignals{
+		Rates:       model.Vector{workloadSample("reviews-v1", 2), workloadSample("reviews-v2", 5), workloadSample("details-v1", 3)},
+		ErrorRatios: model.Vector{workloadSample("reviews-v1", 0.2), workloadSample("reviews-v2", 0), workloadSample("details-v1", 0.5)},
+		Latencies:   model.Vector{workloadSample("reviews-v1", 100), workloadSample("reviews-v2", 20), workloadSample("details-v1", 10)},
+	}, nil)
+	prom.On("GetRequestSignals", labels, "destination_workload", 0.95, "5m", "1w", queryTime).Return(prometheus.RequestSignals{
+		Rates:       model.Vector{workloadSample("reviews-v1", 10), workloadSample("ratings-v1", 4), workloadSample("details-v1", 3)},
+		ErrorRatios: model.Vector{workloadSample("reviews-v1", 0.01), workloadSample("ratings-v1", 0)},
+		Latencies:   model.Vector{workloadSample("reviews-v1", 90), workloadSample("ratings-v1", 20)},
+	}, nil)
+
+	metrics := NewMetricsService(prom)
+	anomalies, err := metrics.GetAnomalies(models.AnomaliesQuery{Namespace: "bookinfo", Kind: "workload", RateInterval: "5m", QueryTime: queryTime})
+
+	assert.NoError(err)
+	assert.Len(anomalies, 4)
+
+	// Traffic dropped by 80% and errors increased, the latency is stable
+	reviews := anomalies["reviews-v1"]
+	assert.Len(reviews, 3)
+	assert.Equal(models.AnomalyRequestRate, reviews[0].Signal)
+	assert.Equal("1w", reviews[0].Baseline)
+	assert.InDelta(-0.8, reviews[0].Score, 1e-9)
+	assert.True(reviews[0].Anomalous)
+	assert.Equal(models.AnomalyErrorRatio, reviews[1].Signal)
+	assert.InDelta(19, reviews[1].Score, 1e-9)
+	assert.True(reviews[1].Anomalous)
+	assert.Equal(models.AnomalyLatency, reviews[2].Signal)
+	assert.InDelta(0.111, reviews[2].Score, 1e-3)
+	assert.False(reviews[2].Anomalous)
+
+	// New traffic is a spike, compared to the minimum rate
+	v2 := anomalies["reviews-v2"]
+	assert.Equal(0.0, *v2[0].Expected)
+	assert.InDelta(50, v2[0].Score, 1e-9)
+	assert.True(v2[0].Anomalous)
+	assert.Nil(v2[1].Expected)
+	assert.False(v2[1].Anomalous)
+
+	// Stopped traffic
+	ratings := anomalies["ratings-v1"]
+	assert.Equal(0.0, *ratings[0].Current)
+	assert.InDelta(-1, ratings[0].Score, 1e-9)
+	assert.True(ratings[0].Anomalous)
+	assert.Nil(ratings[1].Current)
+	assert.False(ratings[1].Anomalous)
+
+	// Errors appeared, the baseline had requests without errors
+	details := anomalies["details-v1"]
+	assert.Equal(0.0, *details[1].Expected)
+	assert.Equal(0.5, *details[1].Current)
+	assert.True(details[1].Anomalous)
+
+	_, err = metrics.GetAnomalies(models.AnomaliesQuery{Namespace: "bookinfo", Kind: "pod"})
+	assert.Error(err)
+}
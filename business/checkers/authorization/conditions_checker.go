@@ -0,0 +1,52 @@
+package authorization
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// Keys supported in the when conditions
+// https://istio.io/latest/docs/reference/config/security/conditions/
+var conditionKeyMatcher = regexp.MustCompile(`^(request\.headers\[.+\]|source\.ip|remote\.ip|source\.namespace|source\.principal|request\.auth\.principal|request\.auth\.audiences|request\.auth\.presenter|request\.auth\.claims\[.+\]|destination\.ip|destination\.port|connection\.sni|experimental\.envoy\.filters\..+)$`)
+
+type ConditionsChecker struct {
+	AuthorizationPolicy kubernetes.IstioObject
+}
+
+// Check validates that the keys of the when conditions are supported by Istio
+func (cc ConditionsChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	rules, ok := cc.AuthorizationPolicy.GetSpec()["rules"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	for ruleIdx, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions, ok := rule["when"].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, c := range conditions {
+			condition, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if key, ok := condition["key"].(string); !ok || !conditionKeyMatcher.MatchString(key) {
+				valid = false
+				path := fmt.Sprintf("spec/rules[%d]/when[%d]", ruleIdx, i)
+				check := models.Build("authorizationpolicy.when.invalidkey", path)
+				checks = append(checks, &check)
+			}
+		}
+	}
+
+	return checks, valid
+}
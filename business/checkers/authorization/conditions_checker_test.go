@@ -0,0 +1,49 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestConditionsValidKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := ConditionsChecker{
+		AuthorizationPolicy: conditionsAuthPolicy("request.headers[version]", "source.ip", "request.auth.claims[iss]", "destination.port"),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestConditionsInvalidKeys(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := ConditionsChecker{
+		AuthorizationPolicy: conditionsAuthPolicy("source.ip", "request.header[version]", "source.namespaces"),
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 2)
+	assert.Equal(models.CheckMessage("authorizationpolicy.when.invalidkey"), validations[0].Message)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal("spec/rules[0]/when[1]", validations[0].Path)
+	assert.Equal("spec/rules[0]/when[2]", validations[1].Path)
+}
+
+func conditionsAuthPolicy(keys ...string) kubernetes.IstioObject {
+	conditions := make([]interface{}, 0, len(keys))
+	for _, k := range keys {
+		conditions = append(conditions, map[string]interface{}{"key": k, "values": []interface{}{"value"}})
+	}
+	return data.CreateAuthorizationPolicyWithRules("auth-policy", "ALLOW", nil, []interface{}{
+		map[string]interface{}{
+			"when": conditions,
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package authorization
+
+import (
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type DenyAllChecker struct {
+	AuthorizationPolicy kubernetes.IstioObject
+}
+
+// Check warns about ALLOW policies without rules, as they don't match any request
+// and therefore deny all the requests sent to the selected workloads.
+func (dc DenyAllChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	if action, ok := dc.AuthorizationPolicy.GetSpec()["action"].(string); ok && action != "ALLOW" {
+		return checks, valid
+	}
+
+	if rules, ok := dc.AuthorizationPolicy.GetSpec()["rules"].([]interface{}); ok && len(rules) > 0 {
+		return checks, valid
+	}
+
+	check := models.Build("authorizationpolicy.allow.denyall", "spec")
+	checks = append(checks, &check)
+	return checks, valid
+}
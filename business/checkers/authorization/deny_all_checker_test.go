@@ -0,0 +1,46 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestAllowWithoutRules(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := DenyAllChecker{
+		AuthorizationPolicy: data.CreateAuthorizationPolicyWithRules("allow-nothing", "ALLOW", nil, nil),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("authorizationpolicy.allow.denyall"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec", validations[0].Path)
+}
+
+func TestAllowWithRules(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := DenyAllChecker{
+		AuthorizationPolicy: data.CreateAuthorizationPolicyWithRules("allow-all", "ALLOW", nil, []interface{}{map[string]interface{}{}}),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestDenyWithoutRules(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := DenyAllChecker{
+		AuthorizationPolicy: data.CreateAuthorizationPolicyWithRules("deny-nothing", "DENY", nil, nil),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
@@ -0,0 +1,107 @@
+package authorization
+
+import (
+	"fmt"
+	"strconv"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type PortsChecker struct {
+	AuthorizationPolicy kubernetes.IstioObject
+	Services            []core_v1.Service
+	WorkloadList        models.WorkloadList
+}
+
+// Check validates that the operation ports are exposed by the Services of the workloads selected by the policy.
+// Both the Service port and its target port are accepted.
+func (pc PortsChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	// The policies of the root namespace apply to the workloads of every namespace, whose Services are not known here
+	if pc.AuthorizationPolicy.GetObjectMeta().Namespace == config.Get().IstioNamespace {
+		return checks, valid
+	}
+
+	rules, ok := pc.AuthorizationPolicy.GetSpec()["rules"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	ports, resolved := pc.selectedPorts()
+	if !resolved || len(ports) == 0 {
+		return checks, valid
+	}
+
+	for ruleIdx, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tos, ok := rule["to"].([]interface{})
+		if !ok {
+			continue
+		}
+		for toIdx, t := range tos {
+			to, ok := t.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			operation, ok := to["operation"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			opPorts, ok := operation["ports"].([]interface{})
+			if !ok {
+				continue
+			}
+			for i, p := range opPorts {
+				if port, err := strconv.Atoi(fmt.Sprintf("%v", p)); err == nil && ports[port] {
+					continue
+				}
+				path := fmt.Sprintf("spec/rules[%d]/to[%d]/operation/ports[%d]", ruleIdx, toIdx, i)
+				check := models.Build("authorizationpolicy.to.portnotfound", path)
+				checks = append(checks, &check)
+			}
+		}
+	}
+
+	return checks, valid
+}
+
+// selectedPorts returns the ports of the Services exposing the workloads selected by the policy.
+// The ports are not resolved when a Service uses a named target port, as the container ports of the workloads are unknown.
+func (pc PortsChecker) selectedPorts() (map[int]bool, bool) {
+	ports := map[int]bool{}
+
+	selector := labels.SelectorFromSet(common.GetSelectorLabels(pc.AuthorizationPolicy))
+	for _, wk := range pc.WorkloadList.Workloads {
+		wkLabels := labels.Set(wk.Labels)
+		if !selector.Matches(wkLabels) {
+			continue
+		}
+		for _, svc := range pc.Services {
+			if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(wkLabels) {
+				continue
+			}
+			for _, port := range svc.Spec.Ports {
+				ports[int(port.Port)] = true
+				if port.TargetPort.Type == intstr.String {
+					return ports, false
+				}
+				if port.TargetPort.IntValue() > 0 {
+					ports[port.TargetPort.IntValue()] = true
+				}
+			}
+		}
+	}
+
+	return ports, true
+}
@@ -0,0 +1,136 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestPortsExisting(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := PortsChecker{
+		AuthorizationPolicy: portsAuthPolicy([]interface{}{"9080", "8080"}),
+		Services:            fakePortServices(),
+		WorkloadList:        fakePortWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestPortsNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := PortsChecker{
+		AuthorizationPolicy: portsAuthPolicy([]interface{}{"9080", "9090", "wrong"}),
+		Services:            fakePortServices(),
+		WorkloadList:        fakePortWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 2)
+	assert.Equal(models.CheckMessage("authorizationpolicy.to.portnotfound"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/rules[0]/to[0]/operation/ports[1]", validations[0].Path)
+	assert.Equal("spec/rules[0]/to[0]/operation/ports[2]", validations[1].Path)
+}
+
+func TestPortsWithoutServices(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := PortsChecker{
+		AuthorizationPolicy: portsAuthPolicy([]interface{}{"9090"}),
+		WorkloadList:        fakePortWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestPortsNamedTargetPort(t *testing.T) {
+	assert := assert.New(t)
+
+	services := fakePortServices()
+	services[0].Spec.Ports = []core_v1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromString("http")}}
+
+	// The container port behind the named target port is unknown
+	validations, valid := PortsChecker{
+		AuthorizationPolicy: portsAuthPolicy([]interface{}{"9080"}),
+		Services:            services,
+		WorkloadList:        fakePortWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestPortsRootNamespace(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	authPolicy := portsAuthPolicy([]interface{}{"9090"})
+	meta := authPolicy.GetObjectMeta()
+	meta.Namespace = conf.IstioNamespace
+	authPolicy.SetObjectMeta(meta)
+
+	// Mesh-wide policies apply to the Services of every namespace
+	validations, valid := PortsChecker{
+		AuthorizationPolicy: authPolicy,
+		Services:            fakePortServices(),
+		WorkloadList:        fakePortWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func portsAuthPolicy(ports []interface{}) kubernetes.IstioObject {
+	return data.CreateAuthorizationPolicyWithRules("auth-policy", "ALLOW", map[string]interface{}{"app": "details"}, []interface{}{
+		map[string]interface{}{
+			"to": []interface{}{
+				map[string]interface{}{
+					"operation": map[string]interface{}{
+						"ports": ports,
+					},
+				},
+			},
+		},
+	})
+}
+
+func fakePortWorkloads() models.WorkloadList {
+	return data.CreateWorkloadList("bookinfo",
+		data.CreateWorkloadListItem("details-v1", map[string]string{"app": "details", "version": "v1"}),
+		data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+	)
+}
+
+func fakePortServices() []core_v1.Service {
+	return []core_v1.Service{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "details", Namespace: "bookinfo"},
+			Spec: core_v1.ServiceSpec{
+				Selector: map[string]string{"app": "details"},
+				Ports:    []core_v1.ServicePort{{Name: "http", Port: 9080, TargetPort: intstr.FromInt(8080)}},
+			},
+		},
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+			Spec: core_v1.ServiceSpec{
+				Selector: map[string]string{"app": "reviews"},
+				Ports:    []core_v1.ServicePort{{Name: "http", Port: 9090}},
+			},
+		},
+	}
+}
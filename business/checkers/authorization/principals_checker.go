@@ -0,0 +1,117 @@
+package authorization
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type PrincipalsChecker struct {
+	AuthorizationPolicy kubernetes.IstioObject
+	// ServiceAccounts of the namespaces referenced by the principals, missing for the namespaces that can't be read
+	ServiceAccounts map[string][]string
+}
+
+// Check validates that the principals of the sources reference existing service accounts.
+// Principals of namespaces whose service accounts can't be read are skipped.
+func (pc PrincipalsChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	forEachPrincipal(pc.AuthorizationPolicy, func(principal, path string) {
+		if !pc.hasServiceAccount(principal) {
+			check := models.Build("authorizationpolicy.source.principalnotfound", path)
+			checks = append(checks, &check)
+		}
+	})
+
+	return checks, valid
+}
+
+// PrincipalsNamespaces returns the namespaces of the service accounts referenced by the principals of the policies
+func PrincipalsNamespaces(authPolicies []kubernetes.IstioObject) []string {
+	namespaces := []string{}
+	found := map[string]bool{}
+	for _, authPolicy := range authPolicies {
+		forEachPrincipal(authPolicy, func(principal, _ string) {
+			if namespace, _, ok := parsePrincipal(principal); ok && !found[namespace] {
+				found[namespace] = true
+				namespaces = append(namespaces, namespace)
+			}
+		})
+	}
+	return namespaces
+}
+
+// forEachPrincipal calls f with every principal of the sources of the policy and its path
+func forEachPrincipal(authPolicy kubernetes.IstioObject, f func(principal, path string)) {
+	rules, ok := authPolicy.GetSpec()["rules"].([]interface{})
+	if !ok {
+		return
+	}
+
+	for ruleIdx, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		froms, ok := rule["from"].([]interface{})
+		if !ok {
+			continue
+		}
+		for fromIdx, fr := range froms {
+			from, ok := fr.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			source, ok := from["source"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			principals, ok := source["principals"].([]interface{})
+			if !ok {
+				continue
+			}
+			for i, p := range principals {
+				if principal, ok := p.(string); ok {
+					f(principal, fmt.Sprintf("spec/rules[%d]/from[%d]/source/principals[%d]", ruleIdx, fromIdx, i))
+				}
+			}
+		}
+	}
+}
+
+// parsePrincipal returns the namespace and the service account of a principal with the format
+// <trust-domain>/ns/<namespace>/sa/<service-account>. Prefix and suffix matches (i.e. "*",
+// "cluster.local/ns/bookinfo/sa/*") are not parsed.
+func parsePrincipal(principal string) (string, string, bool) {
+	if strings.Contains(principal, "*") {
+		return "", "", false
+	}
+	parts := strings.Split(principal, "/")
+	if len(parts) < 5 || parts[len(parts)-4] != "ns" || parts[len(parts)-2] != "sa" {
+		return "", "", false
+	}
+	return parts[len(parts)-3], parts[len(parts)-1], true
+}
+
+// hasServiceAccount returns false only when the principal references a service account
+// that doesn't exist in a namespace whose service accounts are known.
+func (pc PrincipalsChecker) hasServiceAccount(principal string) bool {
+	namespace, serviceAccount, ok := parsePrincipal(principal)
+	if !ok {
+		return true
+	}
+
+	serviceAccounts, found := pc.ServiceAccounts[namespace]
+	if !found {
+		return true
+	}
+	for _, sa := range serviceAccounts {
+		if sa == serviceAccount {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestPrincipalsExisting(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := PrincipalsChecker{
+		AuthorizationPolicy: principalsAuthPolicy([]interface{}{
+			"cluster.local/ns/bookinfo/sa/bookinfo-reviews",
+			"cluster.local/ns/bookinfo/sa/*",
+			"*",
+			"cluster.local/ns/unknown/sa/default",
+		}),
+		ServiceAccounts: map[string][]string{"bookinfo": {"bookinfo-reviews", "default"}},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestPrincipalsNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := PrincipalsChecker{
+		AuthorizationPolicy: principalsAuthPolicy([]interface{}{
+			"cluster.local/ns/bookinfo/sa/bookinfo-reviews",
+			"cluster.local/ns/bookinfo/sa/bookinfo-ratings",
+		}),
+		ServiceAccounts: map[string][]string{"bookinfo": {"bookinfo-reviews"}},
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("authorizationpolicy.source.principalnotfound"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/rules[0]/from[0]/source/principals[1]", validations[0].Path)
+}
+
+func TestPrincipalsNamespaces(t *testing.T) {
+	assert := assert.New(t)
+
+	namespaces := PrincipalsNamespaces([]kubernetes.IstioObject{
+		principalsAuthPolicy([]interface{}{"cluster.local/ns/bookinfo/sa/bookinfo-reviews", "cluster.local/ns/default/sa/*"}),
+		principalsAuthPolicy([]interface{}{"cluster.local/ns/bookinfo/sa/default", "cluster.local/ns/istio-system/sa/ingress", "*"}),
+	})
+
+	assert.Equal([]string{"bookinfo", "istio-system"}, namespaces)
+}
+
+func principalsAuthPolicy(principals []interface{}) kubernetes.IstioObject {
+	return data.CreateAuthorizationPolicyWithRules("auth-policy", "ALLOW", map[string]interface{}{"app": "details"}, []interface{}{
+		map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{
+					"source": map[string]interface{}{
+						"principals": principals,
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,105 @@
+package authorization
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type UnreachableRulesChecker struct {
+	AuthorizationPolicies []kubernetes.IstioObject
+}
+
+// Check marks the rules of ALLOW policies that can't ever be applied because DENY policies,
+// evaluated first, reject all the requests matched by them.
+// A DENY rule covers an ALLOW rule when the ALLOW rule contains all its from, to and when conditions,
+// and the DENY policy is applied at least to the same workloads.
+func (uc UnreachableRulesChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, allow := range uc.AuthorizationPolicies {
+		if policyAction(allow) != "ALLOW" {
+			continue
+		}
+		allowRules, ok := allow.GetSpec()["rules"].([]interface{})
+		if !ok {
+			continue
+		}
+
+		for ruleIdx, allowRule := range allowRules {
+			for _, deny := range uc.AuthorizationPolicies {
+				if policyAction(deny) != "DENY" || !appliesToSameWorkloads(deny, allow) || !denyCoversRule(deny, allowRule) {
+					continue
+				}
+
+				key := models.BuildKey(objectType, allow.GetObjectMeta().Name, allow.GetObjectMeta().Namespace)
+				check := models.Build("authorizationpolicy.rule.unreachable", fmt.Sprintf("spec/rules[%d]", ruleIdx))
+				validations.MergeValidations(models.IstioValidations{key: &models.IstioValidation{
+					Name:       allow.GetObjectMeta().Name,
+					ObjectType: objectType,
+					Valid:      true,
+					Checks:     []*models.IstioCheck{&check},
+					References: []models.IstioValidationKey{models.BuildKey(objectType, deny.GetObjectMeta().Name, deny.GetObjectMeta().Namespace)},
+				}})
+				break
+			}
+		}
+	}
+
+	return validations
+}
+
+func policyAction(ap kubernetes.IstioObject) string {
+	if action, ok := ap.GetSpec()["action"].(string); ok {
+		return action
+	}
+	return "ALLOW"
+}
+
+// appliesToSameWorkloads checks whether the deny policy selects, at least, all the workloads selected by the allow policy
+func appliesToSameWorkloads(deny, allow kubernetes.IstioObject) bool {
+	if deny.GetObjectMeta().Namespace != allow.GetObjectMeta().Namespace {
+		return false
+	}
+	allowLabels := common.GetSelectorLabels(allow)
+	for k, v := range common.GetSelectorLabels(deny) {
+		if allowLabels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// denyCoversRule checks whether any rule of the deny policy matches all the requests matched by the allow rule
+func denyCoversRule(deny kubernetes.IstioObject, allowRule interface{}) bool {
+	allowMap, ok := allowRule.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	denyRules, ok := deny.GetSpec()["rules"].([]interface{})
+	if !ok {
+		return false
+	}
+
+NextRule:
+	for _, dr := range denyRules {
+		denyMap, ok := dr.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, field := range []string{"from", "to", "when"} {
+			denyValue, found := denyMap[field]
+			if !found {
+				continue
+			}
+			if allowValue, found := allowMap[field]; !found || !reflect.DeepEqual(denyValue, allowValue) {
+				continue NextRule
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package authorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestUnreachableRules(t *testing.T) {
+	assert := assert.New(t)
+
+	getRule := map[string]interface{}{
+		"to": []interface{}{map[string]interface{}{"operation": map[string]interface{}{"methods": []interface{}{"GET"}}}},
+	}
+	postFromBookinfo := map[string]interface{}{
+		"from": []interface{}{map[string]interface{}{"source": map[string]interface{}{"namespaces": []interface{}{"bookinfo"}}}},
+		"to":   []interface{}{map[string]interface{}{"operation": map[string]interface{}{"methods": []interface{}{"POST"}}}},
+	}
+	allow := data.CreateAuthorizationPolicyWithRules("allow", "ALLOW", map[string]interface{}{"app": "details", "version": "v1"},
+		[]interface{}{getRule, postFromBookinfo})
+	deny := data.CreateAuthorizationPolicyWithRules("deny", "DENY", map[string]interface{}{"app": "details"},
+		[]interface{}{map[string]interface{}{
+			"to": []interface{}{map[string]interface{}{"operation": map[string]interface{}{"methods": []interface{}{"POST"}}}},
+		}})
+
+	validations := UnreachableRulesChecker{
+		AuthorizationPolicies: []kubernetes.IstioObject{allow, deny},
+	}.Check()
+
+	assert.Len(validations, 1)
+	validation, found := validations[models.BuildKey("authorizationpolicy", "allow", "bookinfo")]
+	assert.True(found)
+	assert.True(validation.Valid)
+	assert.Len(validation.Checks, 1)
+	assert.Equal(models.CheckMessage("authorizationpolicy.rule.unreachable"), validation.Checks[0].Message)
+	assert.Equal("spec/rules[1]", validation.Checks[0].Path)
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("authorizationpolicy", "deny", "bookinfo")}, validation.References)
+}
+
+func TestReachableRulesNarrowerDenySelector(t *testing.T) {
+	assert := assert.New(t)
+
+	rule := map[string]interface{}{
+		"to": []interface{}{map[string]interface{}{"operation": map[string]interface{}{"methods": []interface{}{"POST"}}}},
+	}
+	allow := data.CreateAuthorizationPolicyWithRules("allow", "ALLOW", map[string]interface{}{"app": "details"}, []interface{}{rule})
+	deny := data.CreateAuthorizationPolicyWithRules("deny", "DENY", map[string]interface{}{"app": "details", "version": "v1"}, []interface{}{rule})
+
+	validations := UnreachableRulesChecker{
+		AuthorizationPolicies: []kubernetes.IstioObject{allow, deny},
+	}.Check()
+
+	assert.Empty(validations)
+}
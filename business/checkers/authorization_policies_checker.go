@@ -18,6 +18,7 @@ type AuthorizationPolicyChecker struct {
 	ServiceEntries        []kubernetes.IstioObject
 	Services              []core_v1.Service
 	WorkloadList          models.WorkloadList
+	ServiceAccounts       map[string][]string
 	MtlsDetails           kubernetes.MTLSDetails
 	VirtualServices       []kubernetes.IstioObject
 }
@@ -37,6 +38,10 @@ func (a AuthorizationPolicyChecker) Check() models.IstioValidations {
 		MtlsDetails:           a.MtlsDetails,
 	}.Check())
 
+	validations.MergeValidations(authorization.UnreachableRulesChecker{
+		AuthorizationPolicies: a.AuthorizationPolicies,
+	}.Check())
+
 	return validations
 }
 
@@ -51,6 +56,10 @@ func (a AuthorizationPolicyChecker) runChecks(authPolicy kubernetes.IstioObject)
 		authorization.NamespaceMethodChecker{AuthorizationPolicy: authPolicy, Namespaces: a.Namespaces.GetNames()},
 		authorization.NoHostChecker{AuthorizationPolicy: authPolicy, Namespace: a.Namespace, Namespaces: a.Namespaces,
 			ServiceEntries: serviceHosts, Services: a.Services, VirtualServices: a.VirtualServices},
+		authorization.PrincipalsChecker{AuthorizationPolicy: authPolicy, ServiceAccounts: a.ServiceAccounts},
+		authorization.PortsChecker{AuthorizationPolicy: authPolicy, Services: a.Services, WorkloadList: a.WorkloadList},
+		authorization.ConditionsChecker{AuthorizationPolicy: authPolicy},
+		authorization.DenyAllChecker{AuthorizationPolicy: authPolicy},
 	}
 
 	for _, checker := range enabledCheckers {
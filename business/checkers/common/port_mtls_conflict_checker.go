@@ -0,0 +1,284 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// PortMtlsConflictChecker analyzes the mTLS mode of each port of the workloads exposed by the Services.
+// A port requiring STRICT mTLS can't be called by the clients of a DestinationRule disabling TLS on the Service port:
+// both the PeerAuthentication deciding the mode and the DestinationRule are reported with the client -> server pair
+// that will fail the handshake. Workload-level PeerAuthentications setting different modes on the same port are reported too.
+type PortMtlsConflictChecker struct {
+	PeerAuthentications     []kubernetes.IstioObject
+	MeshPeerAuthentications []kubernetes.IstioObject
+	DestinationRules        []kubernetes.IstioObject
+	Services                []core_v1.Service
+	WorkloadList            models.WorkloadList
+}
+
+// portMtls is the mTLS mode of a port and the PeerAuthentication setting it
+type portMtls struct {
+	mode      string
+	peerAuthn kubernetes.IstioObject
+	path      string
+}
+
+func (c PortMtlsConflictChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, wk := range c.WorkloadList.Workloads {
+		wkLabels := labels.Set(wk.Labels)
+		wkPeerAuthns := c.workloadPeerAuthns(wkLabels)
+
+		for _, svc := range c.Services {
+			if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(wkLabels) {
+				continue
+			}
+			for _, port := range svc.Spec.Ports {
+				// Named target ports can't be resolved without the pods
+				if port.TargetPort.StrVal != "" {
+					continue
+				}
+				targetPort := port.TargetPort.IntValue()
+				if targetPort == 0 {
+					targetPort = int(port.Port)
+				}
+
+				validations.MergeValidations(conflictingModes(wkPeerAuthns, targetPort))
+
+				mtls := c.effectiveMtls(wkPeerAuthns, targetPort)
+				if mtls.mode != "STRICT" {
+					continue
+				}
+				for _, dr := range c.DestinationRules {
+					if !destinationRuleAppliesToService(dr, svc) {
+						continue
+					}
+					for _, path := range tlsDisabledPortPaths(dr, int(port.Port)) {
+						pair := fmt.Sprintf("%s -> %s.%s:%d", destinationRuleClients(dr, svc), wk.Name, svc.Namespace, targetPort)
+
+						paCheck := models.Build("peerauthentications.mtls.portdestinationruledisabled", mtls.path)
+						paCheck.Detail = pair
+						drCheck := models.Build("destinationrules.mtls.portpolicystrict", path)
+						drCheck.Detail = pair
+
+						paKey := objectKey("peerauthentication", mtls.peerAuthn)
+						drKey := objectKey("destinationrule", dr)
+						validations.MergeValidations(models.IstioValidations{
+							paKey: {Name: paKey.Name, ObjectType: paKey.ObjectType, Valid: false, Checks: []*models.IstioCheck{&paCheck}, References: []models.IstioValidationKey{drKey}},
+							drKey: {Name: drKey.Name, ObjectType: drKey.ObjectType, Valid: false, Checks: []*models.IstioCheck{&drCheck}, References: []models.IstioValidationKey{paKey}},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return validations
+}
+
+// workloadPeerAuthns returns the PeerAuthentications of the namespace selecting the workload
+func (c PortMtlsConflictChecker) workloadPeerAuthns(wkLabels labels.Set) []kubernetes.IstioObject {
+	peerAuthns := make([]kubernetes.IstioObject, 0)
+	for _, pa := range c.PeerAuthentications {
+		if !pa.HasMatchLabelsSelector() {
+			continue
+		}
+		if labels.SelectorFromSet(GetSelectorLabels(pa)).Matches(wkLabels) {
+			peerAuthns = append(peerAuthns, pa)
+		}
+	}
+	return peerAuthns
+}
+
+// effectiveMtls returns the mTLS mode applied on a workload port. Workload-level PeerAuthentications are applied first,
+// its port-level settings before its general mode, and then the namespace-wide and mesh-wide PeerAuthentications.
+func (c PortMtlsConflictChecker) effectiveMtls(wkPeerAuthns []kubernetes.IstioObject, port int) portMtls {
+	for _, pa := range wkPeerAuthns {
+		if mode := portLevelMode(pa, port); mode != "" {
+			return portMtls{mode: mode, peerAuthn: pa, path: fmt.Sprintf("spec/portLevelMtls/%d", port)}
+		}
+		if mode := mtlsMode(pa); mode != "" {
+			return portMtls{mode: mode, peerAuthn: pa, path: "spec/mtls/mode"}
+		}
+	}
+
+	for _, peerAuthns := range [][]kubernetes.IstioObject{c.PeerAuthentications, c.MeshPeerAuthentications} {
+		for _, pa := range peerAuthns {
+			if pa.HasMatchLabelsSelector() {
+				continue
+			}
+			if mode := mtlsMode(pa); mode != "" {
+				return portMtls{mode: mode, peerAuthn: pa, path: "spec/mtls/mode"}
+			}
+		}
+	}
+
+	return portMtls{mode: "PERMISSIVE"}
+}
+
+// conflictingModes reports the workload-level PeerAuthentications setting different modes on the same port
+func conflictingModes(wkPeerAuthns []kubernetes.IstioObject, port int) models.IstioValidations {
+	validations := models.IstioValidations{}
+	if len(wkPeerAuthns) < 2 {
+		return validations
+	}
+
+	modes := map[string]bool{}
+	explicit := make([]portMtls, 0, len(wkPeerAuthns))
+	for _, pa := range wkPeerAuthns {
+		if mode := portLevelMode(pa, port); mode != "" {
+			explicit = append(explicit, portMtls{mode: mode, peerAuthn: pa, path: fmt.Sprintf("spec/portLevelMtls/%d", port)})
+			modes[mode] = true
+		} else if mode := mtlsMode(pa); mode != "" {
+			explicit = append(explicit, portMtls{mode: mode, peerAuthn: pa, path: "spec/mtls/mode"})
+			modes[mode] = true
+		}
+	}
+	if len(modes) < 2 {
+		return validations
+	}
+
+	for i, pm := range explicit {
+		refs := make([]models.IstioValidationKey, 0, len(explicit)-1)
+		for j, other := range explicit {
+			if i != j {
+				refs = append(refs, objectKey("peerauthentication", other.peerAuthn))
+			}
+		}
+		check := models.Build("peerauthentications.mtls.portconflict", pm.path)
+		key := objectKey("peerauthentication", pm.peerAuthn)
+		validations.MergeValidations(models.IstioValidations{
+			key: {Name: key.Name, ObjectType: key.ObjectType, Valid: true, Checks: []*models.IstioCheck{&check}, References: refs},
+		})
+	}
+
+	return validations
+}
+
+// mtlsMode returns the mode of the mtls field of a PeerAuthentication, or "" when it is inherited
+func mtlsMode(pa kubernetes.IstioObject) string {
+	if mtls, ok := pa.GetSpec()["mtls"].(map[string]interface{}); ok {
+		if mode, ok := mtls["mode"].(string); ok && mode != "UNSET" {
+			return mode
+		}
+		// An mtls field without mode means PERMISSIVE
+		if _, found := mtls["mode"]; !found {
+			return "PERMISSIVE"
+		}
+	}
+	return ""
+}
+
+// portLevelMode returns the mode set for the port by the portLevelMtls field of a PeerAuthentication, or "" when it is not set
+func portLevelMode(pa kubernetes.IstioObject, port int) string {
+	portLevelMtls, ok := pa.GetSpec()["portLevelMtls"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	settings, ok := portLevelMtls[strconv.Itoa(port)].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if mode, ok := settings["mode"].(string); ok && mode != "UNSET" {
+		return mode
+	}
+	return ""
+}
+
+// tlsDisabledPortPaths returns the paths of the port-level settings of a DestinationRule, including the ones of its subsets,
+// disabling TLS on the port
+func tlsDisabledPortPaths(dr kubernetes.IstioObject, port int) []string {
+	paths := make([]string, 0)
+
+	policies := map[string]interface{}{"spec/trafficPolicy": dr.GetSpec()["trafficPolicy"]}
+	if subsets, ok := dr.GetSpec()["subsets"].([]interface{}); ok {
+		for i, s := range subsets {
+			if subset, ok := s.(map[string]interface{}); ok {
+				policies[fmt.Sprintf("spec/subsets[%d]/trafficPolicy", i)] = subset["trafficPolicy"]
+			}
+		}
+	}
+
+	for _, prefix := range sortedKeys(policies) {
+		policy, ok := policies[prefix].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		settings, ok := policy["portLevelSettings"].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, s := range settings {
+			setting, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			settingPort, ok := setting["port"].(map[string]interface{})
+			if !ok || fmt.Sprintf("%v", settingPort["number"]) != strconv.Itoa(port) {
+				continue
+			}
+			if tls, ok := setting["tls"].(map[string]interface{}); ok && tls["mode"] == "DISABLE" {
+				paths = append(paths, fmt.Sprintf("%s/portLevelSettings[%d]/tls/mode", prefix, i))
+			}
+		}
+	}
+
+	return paths
+}
+
+// destinationRuleAppliesToService checks whether the host of a DestinationRule refers to the Service
+func destinationRuleAppliesToService(dr kubernetes.IstioObject, svc core_v1.Service) bool {
+	host, ok := dr.GetSpec()["host"].(string)
+	if !ok {
+		return false
+	}
+	if kubernetes.HostWithinWildcardHost(fmt.Sprintf("%s.%s.%s", svc.Name, svc.Namespace, config.Get().ExternalServices.Istio.IstioIdentityDomain), host) {
+		return true
+	}
+	// Short names are resolved in the namespace of the DestinationRule
+	if host == svc.Name {
+		return dr.GetObjectMeta().Namespace == svc.Namespace
+	}
+	return kubernetes.FilterByHost(host, svc.Name, svc.Namespace)
+}
+
+// destinationRuleClients describes the clients using a DestinationRule. Istio looks for it in the namespace of the client,
+// then in the namespace of the service and then in the root namespace, the last two only when it is exported.
+func destinationRuleClients(dr kubernetes.IstioObject, svc core_v1.Service) string {
+	namespace := dr.GetObjectMeta().Namespace
+	exportedToAll := true
+	if exportTo, ok := dr.GetSpec()["exportTo"].([]interface{}); ok {
+		for _, e := range exportTo {
+			if e == "." {
+				exportedToAll = false
+			}
+		}
+	}
+	if exportedToAll && (namespace == svc.Namespace || namespace == config.Get().IstioNamespace) {
+		return "all clients"
+	}
+	return fmt.Sprintf("clients in %s", namespace)
+}
+
+func objectKey(objectType string, obj kubernetes.IstioObject) models.IstioValidationKey {
+	return models.BuildKey(objectType, obj.GetObjectMeta().Name, obj.GetObjectMeta().Namespace)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestPortStrictDestinationRuleDisabled(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pa := portMtlsPeerAuthn("reviews-pa", map[string]interface{}{"8080": data.CreateMTLS("STRICT")})
+	sameNsDr := data.AddTrafficPolicyToDestinationRule(disabledPortLevelPolicy(9080), data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews"))
+	clientDr := data.AddTrafficPolicyToDestinationRule(disabledPortLevelPolicy(9080), data.CreateEmptyDestinationRule("travel", "reviews", "reviews.bookinfo.svc.cluster.local"))
+	// Short host names refer to the namespace of the DestinationRule
+	otherDr := data.AddTrafficPolicyToDestinationRule(disabledPortLevelPolicy(9080), data.CreateEmptyDestinationRule("travel", "other", "reviews"))
+
+	validations := PortMtlsConflictChecker{
+		PeerAuthentications: []kubernetes.IstioObject{pa},
+		DestinationRules:    []kubernetes.IstioObject{sameNsDr, clientDr, otherDr},
+		Services:            fakeMtlsServices(),
+		WorkloadList:        fakeMtlsWorkloads(),
+	}.Check()
+
+	paKey := models.BuildKey("peerauthentication", "reviews-pa", "bookinfo")
+	assert.Len(validations, 3)
+	assert.False(validations[paKey].Valid)
+	assert.Len(validations[paKey].Checks, 2)
+	assert.Equal("spec/portLevelMtls/8080", validations[paKey].Checks[0].Path)
+	assert.Equal("KIA0509", validations[paKey].Checks[0].Code)
+	assert.Equal(models.CheckMessage("peerauthentications.mtls.portdestinationruledisabled"), validations[paKey].Checks[0].Message)
+	assert.Equal(models.CheckMessage("peerauthentications.mtls.portdestinationruledisabled"), validations[paKey].Checks[1].Message)
+	details := []string{validations[paKey].Checks[0].Detail, validations[paKey].Checks[1].Detail}
+	assert.Contains(details, "all clients -> reviews-v1.bookinfo:8080")
+	assert.Contains(details, "clients in travel -> reviews-v1.bookinfo:8080")
+	assert.ElementsMatch([]models.IstioValidationKey{models.BuildKey("destinationrule", "reviews", "bookinfo"), models.BuildKey("destinationrule", "reviews", "travel")}, validations[paKey].References)
+
+	drKey := models.BuildKey("destinationrule", "reviews", "travel")
+	assert.False(validations[drKey].Valid)
+	assert.Len(validations[drKey].Checks, 1)
+	assert.Equal(models.ErrorSeverity, validations[drKey].Checks[0].Severity)
+	assert.Equal("spec/trafficPolicy/portLevelSettings[0]/tls/mode", validations[drKey].Checks[0].Path)
+	assert.Equal(models.CheckMessage("destinationrules.mtls.portpolicystrict"), validations[drKey].Checks[0].Message)
+	assert.Equal("clients in travel -> reviews-v1.bookinfo:8080", validations[drKey].Checks[0].Detail)
+	assert.Equal([]models.IstioValidationKey{paKey}, validations[drKey].References)
+}
+
+func TestPortInheritedStrictMode(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	// The workload PeerAuthentication doesn't set the mode of the port, so the namespace-wide one applies
+	workloadPa := portMtlsPeerAuthn("reviews-pa", map[string]interface{}{"9999": data.CreateMTLS("DISABLE")})
+	workloadPa.GetSpec()["mtls"] = data.CreateMTLS("UNSET")
+	nsPa := data.CreateEmptyPeerAuthentication("default", "bookinfo", data.CreateMTLS("STRICT"))
+	dr := data.AddTrafficPolicyToDestinationRule(disabledPortLevelPolicy(9080), data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews"))
+
+	validations := PortMtlsConflictChecker{
+		PeerAuthentications: []kubernetes.IstioObject{workloadPa, nsPa},
+		DestinationRules:    []kubernetes.IstioObject{dr},
+		Services:            fakeMtlsServices(),
+		WorkloadList:        fakeMtlsWorkloads(),
+	}.Check()
+
+	nsKey := models.BuildKey("peerauthentication", "default", "bookinfo")
+	assert.Len(validations, 2)
+	assert.Len(validations[nsKey].Checks, 1)
+	assert.Equal("spec/mtls/mode", validations[nsKey].Checks[0].Path)
+}
+
+func TestPortPermissiveOrTLSEnabled(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	pa := portMtlsPeerAuthn("reviews-pa", map[string]interface{}{"8080": data.CreateMTLS("PERMISSIVE")})
+	dr := data.AddTrafficPolicyToDestinationRule(disabledPortLevelPolicy(9080), data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews"))
+	strictPa := portMtlsPeerAuthn("reviews-pa", map[string]interface{}{"8080": data.CreateMTLS("STRICT")})
+	tlsDr := data.AddTrafficPolicyToDestinationRule(data.CreateTLSPortLevelTrafficPolicyForDestinationRules(), data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews"))
+
+	assert.Empty(PortMtlsConflictChecker{
+		PeerAuthentications: []kubernetes.IstioObject{pa},
+		DestinationRules:    []kubernetes.IstioObject{dr},
+		Services:            fakeMtlsServices(),
+		WorkloadList:        fakeMtlsWorkloads(),
+	}.Check())
+
+	assert.Empty(PortMtlsConflictChecker{
+		PeerAuthentications: []kubernetes.IstioObject{strictPa},
+		DestinationRules:    []kubernetes.IstioObject{tlsDr},
+		Services:            fakeMtlsServices(),
+		WorkloadList:        fakeMtlsWorkloads(),
+	}.Check())
+}
+
+func TestPortConflictingModes(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	strictPa := portMtlsPeerAuthn("strict-pa", map[string]interface{}{"8080": data.CreateMTLS("STRICT")})
+	disabledPa := data.AddSelectorToPeerAuthn(map[string]interface{}{"matchLabels": map[string]interface{}{"version": "v1"}},
+		data.CreateEmptyPeerAuthentication("disabled-pa", "bookinfo", data.CreateMTLS("DISABLE")))
+
+	validations := PortMtlsConflictChecker{
+		PeerAuthentications: []kubernetes.IstioObject{strictPa, disabledPa},
+		Services:            fakeMtlsServices(),
+		WorkloadList:        fakeMtlsWorkloads(),
+	}.Check()
+
+	strictKey := models.BuildKey("peerauthentication", "strict-pa", "bookinfo")
+	disabledKey := models.BuildKey("peerauthentication", "disabled-pa", "bookinfo")
+	assert.Len(validations, 2)
+	assert.True(validations[strictKey].Valid)
+	assert.Equal("spec/portLevelMtls/8080", validations[strictKey].Checks[0].Path)
+	assert.Equal(models.CheckMessage("peerauthentications.mtls.portconflict"), validations[strictKey].Checks[0].Message)
+	assert.Equal([]models.IstioValidationKey{disabledKey}, validations[strictKey].References)
+	assert.Equal("spec/mtls/mode", validations[disabledKey].Checks[0].Path)
+	assert.Equal([]models.IstioValidationKey{strictKey}, validations[disabledKey].References)
+}
+
+func portMtlsPeerAuthn(name string, portLevelMtls map[string]interface{}) kubernetes.IstioObject {
+	pa := data.CreateEmptyPeerAuthenticationWithSelector(name, "bookinfo", data.CreateOneLabelSelector("reviews"))
+	pa.GetSpec()["portLevelMtls"] = portLevelMtls
+	return pa
+}
+
+func disabledPortLevelPolicy(port int) map[string]interface{} {
+	return map[string]interface{}{
+		"portLevelSettings": []interface{}{
+			map[string]interface{}{
+				"port": map[string]interface{}{"number": port},
+				"tls":  map[string]interface{}{"mode": "DISABLE"},
+			},
+		},
+	}
+}
+
+func fakeMtlsWorkloads() models.WorkloadList {
+	return data.CreateWorkloadList("bookinfo",
+		data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+	)
+}
+
+func fakeMtlsServices() []core_v1.Service {
+	return []core_v1.Service{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+			Spec: core_v1.ServiceSpec{
+				Selector: map[string]string{"app": "reviews"},
+				Ports:    []core_v1.ServicePort{{Name: "http", Port: 9080, TargetPort: intstr.FromInt(8080)}},
+			},
+		},
+	}
+}
@@ -12,6 +12,8 @@ type GatewayChecker struct {
 	GatewaysPerNamespace  [][]kubernetes.IstioObject
 	Namespace             string
 	WorkloadsPerNamespace map[string]models.WorkloadList
+	// Certificates of the Secrets referenced by the gateways credentialName, indexed by namespace/name
+	Certificates map[string]*gateways.SecretCertificate
 }
 
 // Check runs checks for the all namespaces actions as well as for the single namespace validations
@@ -41,6 +43,11 @@ func (g GatewayChecker) runSingleChecks(gw kubernetes.IstioObject) models.IstioV
 			Gateway:               gw,
 			WorkloadsPerNamespace: g.WorkloadsPerNamespace,
 		},
+		gateways.TLSChecker{
+			Gateway:               gw,
+			WorkloadsPerNamespace: g.WorkloadsPerNamespace,
+			Certificates:          g.Certificates,
+		},
 	}
 
 	for _, checker := range enabledCheckers {
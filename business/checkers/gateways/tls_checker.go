@@ -0,0 +1,230 @@
+package gateways
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+type TLSChecker struct {
+	Gateway               kubernetes.IstioObject
+	WorkloadsPerNamespace map[string]models.WorkloadList
+	// Certificates of the Secrets referenced by credentialName, indexed by namespace/name. A nil certificate means
+	// the Secret doesn't exist, while Secrets not present in the map couldn't be read and are not validated.
+	Certificates map[string]*SecretCertificate
+}
+
+// SecretCertificate holds the leaf certificate data of a Secret used by the checks, so no other Secret data
+// (i.e. the private key) needs to be kept.
+type SecretCertificate struct {
+	// Valid is false when the Secret doesn't hold a parseable certificate
+	Valid    bool
+	NotAfter time.Time
+	DNSNames []string
+}
+
+// NewSecretCertificate extracts the leaf certificate data of a TLS Secret or a generic Secret with a "cert" key
+func NewSecretCertificate(secret *core_v1.Secret) *SecretCertificate {
+	cert := parseCertificate(secret)
+	if cert == nil {
+		return &SecretCertificate{}
+	}
+	return &SecretCertificate{Valid: true, NotAfter: cert.NotAfter, DNSNames: cert.DNSNames}
+}
+
+// Check validates the TLS settings of the gateway servers: HTTPS servers must define them and
+// the certificates referenced by credentialName must exist, be valid and cover the server hosts.
+func (t TLSChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	servers, ok := t.Gateway.GetSpec()["servers"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	namespaces := gatewayWorkloadNamespaces(t.Gateway, t.WorkloadsPerNamespace)
+
+	for serverIdx, s := range servers {
+		server, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		tls, hasTLS := server["tls"].(map[string]interface{})
+		if !hasTLS {
+			if port, ok := server["port"].(map[string]interface{}); ok && strings.ToUpper(fmt.Sprintf("%v", port["protocol"])) == "HTTPS" {
+				valid = false
+				check := models.Build("gateways.tls.missing", fmt.Sprintf("spec/servers[%d]", serverIdx))
+				checks = append(checks, &check)
+			}
+			continue
+		}
+
+		credentialName := serverCredentialName(tls)
+		if credentialName == "" {
+			continue
+		}
+
+		credentialPath := fmt.Sprintf("spec/servers[%d]/tls/credentialName", serverIdx)
+		for _, ns := range namespaces {
+			cert, found := t.Certificates[ns+"/"+credentialName]
+			if !found {
+				continue
+			}
+			if cert == nil {
+				valid = false
+				check := models.Build("gateways.tls.secretnotfound", credentialPath)
+				checks = append(checks, &check)
+				continue
+			}
+
+			if !cert.Valid {
+				valid = false
+				check := models.Build("gateways.tls.invalidcertificate", credentialPath)
+				checks = append(checks, &check)
+				continue
+			}
+
+			now := util.Clock.Now()
+			warningPeriod := time.Duration(config.Get().KialiFeatureFlags.Validations.CertificateExpirationWarning) * 24 * time.Hour
+			if now.After(cert.NotAfter) {
+				valid = false
+				check := models.Build("gateways.tls.certificateexpired", credentialPath)
+				checks = append(checks, &check)
+			} else if now.Add(warningPeriod).After(cert.NotAfter) {
+				check := models.Build("gateways.tls.certificateexpiring", credentialPath)
+				checks = append(checks, &check)
+			}
+
+			hosts, ok := server["hosts"].([]interface{})
+			if !ok {
+				continue
+			}
+			for hostIdx, h := range hosts {
+				host, ok := h.(string)
+				if !ok || certificateCoversHost(cert, host) {
+					continue
+				}
+				check := models.Build("gateways.tls.sanmismatch", fmt.Sprintf("spec/servers[%d]/hosts[%d]", serverIdx, hostIdx))
+				checks = append(checks, &check)
+			}
+		}
+	}
+
+	return checks, valid
+}
+
+// CredentialSecrets returns the namespace/name keys of the Secrets referenced by the TLS servers of the gateway.
+// Secrets are read by the gateway workloads, so they are looked up in the namespaces of the selected workloads.
+func CredentialSecrets(gw kubernetes.IstioObject, workloadsPerNamespace map[string]models.WorkloadList) []string {
+	secrets := make([]string, 0)
+
+	servers, ok := gw.GetSpec()["servers"].([]interface{})
+	if !ok {
+		return secrets
+	}
+
+	namespaces := gatewayWorkloadNamespaces(gw, workloadsPerNamespace)
+	for _, s := range servers {
+		server, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		tls, ok := server["tls"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if credentialName := serverCredentialName(tls); credentialName != "" {
+			for _, ns := range namespaces {
+				secrets = append(secrets, ns+"/"+credentialName)
+			}
+		}
+	}
+
+	return secrets
+}
+
+// serverCredentialName returns the credentialName of the SIMPLE and MUTUAL TLS modes, the only ones terminating TLS with it
+func serverCredentialName(tls map[string]interface{}) string {
+	mode, _ := tls["mode"].(string)
+	if mode != "" && mode != "SIMPLE" && mode != "MUTUAL" {
+		return ""
+	}
+	credentialName, _ := tls["credentialName"].(string)
+	return credentialName
+}
+
+func gatewayWorkloadNamespaces(gw kubernetes.IstioObject, workloadsPerNamespace map[string]models.WorkloadList) []string {
+	namespaces := make([]string, 0)
+
+	selectors, ok := gw.GetSpec()["selector"].(map[string]interface{})
+	if !ok {
+		return namespaces
+	}
+	labelSelectors := make(map[string]string, len(selectors))
+	for k, v := range selectors {
+		labelSelectors[k] = fmt.Sprintf("%v", v)
+	}
+	selector := labels.SelectorFromSet(labelSelectors)
+
+	for ns, wls := range workloadsPerNamespace {
+		for _, wl := range wls.Workloads {
+			if selector.Matches(labels.Set(wl.Labels)) {
+				namespaces = append(namespaces, ns)
+				break
+			}
+		}
+	}
+	sort.Strings(namespaces)
+
+	return namespaces
+}
+
+// parseCertificate returns the leaf certificate of a TLS Secret or a generic Secret with a "cert" key
+func parseCertificate(secret *core_v1.Secret) *x509.Certificate {
+	data, found := secret.Data[core_v1.TLSCertKey]
+	if !found {
+		data = secret.Data["cert"]
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return cert
+}
+
+// certificateCoversHost checks whether the certificate is valid for a server host, in [namespace/]dnsName format.
+// Wildcard hosts are only covered by the same wildcard SAN.
+func certificateCoversHost(cert *SecretCertificate, host string) bool {
+	if parts := strings.Split(host, "/"); len(parts) == 2 {
+		host = parts[1]
+	}
+	if host == "*" {
+		return true
+	}
+	if strings.HasPrefix(host, "*") {
+		for _, san := range cert.DNSNames {
+			if san == host {
+				return true
+			}
+		}
+		return false
+	}
+	return (&x509.Certificate{DNSNames: cert.DNSNames}).VerifyHostname(host) == nil
+}
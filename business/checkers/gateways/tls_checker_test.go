@@ -0,0 +1,209 @@
+package gateways
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/util"
+)
+
+func TestValidCredential(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.RealClock{}
+
+	checks, valid := TLSChecker{
+		Gateway:               tlsGateway("SIMPLE", "bookinfo-cert", "bookinfo.example.com", "*.reviews.example.com"),
+		WorkloadsPerNamespace: gatewayWorkloads(),
+		Certificates: map[string]*SecretCertificate{
+			"istio-system/bookinfo-cert": NewSecretCertificate(tlsSecret(t, 365*24*time.Hour, "bookinfo.example.com", "*.reviews.example.com")),
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestCredentialNotFound(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.RealClock{}
+
+	checks, valid := TLSChecker{
+		Gateway:               tlsGateway("MUTUAL", "bookinfo-cert", "bookinfo.example.com"),
+		WorkloadsPerNamespace: gatewayWorkloads(),
+		Certificates:          map[string]*SecretCertificate{"istio-system/bookinfo-cert": nil},
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("gateways.tls.secretnotfound"), checks[0].Message)
+	assert.Equal(models.ErrorSeverity, checks[0].Severity)
+	assert.Equal("spec/servers[0]/tls/credentialName", checks[0].Path)
+
+	// Secrets that couldn't be read are not validated
+	checks, valid = TLSChecker{
+		Gateway:               tlsGateway("MUTUAL", "bookinfo-cert", "bookinfo.example.com"),
+		WorkloadsPerNamespace: gatewayWorkloads(),
+		Certificates:          map[string]*SecretCertificate{},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestInvalidCertificate(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.RealClock{}
+
+	checks, valid := TLSChecker{
+		Gateway:               tlsGateway("SIMPLE", "bookinfo-cert", "bookinfo.example.com"),
+		WorkloadsPerNamespace: gatewayWorkloads(),
+		Certificates: map[string]*SecretCertificate{
+			"istio-system/bookinfo-cert": NewSecretCertificate(&core_v1.Secret{Data: map[string][]byte{core_v1.TLSCertKey: []byte("not a certificate")}}),
+		},
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("gateways.tls.invalidcertificate"), checks[0].Message)
+}
+
+func TestCertificateExpiration(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.RealClock{}
+
+	checks, valid := TLSChecker{
+		Gateway:               tlsGateway("SIMPLE", "bookinfo-cert", "bookinfo.example.com"),
+		WorkloadsPerNamespace: gatewayWorkloads(),
+		Certificates: map[string]*SecretCertificate{
+			"istio-system/bookinfo-cert": NewSecretCertificate(tlsSecret(t, 10*24*time.Hour, "bookinfo.example.com")),
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("gateways.tls.certificateexpiring"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+
+	checks, valid = TLSChecker{
+		Gateway:               tlsGateway("SIMPLE", "bookinfo-cert", "bookinfo.example.com"),
+		WorkloadsPerNamespace: gatewayWorkloads(),
+		Certificates: map[string]*SecretCertificate{
+			"istio-system/bookinfo-cert": NewSecretCertificate(tlsSecret(t, -time.Hour, "bookinfo.example.com")),
+		},
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("gateways.tls.certificateexpired"), checks[0].Message)
+	assert.Equal(models.ErrorSeverity, checks[0].Severity)
+}
+
+func TestSANMismatch(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.RealClock{}
+
+	checks, valid := TLSChecker{
+		Gateway:               tlsGateway("SIMPLE", "bookinfo-cert", "bookinfo/bookinfo.example.com", "ratings.example.com", "*.example.com"),
+		WorkloadsPerNamespace: gatewayWorkloads(),
+		Certificates: map[string]*SecretCertificate{
+			"istio-system/bookinfo-cert": NewSecretCertificate(tlsSecret(t, 365*24*time.Hour, "bookinfo.example.com")),
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(checks, 2)
+	assert.Equal(models.CheckMessage("gateways.tls.sanmismatch"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/servers[0]/hosts[1]", checks[0].Path)
+	assert.Equal("spec/servers[0]/hosts[2]", checks[1].Path)
+}
+
+func TestHTTPSWithoutTLS(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	gw := data.AddServerToGateway(data.CreateServer([]string{"bookinfo.example.com"}, 443, "https", "HTTPS"),
+		data.AddServerToGateway(data.CreateServer([]string{"bookinfo.example.com"}, 80, "http", "HTTP"),
+			data.CreateEmptyGateway("bookinfo-gateway", "bookinfo", map[string]string{"istio": "ingressgateway"})))
+
+	checks, valid := TLSChecker{
+		Gateway:               gw,
+		WorkloadsPerNamespace: gatewayWorkloads(),
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("gateways.tls.missing"), checks[0].Message)
+	assert.Equal("spec/servers[1]", checks[0].Path)
+}
+
+func TestCredentialSecrets(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	assert.Equal([]string{"istio-system/bookinfo-cert"}, CredentialSecrets(tlsGateway("SIMPLE", "bookinfo-cert", "bookinfo.example.com"), gatewayWorkloads()))
+	assert.Empty(CredentialSecrets(tlsGateway("PASSTHROUGH", "bookinfo-cert", "bookinfo.example.com"), gatewayWorkloads()))
+	assert.Empty(CredentialSecrets(tlsGateway("SIMPLE", "bookinfo-cert", "bookinfo.example.com"), map[string]models.WorkloadList{}))
+}
+
+func tlsGateway(mode, credentialName string, hosts ...string) kubernetes.IstioObject {
+	server := data.CreateServer(hosts, 443, "https", "HTTPS")
+	server["tls"] = map[string]interface{}{
+		"mode":           mode,
+		"credentialName": credentialName,
+	}
+	return data.AddServerToGateway(server,
+		data.CreateEmptyGateway("bookinfo-gateway", "bookinfo", map[string]string{"istio": "ingressgateway"}))
+}
+
+func gatewayWorkloads() map[string]models.WorkloadList {
+	return map[string]models.WorkloadList{
+		"istio-system": data.CreateWorkloadList("istio-system",
+			data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"istio": "ingressgateway"})),
+		"bookinfo": data.CreateWorkloadList("bookinfo",
+			data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage"})),
+	}
+}
+
+func tlsSecret(t *testing.T, validFor time.Duration, dnsNames ...string) *core_v1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: dnsNames[0]},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-24 * time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &core_v1.Secret{
+		Type: core_v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			core_v1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		},
+	}
+}
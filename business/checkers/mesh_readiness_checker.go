@@ -0,0 +1,79 @@
+package checkers
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/services"
+	"github.com/kiali/kiali/business/checkers/workloads"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const WorkloadCheckerType = "workload"
+
+// MeshReadinessChecker validates that the Services and workloads of a namespace are ready to be part of the mesh.
+// Only the objects with findings are reported, so namespaces out of the mesh don't get an entry per object.
+type MeshReadinessChecker struct {
+	Namespace       models.Namespace
+	Services        []core_v1.Service
+	WorkloadList    models.WorkloadList
+	Pods            []core_v1.Pod
+	VirtualServices []kubernetes.IstioObject
+}
+
+func (m MeshReadinessChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for _, svc := range m.Services {
+		validations.MergeValidations(runIfFound(svc.Name, m.Namespace.Name, ServiceCheckerType, []Checker{
+			services.ProtocolChecker{Namespace: m.Namespace, Service: svc, Workloads: m.selectedWorkloads(svc)},
+			services.HeadlessChecker{Service: svc, VirtualServices: m.VirtualServices},
+		}))
+	}
+
+	for _, wk := range m.WorkloadList.Workloads {
+		pods := []core_v1.Pod{}
+		if len(wk.Labels) > 0 {
+			pods = kubernetes.FilterPodsForSelector(labels.SelectorFromSet(wk.Labels), m.Pods)
+		}
+		validations.MergeValidations(runIfFound(wk.Name, m.Namespace.Name, WorkloadCheckerType, []Checker{
+			workloads.LabelsChecker{Workload: wk},
+			workloads.PodsChecker{Namespace: m.Namespace, Workload: wk, Pods: pods},
+			workloads.InjectionChecker{Namespace: m.Namespace, Workload: wk, Pods: pods},
+		}))
+	}
+
+	return validations
+}
+
+// selectedWorkloads returns the workloads selected by a Service
+func (m MeshReadinessChecker) selectedWorkloads(svc core_v1.Service) []models.WorkloadListItem {
+	selected := []models.WorkloadListItem{}
+	if len(svc.Spec.Selector) == 0 {
+		return selected
+	}
+	selector := labels.SelectorFromSet(svc.Spec.Selector)
+	for _, wk := range m.WorkloadList.Workloads {
+		if selector.Matches(labels.Set(wk.Labels)) {
+			selected = append(selected, wk)
+		}
+	}
+	return selected
+}
+
+// runIfFound runs the checkers of an object, returning its validation only when some check is found
+func runIfFound(name, namespace, objectType string, enabledCheckers []Checker) models.IstioValidations {
+	key, validation := EmptyValidValidation(name, namespace, objectType)
+
+	for _, checker := range enabledCheckers {
+		checks, validChecker := checker.Check()
+		validation.Checks = append(validation.Checks, checks...)
+		validation.Valid = validation.Valid && validChecker
+	}
+
+	if len(validation.Checks) == 0 {
+		return models.IstioValidations{}
+	}
+	return models.IstioValidations{key: validation}
+}
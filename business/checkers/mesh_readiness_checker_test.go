@@ -0,0 +1,78 @@
+package checkers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestMeshReadiness(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	disabled := false
+	validations := MeshReadinessChecker{
+		Namespace: models.Namespace{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled"}},
+		Services: []core_v1.Service{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"},
+				Spec:       core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "http", Port: 9080}}},
+			},
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "dns", Namespace: "bookinfo"},
+				Spec:       core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "http-dns", Port: 53, Protocol: core_v1.ProtocolUDP}}},
+			},
+		},
+		WorkloadList: models.WorkloadList{
+			Namespace: models.Namespace{Name: "bookinfo"},
+			Workloads: []models.WorkloadListItem{
+				{Name: "reviews-v1", Labels: map[string]string{"app": "reviews", "version": "v1"}, IstioSidecar: true, AppLabel: true, VersionLabel: true},
+				{Name: "legacy", Labels: map[string]string{"app": "legacy"}, IstioInjectionAnnotation: &disabled, AppLabel: true},
+			},
+		},
+	}.Check()
+
+	// Objects without findings are not reported
+	assert.Len(validations, 2)
+
+	dns := validations[models.BuildKey(ServiceCheckerType, "dns", "bookinfo")]
+	assert.NotNil(dns)
+	assert.True(dns.Valid)
+	assert.Len(dns.Checks, 1)
+	assert.Equal("KIA0703", dns.Checks[0].Code)
+
+	legacy := validations[models.BuildKey(WorkloadCheckerType, "legacy", "bookinfo")]
+	assert.NotNil(legacy)
+	assert.True(legacy.Valid)
+	assert.Len(legacy.Checks, 1)
+	assert.Equal("KIA1204", legacy.Checks[0].Code)
+
+	// Out of the mesh, nothing is reported
+	validations = MeshReadinessChecker{
+		Namespace: models.Namespace{Name: "default"},
+		Services: []core_v1.Service{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "dns", Namespace: "default"},
+				Spec:       core_v1.ServiceSpec{Ports: []core_v1.ServicePort{{Name: "http-dns", Port: 53, Protocol: core_v1.ProtocolUDP}}},
+			},
+		},
+		WorkloadList: models.WorkloadList{
+			Namespace: models.Namespace{Name: "default"},
+			Workloads: []models.WorkloadListItem{{Name: "node-agent", Labels: map[string]string{"app": "node-agent"}}},
+		},
+		Pods: []core_v1.Pod{
+			{
+				ObjectMeta: meta_v1.ObjectMeta{Name: "node-agent-1", Labels: map[string]string{"app": "node-agent"}},
+				Spec:       core_v1.PodSpec{HostNetwork: true},
+			},
+		},
+	}.Check()
+	assert.Empty(validations)
+}
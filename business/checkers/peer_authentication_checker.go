@@ -1,6 +1,8 @@
 package checkers
 
 import (
+	core_v1 "k8s.io/api/core/v1"
+
 	"github.com/kiali/kiali/business/checkers/common"
 	"github.com/kiali/kiali/business/checkers/peerauthentications"
 	"github.com/kiali/kiali/config"
@@ -14,6 +16,7 @@ type PeerAuthenticationChecker struct {
 	PeerAuthentications []kubernetes.IstioObject
 	MTLSDetails         kubernetes.MTLSDetails
 	WorkloadList        models.WorkloadList
+	Services            []core_v1.Service
 }
 
 func (m PeerAuthenticationChecker) Check() models.IstioValidations {
@@ -36,6 +39,7 @@ func (m PeerAuthenticationChecker) runChecks(peerAuthn kubernetes.IstioObject) m
 	var enabledCheckers []Checker
 
 	enabledCheckers = append(enabledCheckers, common.SelectorNoWorkloadFoundChecker(PeerAuthenticationCheckerType, peerAuthn, m.WorkloadList))
+	enabledCheckers = append(enabledCheckers, peerauthentications.PortMtlsChecker{PeerAuthn: peerAuthn, Services: m.Services, WorkloadList: m.WorkloadList})
 	if peerAuthn.GetObjectMeta().Namespace == config.Get().IstioNamespace {
 		enabledCheckers = append(enabledCheckers, peerauthentications.DisabledMeshWideChecker{PeerAuthn: peerAuthn, DestinationRules: m.MTLSDetails.DestinationRules})
 	} else {
@@ -0,0 +1,91 @@
+package peerauthentications
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type PortMtlsChecker struct {
+	PeerAuthn    kubernetes.IstioObject
+	Services     []core_v1.Service
+	WorkloadList models.WorkloadList
+}
+
+// Check validates that the ports of portLevelMtls are exposed by the selected workloads.
+// Port-level mTLS is only supported on PeerAuthentications selecting workloads.
+func (c PortMtlsChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	portLevelMtls, ok := c.PeerAuthn.GetSpec()["portLevelMtls"].(map[string]interface{})
+	if !ok || len(portLevelMtls) == 0 {
+		return checks, valid
+	}
+
+	if !c.PeerAuthn.HasMatchLabelsSelector() {
+		check := models.Build("peerauthentications.mtls.portlevelnoselector", "spec/portLevelMtls")
+		return append(checks, &check), false
+	}
+
+	ports, found := c.selectedPorts()
+	if !found || ports == nil {
+		// Missing workloads are already reported by the selector checker, and named target ports can't be checked
+		return checks, valid
+	}
+
+	portKeys := make([]string, 0, len(portLevelMtls))
+	for port := range portLevelMtls {
+		portKeys = append(portKeys, port)
+	}
+	sort.Strings(portKeys)
+
+	for _, port := range portKeys {
+		if number, err := strconv.Atoi(port); err == nil && ports[number] {
+			continue
+		}
+		check := models.Build("peerauthentications.mtls.portnotfound", fmt.Sprintf("spec/portLevelMtls/%s", port))
+		checks = append(checks, &check)
+	}
+
+	return checks, valid
+}
+
+// selectedPorts returns the target ports of the Services exposing the workloads selected by the PeerAuthentication,
+// and whether any workload is selected. Ports are nil when a Service uses a named target port, which can't be resolved.
+func (c PortMtlsChecker) selectedPorts() (map[int]bool, bool) {
+	ports := map[int]bool{}
+	found := false
+
+	selector := labels.SelectorFromSet(common.GetSelectorLabels(c.PeerAuthn))
+	for _, wk := range c.WorkloadList.Workloads {
+		wkLabels := labels.Set(wk.Labels)
+		if !selector.Matches(wkLabels) {
+			continue
+		}
+		found = true
+		for _, svc := range c.Services {
+			if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(wkLabels) {
+				continue
+			}
+			for _, port := range svc.Spec.Ports {
+				if port.TargetPort.StrVal != "" {
+					return nil, found
+				}
+				if port.TargetPort.IntValue() > 0 {
+					ports[port.TargetPort.IntValue()] = true
+				} else {
+					ports[int(port.Port)] = true
+				}
+			}
+		}
+	}
+
+	return ports, found
+}
@@ -0,0 +1,106 @@
+package peerauthentications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestPortLevelMtlsPortsFound(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := PortMtlsChecker{
+		PeerAuthn:    portLevelPeerAuthn(map[string]interface{}{"app": "details"}, "8080"),
+		Services:     fakePortMtlsServices(),
+		WorkloadList: fakePortMtlsWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestPortLevelMtlsPortNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	// 9080 is the Service port, but PeerAuthentications refer to the workload port
+	validations, valid := PortMtlsChecker{
+		PeerAuthn:    portLevelPeerAuthn(map[string]interface{}{"app": "details"}, "8080", "9080"),
+		Services:     fakePortMtlsServices(),
+		WorkloadList: fakePortMtlsWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("peerauthentications.mtls.portnotfound"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/portLevelMtls/9080", validations[0].Path)
+}
+
+func TestPortLevelMtlsWithoutSelector(t *testing.T) {
+	assert := assert.New(t)
+
+	validations, valid := PortMtlsChecker{
+		PeerAuthn:    portLevelPeerAuthn(nil, "8080"),
+		Services:     fakePortMtlsServices(),
+		WorkloadList: fakePortMtlsWorkloads(),
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("peerauthentications.mtls.portlevelnoselector"), validations[0].Message)
+	assert.Equal("spec/portLevelMtls", validations[0].Path)
+}
+
+func TestPortLevelMtlsNamedTargetPort(t *testing.T) {
+	assert := assert.New(t)
+
+	services := fakePortMtlsServices()
+	services[0].Spec.Ports[0].TargetPort = intstr.FromString("http")
+
+	validations, valid := PortMtlsChecker{
+		PeerAuthn:    portLevelPeerAuthn(map[string]interface{}{"app": "details"}, "9999"),
+		Services:     services,
+		WorkloadList: fakePortMtlsWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func portLevelPeerAuthn(selector map[string]interface{}, ports ...string) kubernetes.IstioObject {
+	pa := data.CreateEmptyPeerAuthentication("details-pa", "bookinfo", data.CreateMTLS("PERMISSIVE"))
+	if selector != nil {
+		pa = data.AddSelectorToPeerAuthn(map[string]interface{}{"matchLabels": selector}, pa)
+	}
+	portLevelMtls := map[string]interface{}{}
+	for _, port := range ports {
+		portLevelMtls[port] = data.CreateMTLS("STRICT")
+	}
+	pa.GetSpec()["portLevelMtls"] = portLevelMtls
+	return pa
+}
+
+func fakePortMtlsWorkloads() models.WorkloadList {
+	return data.CreateWorkloadList("bookinfo",
+		data.CreateWorkloadListItem("details-v1", map[string]string{"app": "details", "version": "v1"}),
+	)
+}
+
+func fakePortMtlsServices() []core_v1.Service {
+	return []core_v1.Service{
+		{
+			ObjectMeta: meta_v1.ObjectMeta{Name: "details", Namespace: "bookinfo"},
+			Spec: core_v1.ServiceSpec{
+				Selector: map[string]string{"app": "details"},
+				Ports:    []core_v1.ServicePort{{Name: "http", Port: 9080, TargetPort: intstr.FromInt(8080)}},
+			},
+		},
+	}
+}
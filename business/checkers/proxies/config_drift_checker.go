@@ -0,0 +1,208 @@
+package proxies
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const (
+	workloadType            = "workload"
+	virtualServiceType      = "virtualservice"
+	destinationRuleType     = "destinationrule"
+	authorizationPolicyType = "authorizationpolicy"
+)
+
+// ConfigDriftChecker compares the Istio objects that should be applied to a workload
+// with the configuration actually received by one of its proxies.
+// Objects that are missing from the proxy config were likely rejected by istiod.
+type ConfigDriftChecker struct {
+	Namespace             string
+	Namespaces            models.NamespaceNames
+	Workload              models.WorkloadListItem
+	ConfigDump            *kubernetes.ConfigDump
+	VirtualServices       []kubernetes.IstioObject
+	DestinationRules      []kubernetes.IstioObject
+	AuthorizationPolicies []kubernetes.IstioObject
+}
+
+func (c ConfigDriftChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+	if c.ConfigDump == nil {
+		return validations
+	}
+
+	clusters := c.ConfigDump.GetClusterNames()
+	configRefs := c.ConfigDump.GetIstioConfigReferences()
+	rbacPolicies := c.ConfigDump.GetRBACPolicyNames()
+
+	wkKey := models.BuildKey(workloadType, c.Workload.Name, c.Namespace)
+	wkValidation := &models.IstioValidation{
+		Name:       c.Workload.Name,
+		ObjectType: workloadType,
+		Valid:      true,
+		Checks:     []*models.IstioCheck{},
+		References: []models.IstioValidationKey{},
+	}
+
+	for _, vs := range c.VirtualServices {
+		if !c.isVirtualServiceExpected(vs, clusters) {
+			continue
+		}
+		// Istio may report any of the served API versions (v1alpha3, v1beta1), so only the suffix is compared
+		ref := fmt.Sprintf("/namespaces/%s/virtual-service/%s", vs.GetObjectMeta().Namespace, vs.GetObjectMeta().Name)
+		if !hasSuffix(configRefs, ref) {
+			c.addDrift(validations, wkValidation, vs, virtualServiceType, "virtualservices.proxy.notapplied", "spec/http")
+		}
+	}
+
+	for _, dr := range c.DestinationRules {
+		for _, path := range c.missingSubsets(dr, clusters) {
+			c.addDrift(validations, wkValidation, dr, destinationRuleType, "destinationrules.proxy.subsetnotapplied", path)
+		}
+	}
+
+	for _, ap := range c.AuthorizationPolicies {
+		if !c.isAuthorizationPolicyExpected(ap) {
+			continue
+		}
+		prefix := fmt.Sprintf("ns[%s]-policy[%s]-rule[", ap.GetObjectMeta().Namespace, ap.GetObjectMeta().Name)
+		if !hasPrefix(rbacPolicies, prefix) {
+			c.addDrift(validations, wkValidation, ap, authorizationPolicyType, "authorizationpolicy.proxy.notapplied", "spec/rules")
+		}
+	}
+
+	validations.MergeValidations(models.IstioValidations{wkKey: wkValidation})
+	return validations
+}
+
+// addDrift marks both the Istio object and the workload as drifted, referencing each other
+func (c ConfigDriftChecker) addDrift(validations models.IstioValidations, wkValidation *models.IstioValidation, obj kubernetes.IstioObject, objectType, checkId, path string) {
+	objKey := models.BuildKey(objectType, obj.GetObjectMeta().Name, obj.GetObjectMeta().Namespace)
+
+	check := models.Build(checkId, path)
+	validations.MergeValidations(models.IstioValidations{objKey: &models.IstioValidation{
+		Name:       objKey.Name,
+		ObjectType: objectType,
+		Valid:      check.Severity != models.ErrorSeverity,
+		Checks:     []*models.IstioCheck{&check},
+		References: []models.IstioValidationKey{models.BuildKey(workloadType, c.Workload.Name, c.Namespace)},
+	}})
+
+	wkCheck := models.Build("workload.proxy.configdrift", fmt.Sprintf("%s/%s", objectType, objKey.Name))
+	wkValidation.Checks = append(wkValidation.Checks, &wkCheck)
+	wkValidation.References = append(wkValidation.References, objKey)
+}
+
+// isVirtualServiceExpected checks whether the VirtualService should be part of the sidecar routes:
+// it has to be bound to the mesh, define http routes and target a host known by the proxy.
+func (c ConfigDriftChecker) isVirtualServiceExpected(vs kubernetes.IstioObject, clusters map[string]bool) bool {
+	if _, ok := vs.GetSpec()["http"]; !ok {
+		return false
+	}
+
+	if gateways, ok := vs.GetSpec()["gateways"].([]interface{}); ok && len(gateways) > 0 {
+		meshBound := false
+		for _, g := range gateways {
+			if gw, ok := g.(string); ok && gw == "mesh" {
+				meshBound = true
+			}
+		}
+		if !meshBound {
+			return false
+		}
+	}
+
+	hosts, ok := vs.GetSpec()["hosts"].([]interface{})
+	if !ok {
+		return false
+	}
+	for _, h := range hosts {
+		if host, ok := h.(string); ok {
+			fqdn := kubernetes.GetHost(host, vs.GetObjectMeta().Namespace, vs.GetObjectMeta().ClusterName, c.Namespaces).String()
+			if hasSuffix(clusters, "|"+fqdn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// missingSubsets returns the paths of the subsets whose clusters are not present in the proxy.
+// Hosts not known by the proxy (i.e. filtered out by a Sidecar resource) are ignored.
+func (c ConfigDriftChecker) missingSubsets(dr kubernetes.IstioObject, clusters map[string]bool) []string {
+	paths := make([]string, 0)
+	host, ok := dr.GetSpec()["host"].(string)
+	if !ok {
+		return paths
+	}
+
+	fqdn := kubernetes.GetHost(host, dr.GetObjectMeta().Namespace, dr.GetObjectMeta().ClusterName, c.Namespaces).String()
+	if !hasSuffix(clusters, "|"+fqdn) {
+		return paths
+	}
+
+	subsets, ok := dr.GetSpec()["subsets"].([]interface{})
+	if !ok {
+		return paths
+	}
+	for i, s := range subsets {
+		subset, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if name, ok := subset["name"].(string); ok {
+			if !hasSuffix(clusters, fmt.Sprintf("|%s|%s", name, fqdn)) {
+				paths = append(paths, fmt.Sprintf("spec/subsets[%d]", i))
+			}
+		}
+	}
+	return paths
+}
+
+// isAuthorizationPolicyExpected checks whether the policy applies to the workload and generates RBAC rules.
+// ALLOW policies without rules deny everything and don't generate any named policy.
+func (c ConfigDriftChecker) isAuthorizationPolicyExpected(ap kubernetes.IstioObject) bool {
+	apNamespace := ap.GetObjectMeta().Namespace
+	if apNamespace != c.Namespace && apNamespace != config.Get().IstioNamespace {
+		return false
+	}
+
+	if action, ok := ap.GetSpec()["action"].(string); ok && action != "ALLOW" && action != "DENY" {
+		return false
+	}
+
+	if rules, ok := ap.GetSpec()["rules"].([]interface{}); !ok || len(rules) == 0 {
+		return false
+	}
+
+	selectorLabels := common.GetSelectorLabels(ap)
+	if len(selectorLabels) == 0 {
+		return true
+	}
+	return labels.SelectorFromSet(selectorLabels).Matches(labels.Set(c.Workload.Labels))
+}
+
+func hasSuffix(names map[string]bool, suffix string) bool {
+	for n := range names {
+		if strings.HasSuffix(n, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPrefix(names map[string]bool, prefix string) bool {
+	for n := range names {
+		if strings.HasPrefix(n, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package proxies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+const proxyConfigDump = `{
+  "configs": [
+    {
+      "@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump",
+      "dynamic_active_clusters": [
+        { "cluster": { "name": "outbound|9080||reviews.bookinfo.svc.cluster.local" } },
+        { "cluster": { "name": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local" } },
+        { "cluster": { "name": "outbound|9080||ratings.bookinfo.svc.cluster.local" } }
+      ]
+    },
+    {
+      "@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump",
+      "dynamic_listeners": [
+        { "active_state": { "listener": { "filter_chains": [ { "filters": [ { "typed_config": { "http_filters": [
+          { "name": "envoy.filters.http.rbac", "typed_config": { "rules": { "policies": {
+            "ns[bookinfo]-policy[applied]-rule[0]": {}
+          } } } }
+        ] } } ] } ] } } }
+      ]
+    },
+    {
+      "@type": "type.googleapis.com/envoy.admin.v3.RoutesConfigDump",
+      "dynamic_route_configs": [
+        { "route_config": { "virtual_hosts": [ { "routes": [ { "metadata": { "filter_metadata": { "istio": {
+          "config": "/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtual-service/reviews"
+        } } } } ] } ] } }
+      ]
+    }
+  ]
+}`
+
+func TestNoDrift(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	assert := assert.New(t)
+
+	vals := ConfigDriftChecker{
+		Namespace:             "bookinfo",
+		Namespaces:            models.NamespaceNames{"bookinfo"},
+		Workload:              data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage"}),
+		ConfigDump:            fakeConfigDump(t),
+		VirtualServices:       []kubernetes.IstioObject{fakeVirtualService("reviews", "reviews")},
+		DestinationRules:      []kubernetes.IstioObject{fakeDestinationRule("reviews", "reviews", "v1")},
+		AuthorizationPolicies: []kubernetes.IstioObject{fakeAuthorizationPolicy("applied", "productpage")},
+	}.Check()
+
+	wk := vals[models.BuildKey("workload", "productpage-v1", "bookinfo")]
+	assert.NotNil(wk)
+	assert.True(wk.Valid)
+	assert.Empty(wk.Checks)
+	assert.Len(vals, 1)
+}
+
+func TestDrift(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	assert := assert.New(t)
+
+	vals := ConfigDriftChecker{
+		Namespace:  "bookinfo",
+		Namespaces: models.NamespaceNames{"bookinfo"},
+		Workload:   data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage"}),
+		ConfigDump: fakeConfigDump(t),
+		VirtualServices: []kubernetes.IstioObject{
+			fakeVirtualService("ratings", "ratings"),
+			// Unknown host for the proxy: not expected in its config
+			fakeVirtualService("details", "details"),
+		},
+		DestinationRules: []kubernetes.IstioObject{fakeDestinationRule("reviews", "reviews", "v1", "v2")},
+		AuthorizationPolicies: []kubernetes.IstioObject{
+			fakeAuthorizationPolicy("rejected", "productpage"),
+			// Selecting another workload
+			fakeAuthorizationPolicy("other", "details"),
+		},
+	}.Check()
+
+	assert.Len(vals, 4)
+
+	wk := vals[models.BuildKey("workload", "productpage-v1", "bookinfo")]
+	assert.NotNil(wk)
+	assert.Len(wk.Checks, 3)
+	assert.Len(wk.References, 3)
+	assert.Equal(models.CheckMessage("workload.proxy.configdrift"), wk.Checks[0].Message)
+
+	vs := vals[models.BuildKey("virtualservice", "ratings", "bookinfo")]
+	assert.NotNil(vs)
+	assert.Equal(models.CheckMessage("virtualservices.proxy.notapplied"), vs.Checks[0].Message)
+	assert.Equal(models.WarningSeverity, vs.Checks[0].Severity)
+	assert.Equal([]models.IstioValidationKey{models.BuildKey("workload", "productpage-v1", "bookinfo")}, vs.References)
+
+	dr := vals[models.BuildKey("destinationrule", "reviews", "bookinfo")]
+	assert.NotNil(dr)
+	assert.Len(dr.Checks, 1)
+	assert.Equal(models.CheckMessage("destinationrules.proxy.subsetnotapplied"), dr.Checks[0].Message)
+	assert.Equal("spec/subsets[1]", dr.Checks[0].Path)
+
+	ap := vals[models.BuildKey("authorizationpolicy", "rejected", "bookinfo")]
+	assert.NotNil(ap)
+	assert.Equal(models.CheckMessage("authorizationpolicy.proxy.notapplied"), ap.Checks[0].Message)
+}
+
+func fakeConfigDump(t *testing.T) *kubernetes.ConfigDump {
+	cd, err := kubernetes.ParseConfigDump([]byte(proxyConfigDump))
+	assert.NoError(t, err)
+	return cd
+}
+
+func fakeVirtualService(name, host string) kubernetes.IstioObject {
+	return (&kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: "bookinfo",
+		},
+		Spec: map[string]interface{}{
+			"hosts": []interface{}{host},
+			"http": []interface{}{
+				map[string]interface{}{
+					"route": []interface{}{
+						map[string]interface{}{
+							"destination": map[string]interface{}{"host": host},
+						},
+					},
+				},
+			},
+		},
+	}).DeepCopyIstioObject()
+}
+
+func fakeDestinationRule(name, host string, subsets ...string) kubernetes.IstioObject {
+	dr := data.CreateEmptyDestinationRule("bookinfo", name, host)
+	for _, s := range subsets {
+		dr = data.AddSubsetToDestinationRule(data.CreateSubset(s, s), dr)
+	}
+	return dr
+}
+
+func fakeAuthorizationPolicy(name, app string) kubernetes.IstioObject {
+	ap := data.CreateAuthorizationPolicy([]interface{}{"bookinfo"}, []interface{}{"GET"}, []interface{}{}, map[string]interface{}{"app": app})
+	meta := ap.GetObjectMeta()
+	meta.Name = name
+	ap.SetObjectMeta(meta)
+	return ap
+}
@@ -2,6 +2,7 @@ package checkers
 
 import (
 	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/requestauthentications"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 )
@@ -9,8 +10,11 @@ import (
 const RequestAuthenticationCheckerType = "requestauthentication"
 
 type RequestAuthenticationChecker struct {
-	RequestAuthentications []kubernetes.IstioObject
-	WorkloadList           models.WorkloadList
+	RequestAuthentications    []kubernetes.IstioObject
+	AuthorizationPolicies     []kubernetes.IstioObject
+	MeshAuthorizationPolicies []kubernetes.IstioObject
+	WorkloadList              models.WorkloadList
+	Jwks                      map[string]requestauthentications.JwksStatus
 }
 
 func (m RequestAuthenticationChecker) Check() models.IstioValidations {
@@ -32,6 +36,8 @@ func (m RequestAuthenticationChecker) runChecks(requestAuthn kubernetes.IstioObj
 
 	enabledCheckers := []Checker{
 		common.SelectorNoWorkloadFoundChecker(RequestAuthenticationCheckerType, requestAuthn, m.WorkloadList),
+		requestauthentications.JwtRulesChecker{RequestAuthn: requestAuthn, Jwks: m.Jwks},
+		requestauthentications.EnforcementChecker{RequestAuthn: requestAuthn, AuthorizationPolicies: m.AuthorizationPolicies, MeshAuthorizationPolicies: m.MeshAuthorizationPolicies, WorkloadList: m.WorkloadList},
 	}
 
 	for _, checker := range enabledCheckers {
@@ -0,0 +1,100 @@
+package requestauthentications
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type EnforcementChecker struct {
+	RequestAuthn              kubernetes.IstioObject
+	AuthorizationPolicies     []kubernetes.IstioObject
+	MeshAuthorizationPolicies []kubernetes.IstioObject
+	WorkloadList              models.WorkloadList
+}
+
+// Check validates that the workloads selected by the RequestAuthentication are protected by an AuthorizationPolicy
+// requiring a request principal. A RequestAuthentication alone only rejects invalid tokens: requests without token are allowed.
+func (c EnforcementChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	// Mesh-wide RequestAuthentications are enforced by the policies of each namespace
+	if !c.RequestAuthn.HasMatchLabelsSelector() && c.RequestAuthn.GetObjectMeta().Namespace == config.Get().IstioNamespace {
+		return checks, valid
+	}
+
+	selector := labels.SelectorFromSet(common.GetSelectorLabels(c.RequestAuthn))
+	for _, wk := range c.WorkloadList.Workloads {
+		wkLabels := labels.Set(wk.Labels)
+		if !selector.Matches(wkLabels) {
+			continue
+		}
+		if !c.enforced(wkLabels) {
+			check := models.Build("requestauthentication.authorization.notenforced", "spec")
+			return append(checks, &check), valid
+		}
+	}
+
+	return checks, valid
+}
+
+// enforced checks whether an AuthorizationPolicy applied to the workload uses the request principal.
+// The policies of the root namespace apply to the workloads of every namespace.
+func (c EnforcementChecker) enforced(wkLabels labels.Set) bool {
+	policies := append(append([]kubernetes.IstioObject{}, c.AuthorizationPolicies...), c.MeshAuthorizationPolicies...)
+	for _, ap := range policies {
+		if ap.HasMatchLabelsSelector() && !labels.SelectorFromSet(common.GetSelectorLabels(ap)).Matches(wkLabels) {
+			continue
+		}
+		if usesRequestPrincipals(ap) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesRequestPrincipals checks whether any rule of the AuthorizationPolicy has a source matching request principals
+// or a condition on the request.auth attributes
+func usesRequestPrincipals(ap kubernetes.IstioObject) bool {
+	rules, ok := ap.GetSpec()["rules"].([]interface{})
+	if !ok {
+		return false
+	}
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if froms, ok := rule["from"].([]interface{}); ok {
+			for _, f := range froms {
+				from, ok := f.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if source, ok := from["source"].(map[string]interface{}); ok {
+					if _, found := source["requestPrincipals"]; found {
+						return true
+					}
+					if _, found := source["notRequestPrincipals"]; found {
+						return true
+					}
+				}
+			}
+		}
+		if whens, ok := rule["when"].([]interface{}); ok {
+			for _, w := range whens {
+				if when, ok := w.(map[string]interface{}); ok {
+					if key, ok := when["key"].(string); ok && strings.HasPrefix(key, "request.auth.") {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package requestauthentications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestRequestAuthnEnforced(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := EnforcementChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", map[string]interface{}{"app": "reviews"}, nil),
+		AuthorizationPolicies: []kubernetes.IstioObject{
+			data.CreateAuthorizationPolicyWithRules("require-jwt", "ALLOW", map[string]interface{}{"app": "reviews"}, []interface{}{
+				map[string]interface{}{
+					"from": []interface{}{
+						map[string]interface{}{"source": map[string]interface{}{"requestPrincipals": []interface{}{"*"}}},
+					},
+				},
+			}),
+		},
+		WorkloadList: fakeWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestRequestAuthnEnforcedByCondition(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	// Namespace-wide policy with a condition on the token claims
+	validations, valid := EnforcementChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", map[string]interface{}{"app": "reviews"}, nil),
+		AuthorizationPolicies: []kubernetes.IstioObject{
+			data.CreateAuthorizationPolicyWithRules("require-claim", "ALLOW", nil, []interface{}{
+				map[string]interface{}{
+					"when": []interface{}{
+						map[string]interface{}{"key": "request.auth.claims[groups]", "values": []interface{}{"group1"}},
+					},
+				},
+			}),
+		},
+		WorkloadList: fakeWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestRequestAuthnNotEnforced(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := EnforcementChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", nil, nil),
+		AuthorizationPolicies: []kubernetes.IstioObject{
+			// Only applied to details
+			data.CreateAuthorizationPolicyWithRules("require-jwt", "ALLOW", map[string]interface{}{"app": "details"}, []interface{}{
+				map[string]interface{}{
+					"from": []interface{}{
+						map[string]interface{}{"source": map[string]interface{}{"requestPrincipals": []interface{}{"*"}}},
+					},
+				},
+			}),
+			data.CreateAuthorizationPolicyWithRules("allow-ns", "ALLOW", nil, []interface{}{
+				map[string]interface{}{
+					"from": []interface{}{
+						map[string]interface{}{"source": map[string]interface{}{"namespaces": []interface{}{"bookinfo"}}},
+					},
+				},
+			}),
+		},
+		WorkloadList: fakeWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("requestauthentication.authorization.notenforced"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec", validations[0].Path)
+}
+
+func TestRequestAuthnEnforcedFromRootNamespace(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	// Mesh-wide policy denying the requests without token
+	meshPolicy := data.CreateAuthorizationPolicyWithRules("require-jwt", "DENY", nil, []interface{}{
+		map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{"source": map[string]interface{}{"notRequestPrincipals": []interface{}{"*"}}},
+			},
+		},
+	})
+	meta := meshPolicy.GetObjectMeta()
+	meta.Namespace = "istio-system"
+	meshPolicy.SetObjectMeta(meta)
+
+	validations, valid := EnforcementChecker{
+		RequestAuthn:              data.CreateRequestAuthentication("jwt", "bookinfo", nil, nil),
+		MeshAuthorizationPolicies: []kubernetes.IstioObject{meshPolicy},
+		WorkloadList:              fakeWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestRequestAuthnEnforcedFromRootNamespaceWithSelector(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	// Policy of the root namespace only applied to the reviews workloads of any namespace
+	meshPolicy := data.CreateAuthorizationPolicyWithRules("require-jwt", "ALLOW", map[string]interface{}{"app": "reviews"}, []interface{}{
+		map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{"source": map[string]interface{}{"requestPrincipals": []interface{}{"*"}}},
+			},
+		},
+	})
+	meta := meshPolicy.GetObjectMeta()
+	meta.Namespace = "istio-system"
+	meshPolicy.SetObjectMeta(meta)
+
+	validations, valid := EnforcementChecker{
+		RequestAuthn:              data.CreateRequestAuthentication("jwt", "bookinfo", map[string]interface{}{"app": "reviews"}, nil),
+		MeshAuthorizationPolicies: []kubernetes.IstioObject{meshPolicy},
+		WorkloadList:              fakeWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+
+	validations, valid = EnforcementChecker{
+		RequestAuthn:              data.CreateRequestAuthentication("jwt", "bookinfo", map[string]interface{}{"app": "details"}, nil),
+		MeshAuthorizationPolicies: []kubernetes.IstioObject{meshPolicy},
+		WorkloadList:              fakeWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("requestauthentication.authorization.notenforced"), validations[0].Message)
+}
+
+func TestRequestAuthnWithoutWorkloads(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := EnforcementChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", map[string]interface{}{"app": "ratings"}, nil),
+		WorkloadList: fakeWorkloads(),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestMeshWideRequestAuthn(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := EnforcementChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "istio-system", nil, nil),
+		WorkloadList: data.CreateWorkloadList("istio-system", data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"app": "istio-ingressgateway"})),
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func fakeWorkloads() models.WorkloadList {
+	return data.CreateWorkloadList("bookinfo",
+		data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+		data.CreateWorkloadListItem("details-v1", map[string]string{"app": "details", "version": "v1"}),
+	)
+}
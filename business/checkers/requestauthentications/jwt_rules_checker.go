@@ -0,0 +1,168 @@
+package requestauthentications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// JwksStatus is the result of fetching the JWKS of a jwksUri
+type JwksStatus struct {
+	// Reachable is false when the jwksUri can't be fetched
+	Reachable bool
+	// Err is the fetch error of an unreachable jwksUri, or the parse error of the JWKS returned
+	Err error
+}
+
+type JwtRulesChecker struct {
+	RequestAuthn kubernetes.IstioObject
+	// JWKS fetched by jwksUri. URIs not fetched are left out and not validated.
+	Jwks map[string]JwksStatus
+}
+
+// Check validates the jwtRules of a RequestAuthentication: issuer is required, the JWKS, inline or fetched
+// from the jwksUri, must be valid and the token locations shouldn't be shared between rules.
+func (c JwtRulesChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	rules, ok := c.RequestAuthn.GetSpec()["jwtRules"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	headers := map[string]int{}
+	params := map[string]int{}
+	for ruleIdx, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		path := fmt.Sprintf("spec/jwtRules[%d]", ruleIdx)
+
+		if issuer, ok := rule["issuer"].(string); !ok || issuer == "" {
+			check := models.Build("requestauthentication.jwt.issuermissing", path)
+			checks = append(checks, &check)
+			valid = false
+		}
+
+		if jwks, ok := rule["jwks"].(string); ok && jwks != "" {
+			if err := ParseJwks([]byte(jwks)); err != nil {
+				check := models.Build("requestauthentication.jwt.inlinejwksinvalid", path+"/jwks")
+				check.Detail = err.Error()
+				checks = append(checks, &check)
+				valid = false
+			}
+		} else if uri, ok := rule["jwksUri"].(string); ok && uri != "" {
+			if status, found := c.Jwks[uri]; found && status.Err != nil {
+				checkId := "requestauthentication.jwt.jwksinvalid"
+				if !status.Reachable {
+					checkId = "requestauthentication.jwt.jwksunreachable"
+				}
+				// The fetch and parse errors of the remote JWKS are only logged when fetched
+				check := models.Build(checkId, path+"/jwksUri")
+				checks = append(checks, &check)
+				valid = false
+			}
+		}
+
+		checks = append(checks, locationCollisions(rule, ruleIdx, "fromHeaders", headers)...)
+		checks = append(checks, locationCollisions(rule, ruleIdx, "fromParams", params)...)
+	}
+
+	return checks, valid
+}
+
+// locationCollisions reports the headers or params of the rule already used by a previous rule.
+// Header names are case insensitive.
+func locationCollisions(rule map[string]interface{}, ruleIdx int, field string, used map[string]int) []*models.IstioCheck {
+	checks := make([]*models.IstioCheck, 0)
+
+	locations, ok := rule[field].([]interface{})
+	if !ok {
+		return checks
+	}
+	for i, l := range locations {
+		var name string
+		if field == "fromHeaders" {
+			header, ok := l.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ = header["name"].(string)
+			name = strings.ToLower(name)
+		} else {
+			name, _ = l.(string)
+		}
+		if name == "" {
+			continue
+		}
+
+		if previous, found := used[name]; found && previous != ruleIdx {
+			check := models.Build("requestauthentication.jwt.locationcollision", fmt.Sprintf("spec/jwtRules[%d]/%s[%d]", ruleIdx, field, i))
+			checks = append(checks, &check)
+		} else if !found {
+			used[name] = ruleIdx
+		}
+	}
+
+	return checks
+}
+
+// JwksUris returns the jwksUri of the rules of a RequestAuthentication that don't define an inline JWKS
+func JwksUris(requestAuthn kubernetes.IstioObject) []string {
+	uris := make([]string, 0)
+	rules, ok := requestAuthn.GetSpec()["jwtRules"].([]interface{})
+	if !ok {
+		return uris
+	}
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if jwks, ok := rule["jwks"].(string); ok && jwks != "" {
+			continue
+		}
+		if uri, ok := rule["jwksUri"].(string); ok && uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// ParseJwks validates a JSON Web Key Set: it must contain at least one key, and every key the parameters required by its type
+func ParseJwks(data []byte) error {
+	jwks := struct {
+		Keys []map[string]interface{} `json:"keys"`
+	}{}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return err
+	}
+	if len(jwks.Keys) == 0 {
+		return errors.New("no keys found")
+	}
+
+	required := map[string][]string{
+		"RSA": {"n", "e"},
+		"EC":  {"crv", "x", "y"},
+		"OKP": {"crv", "x"},
+		"oct": {"k"},
+	}
+	for i, key := range jwks.Keys {
+		kty, _ := key["kty"].(string)
+		params, known := required[kty]
+		if !known {
+			return fmt.Errorf("key %d has an unknown type %q", i, kty)
+		}
+		for _, param := range params {
+			if value, ok := key[param].(string); !ok || value == "" {
+				return fmt.Errorf("%s key %d without %s", kty, i, param)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package requestauthentications
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+const validJwks = `{"keys":[{"kty":"RSA","kid":"key-1","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbf","e":"AQAB"}]}`
+
+func TestValidJwtRules(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := JwtRulesChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", nil, []interface{}{
+			map[string]interface{}{"issuer": "issuer-1", "jwks": validJwks},
+			map[string]interface{}{"issuer": "issuer-2", "jwksUri": "https://issuer-2/jwks.json"},
+		}),
+		Jwks: map[string]JwksStatus{"https://issuer-2/jwks.json": {Reachable: true}},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestJwtRuleWithoutIssuer(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := JwtRulesChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", nil, []interface{}{
+			map[string]interface{}{"issuer": "issuer-1"},
+			map[string]interface{}{"jwks": validJwks},
+		}),
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("requestauthentication.jwt.issuermissing"), validations[0].Message)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal("spec/jwtRules[1]", validations[0].Path)
+}
+
+func TestInvalidInlineJwks(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := JwtRulesChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", nil, []interface{}{
+			map[string]interface{}{"issuer": "issuer-1", "jwks": `{"keys":[{"kty":"RSA","e":"AQAB"}]}`},
+		}),
+	}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("requestauthentication.jwt.inlinejwksinvalid"), validations[0].Message)
+	assert.Equal("RSA key 0 without n", validations[0].Detail)
+	assert.Equal("spec/jwtRules[0]/jwks", validations[0].Path)
+}
+
+func TestJwksUriErrors(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := JwtRulesChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", nil, []interface{}{
+			map[string]interface{}{"issuer": "issuer-1", "jwksUri": "https://issuer-1/jwks.json"},
+			map[string]interface{}{"issuer": "issuer-2", "jwksUri": "https://issuer-2/jwks.json"},
+			map[string]interface{}{"issuer": "issuer-3", "jwksUri": "https://issuer-3/jwks.json"},
+		}),
+		Jwks: map[string]JwksStatus{
+			"https://issuer-1/jwks.json": {Reachable: false, Err: errors.New("HTTP status 404")},
+			"https://issuer-2/jwks.json": {Reachable: true, Err: errors.New("no keys found")},
+		},
+	}.Check()
+
+	// issuer-3 was not fetched, so it is not validated
+	assert.False(valid)
+	assert.Len(validations, 2)
+	assert.Equal(models.CheckMessage("requestauthentication.jwt.jwksunreachable"), validations[0].Message)
+	assert.Equal("spec/jwtRules[0]/jwksUri", validations[0].Path)
+	// Remote errors are not reported in the checks
+	assert.Empty(validations[0].Detail)
+	assert.Equal(models.CheckMessage("requestauthentication.jwt.jwksinvalid"), validations[1].Message)
+	assert.Equal("spec/jwtRules[1]/jwksUri", validations[1].Path)
+}
+
+func TestTokenLocationCollision(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := JwtRulesChecker{
+		RequestAuthn: data.CreateRequestAuthentication("jwt", "bookinfo", nil, []interface{}{
+			map[string]interface{}{
+				"issuer":      "issuer-1",
+				"jwks":        validJwks,
+				"fromHeaders": []interface{}{map[string]interface{}{"name": "x-jwt-assertion"}},
+				"fromParams":  []interface{}{"token"},
+			},
+			map[string]interface{}{
+				"issuer":      "issuer-2",
+				"jwks":        validJwks,
+				"fromHeaders": []interface{}{map[string]interface{}{"name": "X-JWT-Assertion", "prefix": "Bearer "}},
+				"fromParams":  []interface{}{"access_token", "token"},
+			},
+		}),
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 2)
+	assert.Equal(models.CheckMessage("requestauthentication.jwt.locationcollision"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/jwtRules[1]/fromHeaders[0]", validations[0].Path)
+	assert.Equal("spec/jwtRules[1]/fromParams[1]", validations[1].Path)
+}
+
+func TestParseJwks(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(ParseJwks([]byte(validJwks)))
+	assert.NoError(ParseJwks([]byte(`{"keys":[{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}]}`)))
+	assert.Error(ParseJwks([]byte(`not json`)))
+	assert.EqualError(ParseJwks([]byte(`{"keys":[]}`)), "no keys found")
+	assert.EqualError(ParseJwks([]byte(`{"keys":[{"kty":"XYZ"}]}`)), `key 0 has an unknown type "XYZ"`)
+}
+
+func TestJwksUris(t *testing.T) {
+	assert := assert.New(t)
+
+	uris := JwksUris(data.CreateRequestAuthentication("jwt", "bookinfo", nil, []interface{}{
+		map[string]interface{}{"issuer": "issuer-1", "jwksUri": "https://issuer-1/jwks.json"},
+		map[string]interface{}{"issuer": "issuer-2", "jwksUri": "https://issuer-2/jwks.json", "jwks": validJwks},
+	}))
+
+	// Inline JWKS take precedence over the jwksUri
+	assert.Equal([]string{"https://issuer-1/jwks.json"}, uris)
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type HeadlessChecker struct {
+	Service         v1.Service
+	VirtualServices []kubernetes.IstioObject
+}
+
+// Check reports headless Services routed by a VirtualService. Clients connect straight to the pod IPs
+// of a headless Service, so traffic splitting and most of the HTTP routing rules are not applied.
+func (h HeadlessChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+
+	if h.Service.Spec.ClusterIP != v1.ClusterIPNone {
+		return validations, true
+	}
+
+	for _, vs := range h.VirtualServices {
+		if kubernetes.FilterByRoute(vs.GetSpec(), []string{"http", "tcp", "tls"}, h.Service.Name, h.Service.Namespace, nil) {
+			validation := models.Build("service.headless.virtualservice", "spec/clusterIP")
+			validation.Detail = fmt.Sprintf("%s.%s", vs.GetObjectMeta().Name, vs.GetObjectMeta().Namespace)
+			validations = append(validations, &validation)
+		}
+	}
+
+	return validations, true
+}
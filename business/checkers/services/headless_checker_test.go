@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestHeadlessServiceWithVirtualService(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	service := getService(9080, "http-web")
+	service.Namespace = "bookinfo"
+	service.Spec.ClusterIP = v1.ClusterIPNone
+
+	validations, valid := HeadlessChecker{Service: service, VirtualServices: headlessVirtualServices()}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("service.headless.virtualservice"), validations[0].Message)
+	assert.Equal("service1-vs.bookinfo", validations[0].Detail)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/clusterIP", validations[0].Path)
+}
+
+func TestServiceWithVirtualService(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	service := getService(9080, "http-web")
+	service.Namespace = "bookinfo"
+
+	validations, valid := HeadlessChecker{Service: service, VirtualServices: headlessVirtualServices()}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func headlessVirtualServices() []kubernetes.IstioObject {
+	return []kubernetes.IstioObject{
+		data.AddRoutesToVirtualService("http", data.CreateRoute("service1", "v1", -1),
+			data.CreateEmptyVirtualService("service1-vs", "bookinfo", []string{"service1"})),
+		data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", -1),
+			data.CreateEmptyVirtualService("reviews-vs", "bookinfo", []string{"reviews"})),
+	}
+}
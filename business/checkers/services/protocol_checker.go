@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business/checkers/workloads"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type ProtocolChecker struct {
+	Namespace models.Namespace
+	Service   v1.Service
+	// Workloads selected by the Service
+	Workloads []models.WorkloadListItem
+}
+
+// Check validates that the protocols declared by the port names of the Services in the mesh are consistent:
+// UDP ports can't be named after a TCP-based protocol, and the ports targeting the same
+// container port should declare the same protocol, otherwise Istio will apply one of them to all.
+// Services are in the mesh when their namespace is injected or some of their workloads has a sidecar.
+func (p ProtocolChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+	if !p.inMesh() {
+		return validations, true
+	}
+
+	targetProtocols := map[string]string{}
+	for portIndex, sp := range p.Service.Spec.Ports {
+		protocol := kubernetes.PortNameProtocol(sp.Name)
+		if protocol == "" {
+			continue
+		}
+
+		isUdp := strings.ToLower(string(sp.Protocol)) == "udp"
+		if isUdp != (protocol == "udp") {
+			validation := models.Build("service.port.protocolmismatch", fmt.Sprintf("spec/ports[%d]/name", portIndex))
+			validations = append(validations, &validation)
+			continue
+		}
+
+		targetPort := sp.TargetPort.String()
+		if sp.TargetPort.IntValue() == 0 && sp.TargetPort.StrVal == "" {
+			targetPort = fmt.Sprintf("%d", sp.Port)
+		}
+		if previous, found := targetProtocols[targetPort]; found && previous != protocol {
+			validation := models.Build("service.port.protocolconflict", fmt.Sprintf("spec/ports[%d]/name", portIndex))
+			validations = append(validations, &validation)
+		} else if !found {
+			targetProtocols[targetPort] = protocol
+		}
+	}
+
+	return validations, true
+}
+
+func (p ProtocolChecker) inMesh() bool {
+	if workloads.IsInjectionEnabled(p.Namespace) {
+		return true
+	}
+	for _, wk := range p.Workloads {
+		if wk.IstioSidecar {
+			return true
+		}
+	}
+	return false
+}
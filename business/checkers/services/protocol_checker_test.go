@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+var injected = models.Namespace{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled"}}
+
+func TestConsistentProtocols(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	service := getService(9080, "http-web")
+	service.Spec.Ports = append(service.Spec.Ports,
+		v1.ServicePort{Name: "http2-api", Port: 9081},
+		v1.ServicePort{Name: "udp-dns", Port: 53, Protocol: v1.ProtocolUDP},
+		v1.ServicePort{Name: "metrics", Port: 9090},
+	)
+
+	validations, valid := ProtocolChecker{Namespace: injected, Service: service}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestProtocolConflict(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	service := getService(9080, "http-web")
+	service.Spec.Ports[0].TargetPort = intstr.FromInt(8080)
+	service.Spec.Ports = append(service.Spec.Ports, v1.ServicePort{Name: "grpc-api", Port: 9081, TargetPort: intstr.FromInt(8080)})
+
+	validations, valid := ProtocolChecker{Namespace: injected, Service: service}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("service.port.protocolconflict"), validations[0].Message)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/ports[1]/name", validations[0].Path)
+}
+
+func TestProtocolMismatch(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	service := getService(53, "http-dns")
+	service.Spec.Ports[0].Protocol = v1.ProtocolUDP
+	service.Spec.Ports = append(service.Spec.Ports, v1.ServicePort{Name: "udp-data", Port: 9081})
+
+	validations, valid := ProtocolChecker{Namespace: injected, Service: service}.Check()
+	assert.True(valid)
+	assert.Len(validations, 2)
+	assert.Equal(models.CheckMessage("service.port.protocolmismatch"), validations[0].Message)
+	assert.Equal("spec/ports[0]/name", validations[0].Path)
+	assert.Equal(models.CheckMessage("service.port.protocolmismatch"), validations[1].Message)
+	assert.Equal("spec/ports[1]/name", validations[1].Path)
+}
+
+func TestProtocolsOutOfMesh(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	service := getService(53, "http-dns")
+	service.Spec.Ports[0].Protocol = v1.ProtocolUDP
+
+	validations, valid := ProtocolChecker{Namespace: models.Namespace{Name: "default"}, Service: service}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+
+	// A workload with a sidecar brings the Service in the mesh
+	validations, _ = ProtocolChecker{
+		Namespace: models.Namespace{Name: "default"},
+		Service:   service,
+		Workloads: []models.WorkloadListItem{{Name: "dns", IstioSidecar: true}},
+	}.Check()
+	assert.Len(validations, 1)
+}
@@ -0,0 +1,158 @@
+package sidecars
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type TrafficChecker struct {
+	Sidecar      kubernetes.IstioObject
+	Sidecars     []kubernetes.IstioObject
+	WorkloadList models.WorkloadList
+	// Hosts observed in the outbound traffic of each workload of the namespace
+	OutboundHosts map[string][]models.OutboundHost
+}
+
+// Check compares the egress hosts of the sidecar with the outbound traffic of the workloads it is applied to.
+// Requests to hosts not declared would fail once the proxy configuration is restricted to the registry
+// (outboundTrafficPolicy: REGISTRY_ONLY), while declared hosts never called only increase the proxy configuration.
+func (tc TrafficChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	egress, ok := tc.Sidecar.GetSpec()["egress"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	observed := tc.observedHosts()
+	used := map[string]bool{}
+
+	for _, oh := range observed {
+		declared := false
+		for i, e := range egress {
+			listener, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			hosts, ok := listener["hosts"].([]interface{})
+			if !ok {
+				continue
+			}
+			for j, h := range hosts {
+				if host, ok := h.(string); ok && tc.matchesEgressHost(host, oh) {
+					used[fmt.Sprintf("%d/%d", i, j)] = true
+					declared = true
+				}
+			}
+		}
+		if !declared {
+			check := models.Build("sidecar.egress.undeclaredhost", "spec/egress")
+			check.Detail = oh.Host
+			checks = append(checks, &check)
+		}
+	}
+
+	// Without observed traffic, unused hosts are not reported as the workloads may be idle
+	if len(observed) == 0 {
+		return checks, valid
+	}
+
+	for i, e := range egress {
+		listener, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hosts, ok := listener["hosts"].([]interface{})
+		if !ok {
+			continue
+		}
+		for j, h := range hosts {
+			host, ok := h.(string)
+			// Wildcard hosts are not expected to be fully used
+			if !ok || strings.HasSuffix(host, "/*") || used[fmt.Sprintf("%d/%d", i, j)] {
+				continue
+			}
+			checks = append(checks, buildCheck("sidecar.egress.unusedhost", i, j))
+		}
+	}
+
+	return checks, valid
+}
+
+// observedHosts returns the distinct hosts called by the workloads the sidecar is applied to.
+// A sidecar without workloadSelector is applied to the workloads not selected by any other sidecar of the namespace.
+func (tc TrafficChecker) observedHosts() []models.OutboundHost {
+	hosts := make([]models.OutboundHost, 0)
+	seen := map[models.OutboundHost]bool{}
+
+	for _, wk := range tc.WorkloadList.Workloads {
+		if !tc.appliesTo(wk) {
+			continue
+		}
+		for _, oh := range tc.OutboundHosts[wk.Name] {
+			if !seen[oh] {
+				seen[oh] = true
+				hosts = append(hosts, oh)
+			}
+		}
+	}
+
+	return hosts
+}
+
+func (tc TrafficChecker) appliesTo(wk models.WorkloadListItem) bool {
+	wkLabels := labels.Set(wk.Labels)
+	if common.HasWorkloadSelector(tc.Sidecar) {
+		return labels.SelectorFromSet(common.GetWorkloadSelectorLabels(tc.Sidecar)).Matches(wkLabels)
+	}
+	for _, sc := range tc.Sidecars {
+		if sc.GetObjectMeta().Namespace == tc.Sidecar.GetObjectMeta().Namespace && common.HasWorkloadSelector(sc) &&
+			labels.SelectorFromSet(common.GetWorkloadSelectorLabels(sc)).Matches(wkLabels) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesEgressHost checks whether an egress host, in namespace/dnsName format, imports the observed host
+func (tc TrafficChecker) matchesEgressHost(egressHost string, observed models.OutboundHost) bool {
+	hostNs, dnsName, valid := getHostComponents(egressHost)
+	if !valid {
+		return false
+	}
+
+	sidecarNs := tc.Sidecar.GetObjectMeta().Namespace
+	switch hostNs {
+	case "*":
+	case ".":
+		if observed.Namespace != sidecarNs {
+			return false
+		}
+	default:
+		if hostNs != observed.Namespace {
+			return false
+		}
+	}
+
+	if dnsName == "*" {
+		return true
+	}
+	if strings.HasPrefix(dnsName, "*") {
+		return strings.HasSuffix(observed.Host, dnsName[1:])
+	}
+	if dnsName == observed.Host {
+		return true
+	}
+
+	// Short names are resolved in the namespace of the egress host
+	if hostNs == "." || hostNs == "*" {
+		hostNs = sidecarNs
+	}
+	return kubernetes.ParseHost(dnsName, hostNs, tc.Sidecar.GetObjectMeta().ClusterName).String() == observed.Host
+}
@@ -0,0 +1,108 @@
+package sidecars
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestTrafficDeclaredHosts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	checks, valid := TrafficChecker{
+		Sidecar: sidecarWithHosts([]interface{}{
+			"istio-system/*",
+			"./reviews",
+			"travel/*.travel.svc.cluster.local",
+		}),
+		WorkloadList: trafficWorkloads(),
+		OutboundHosts: map[string][]models.OutboundHost{
+			"productpage-v1": {
+				{Host: "reviews.bookinfo.svc.cluster.local", Namespace: "bookinfo"},
+				{Host: "hotels.travel.svc.cluster.local", Namespace: "travel"},
+			},
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestTrafficUndeclaredAndUnusedHosts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	checks, valid := TrafficChecker{
+		Sidecar: sidecarWithHosts([]interface{}{
+			"istio-system/*",
+			"./reviews.bookinfo.svc.cluster.local",
+			"./ratings.bookinfo.svc.cluster.local",
+		}),
+		WorkloadList: trafficWorkloads(),
+		OutboundHosts: map[string][]models.OutboundHost{
+			"productpage-v1": {
+				{Host: "reviews.bookinfo.svc.cluster.local", Namespace: "bookinfo"},
+				{Host: "details.bookinfo.svc.cluster.local", Namespace: "bookinfo"},
+			},
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Len(checks, 2)
+	assert.Equal(models.CheckMessage("sidecar.egress.undeclaredhost"), checks[0].Message)
+	assert.Equal("details.bookinfo.svc.cluster.local", checks[0].Detail)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/egress", checks[0].Path)
+	assert.Equal(models.CheckMessage("sidecar.egress.unusedhost"), checks[1].Message)
+	assert.Equal(models.Unknown, checks[1].Severity)
+	assert.Equal("spec/egress[0]/hosts[2]", checks[1].Path)
+}
+
+func TestTrafficOfWorkloadsWithOwnSidecar(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	productpageSidecar := data.AddSelectorToSidecar(map[string]interface{}{
+		"labels": map[string]interface{}{"app": "productpage"},
+	}, sidecarWithHosts([]interface{}{"./*"}))
+	defaultSidecar := sidecarWithHosts([]interface{}{"./ratings.bookinfo.svc.cluster.local"})
+
+	checks, valid := TrafficChecker{
+		Sidecar:      defaultSidecar,
+		Sidecars:     []kubernetes.IstioObject{productpageSidecar, defaultSidecar},
+		WorkloadList: trafficWorkloads(),
+		OutboundHosts: map[string][]models.OutboundHost{
+			"productpage-v1": {{Host: "details.bookinfo.svc.cluster.local", Namespace: "bookinfo"}},
+			"reviews-v2":     {{Host: "ratings.bookinfo.svc.cluster.local", Namespace: "bookinfo"}},
+		},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestTrafficNotObserved(t *testing.T) {
+	assert := assert.New(t)
+
+	checks, valid := TrafficChecker{
+		Sidecar:       sidecarWithHosts([]interface{}{"./reviews.bookinfo.svc.cluster.local"}),
+		WorkloadList:  trafficWorkloads(),
+		OutboundHosts: map[string][]models.OutboundHost{},
+	}.Check()
+
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func trafficWorkloads() models.WorkloadList {
+	return data.CreateWorkloadList("bookinfo",
+		data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage", "version": "v1"}),
+		data.CreateWorkloadListItem("reviews-v2", map[string]string{"app": "reviews", "version": "v2"}),
+	)
+}
@@ -0,0 +1,36 @@
+package checkers
+
+import (
+	"github.com/kiali/kiali/business/checkers/versions"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// VersionProfileChecker validates the Istio objects of a namespace against an Istio release: the release of the
+// control plane or a target release, to plan an upgrade. Only the objects with findings are reported.
+type VersionProfileChecker struct {
+	Namespace string
+	Release   string
+	// Objects indexed by object type, i.e. as returned by validatedObjects
+	Objects map[string][]kubernetes.IstioObject
+}
+
+func (v VersionProfileChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+
+	for objectType, objects := range v.Objects {
+		for _, o := range objects {
+			meta := o.GetObjectMeta()
+			// Cluster-scoped objects are reported along the Istio namespace
+			if meta.Namespace != v.Namespace && (meta.Namespace != "" || v.Namespace != config.Get().IstioNamespace) {
+				continue
+			}
+			validations.MergeValidations(runIfFound(meta.Name, meta.Namespace, objectType, []Checker{
+				versions.ProfileChecker{ObjectType: objectType, Object: o, Release: v.Release},
+			}))
+		}
+	}
+
+	return validations
+}
@@ -0,0 +1,52 @@
+package checkers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestVersionProfile(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	objects := map[string][]kubernetes.IstioObject{
+		"peerauthentication": {
+			data.CreateEmptyPeerAuthentication("default", "bookinfo", data.CreateMTLS("STRICT")),
+			// Mesh-wide objects of the Istio namespace are not reported in other namespaces
+			data.CreateEmptyMeshPeerAuthentication("default", data.CreateMTLS("STRICT")),
+		},
+		"authorizationpolicy": {
+			data.CreateAuthorizationPolicyWithRules("allow-all", "ALLOW", nil, nil),
+		},
+		"clusterrbacconfig": {
+			&kubernetes.GenericIstioObject{ObjectMeta: meta_v1.ObjectMeta{Name: "default"}, Spec: map[string]interface{}{}},
+		},
+	}
+
+	validations := VersionProfileChecker{Namespace: "bookinfo", Release: "1.4", Objects: objects}.Check()
+
+	assert.Len(validations, 1)
+	validation, found := validations[models.BuildKey("peerauthentication", "default", "bookinfo")]
+	assert.True(found)
+	assert.False(validation.Valid)
+	assert.Len(validation.Checks, 1)
+	assert.Equal("KIA1402", validation.Checks[0].Code)
+
+	// Cluster-scoped objects are reported along the Istio namespace
+	validations = VersionProfileChecker{Namespace: "istio-system", Release: "1.6", Objects: objects}.Check()
+
+	assert.Len(validations, 1)
+	validation, found = validations[models.BuildKey("clusterrbacconfig", "default", "")]
+	assert.True(found)
+	assert.False(validation.Valid)
+	assert.Equal("KIA1401", validation.Checks[0].Code)
+}
@@ -0,0 +1,101 @@
+package versions
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type ProfileChecker struct {
+	ObjectType string
+	Object     kubernetes.IstioObject
+	// Istio release the object is validated against, empty when unknown
+	Release string
+}
+
+// Check reports the resource types and fields removed, not yet introduced or deprecated in the Istio release.
+// When the release is unknown, only the deprecations are reported.
+func (p ProfileChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+	release, known := ParseRelease(p.Release)
+
+	if profile, found := ResourceProfiles[p.ObjectType]; found {
+		if check := p.evaluate(profile, release, known, "version.resource", "spec"); check != nil {
+			validations = append(validations, check)
+		}
+	}
+
+	for _, profile := range FieldProfiles {
+		if profile.ObjectType != p.ObjectType {
+			continue
+		}
+		for _, path := range findField(p.Object.GetSpec(), strings.Split(profile.Path, "/"), "spec", profile.Value) {
+			if check := p.evaluate(profile.Profile, release, known, "version.field", path); check != nil {
+				validations = append(validations, check)
+			}
+		}
+	}
+
+	valid := true
+	for _, check := range validations {
+		valid = valid && check.Severity != models.ErrorSeverity
+	}
+	return validations, valid
+}
+
+func (p ProfileChecker) evaluate(profile Profile, release Release, known bool, checkPrefix, path string) *models.IstioCheck {
+	var check models.IstioCheck
+	var detail string
+	switch {
+	case known && profile.Removed != "" && !release.Before(profile.Removed):
+		check = models.Build(checkPrefix+".removed", path)
+		detail = fmt.Sprintf("removed in Istio %s, validated against %s", profile.Removed, release)
+	case known && profile.Introduced != "" && release.Before(profile.Introduced):
+		check = models.Build(checkPrefix+".unsupported", path)
+		detail = fmt.Sprintf("introduced in Istio %s, validated against %s", profile.Introduced, release)
+	case profile.Deprecated != "" && (!known || !release.Before(profile.Deprecated)):
+		check = models.Build(checkPrefix+".deprecated", path)
+		detail = fmt.Sprintf("deprecated since Istio %s", profile.Deprecated)
+	default:
+		return nil
+	}
+
+	if profile.Replacement != "" {
+		detail = fmt.Sprintf("%s, use %s instead", detail, profile.Replacement)
+	}
+	check.Detail = detail
+	return &check
+}
+
+// findField returns the paths where the field is set, i.e. spec/http[0]/mirror_percent
+func findField(node interface{}, segments []string, path, value string) []string {
+	if len(segments) == 0 {
+		if value == "" || fmt.Sprintf("%v", node) == value {
+			return []string{path}
+		}
+		return []string{}
+	}
+
+	fields, ok := node.(map[string]interface{})
+	if !ok {
+		return []string{}
+	}
+	name := strings.TrimSuffix(segments[0], "[]")
+	child, found := fields[name]
+	if !found {
+		return []string{}
+	}
+	if name == segments[0] {
+		return findField(child, segments[1:], path+"/"+name, value)
+	}
+
+	paths := []string{}
+	if items, ok := child.([]interface{}); ok {
+		for i, item := range items {
+			paths = append(paths, findField(item, segments[1:], fmt.Sprintf("%s/%s[%d]", path, name, i), value)...)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,160 @@
+package versions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestParseRelease(t *testing.T) {
+	assert := assert.New(t)
+
+	release, ok := ParseRelease("1.6.3")
+	assert.True(ok)
+	assert.Equal(Release{Major: 1, Minor: 6}, release)
+	assert.Equal("1.6", release.String())
+
+	release, ok = ParseRelease("v1.10")
+	assert.True(ok)
+	assert.True(release.Before("1.11"))
+	assert.False(release.Before("1.9"))
+	assert.False(release.Before("1.10"))
+
+	_, ok = ParseRelease("unknown")
+	assert.False(ok)
+}
+
+func TestLegacyResourceRemoved(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := ProfileChecker{ObjectType: "clusterrbacconfig", Object: legacyObject("default"), Release: "1.6"}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("version.resource.removed"), validations[0].Message)
+	assert.Equal("removed in Istio 1.6, validated against 1.6, use AuthorizationPolicy instead", validations[0].Detail)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal("spec", validations[0].Path)
+}
+
+func TestLegacyResourceDeprecated(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := ProfileChecker{ObjectType: "meshpolicy", Object: legacyObject("default"), Release: "1.5.2"}.Check()
+
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("version.resource.deprecated"), validations[0].Message)
+	assert.Equal("deprecated since Istio 1.5, use PeerAuthentication and RequestAuthentication instead", validations[0].Detail)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+
+	// Deprecations are reported when the release is unknown
+	validations, valid = ProfileChecker{ObjectType: "meshpolicy", Object: legacyObject("default")}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("version.resource.deprecated"), validations[0].Message)
+	assert.Equal("deprecated since Istio 1.5, use PeerAuthentication and RequestAuthentication instead", validations[0].Detail)
+}
+
+func TestResourceNotSupported(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	pa := data.CreateEmptyPeerAuthentication("default", "bookinfo", data.CreateMTLS("STRICT"))
+
+	validations, valid := ProfileChecker{ObjectType: "peerauthentication", Object: pa, Release: "1.4"}.Check()
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("version.resource.unsupported"), validations[0].Message)
+	assert.Equal("introduced in Istio 1.5, validated against 1.4", validations[0].Detail)
+
+	validations, valid = ProfileChecker{ObjectType: "peerauthentication", Object: pa, Release: "1.5"}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+
+	// Nothing can be told without release
+	validations, valid = ProfileChecker{ObjectType: "peerauthentication", Object: pa}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestFieldProfiles(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	vs := data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})
+	vs.GetSpec()["http"] = []interface{}{
+		map[string]interface{}{
+			"match": []interface{}{
+				map[string]interface{}{"uri": map[string]interface{}{"prefix": "/"}},
+			},
+			"route": []interface{}{data.CreateRoute("reviews", "v1", -1)},
+		},
+		map[string]interface{}{
+			"match": []interface{}{
+				map[string]interface{}{"withoutHeaders": map[string]interface{}{"end-user": map[string]interface{}{"exact": "jason"}}},
+			},
+			"route":          []interface{}{data.CreateRoute("reviews", "v1", -1)},
+			"mirror":         map[string]interface{}{"host": "reviews", "subset": "v2"},
+			"mirror_percent": 50,
+		},
+	}
+
+	validations, valid := ProfileChecker{ObjectType: "virtualservice", Object: vs, Release: "1.5"}.Check()
+
+	assert.False(valid)
+	assert.Len(validations, 2)
+	assert.Equal(models.CheckMessage("version.field.deprecated"), validations[0].Message)
+	assert.Equal("deprecated since Istio 1.5, use mirrorPercentage instead", validations[0].Detail)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/http[1]/mirror_percent", validations[0].Path)
+	assert.Equal(models.CheckMessage("version.field.unsupported"), validations[1].Message)
+	assert.Equal("introduced in Istio 1.6, validated against 1.5", validations[1].Detail)
+	assert.Equal(models.ErrorSeverity, validations[1].Severity)
+	assert.Equal("spec/http[1]/match[0]/withoutHeaders", validations[1].Path)
+}
+
+func TestFieldValueProfiles(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	custom := data.CreateAuthorizationPolicyWithRules("ext-authz", "CUSTOM", nil, nil)
+
+	validations, valid := ProfileChecker{ObjectType: "authorizationpolicy", Object: custom, Release: "1.8"}.Check()
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("version.field.unsupported"), validations[0].Message)
+	assert.Equal("introduced in Istio 1.9, validated against 1.8", validations[0].Detail)
+	assert.Equal("spec/action", validations[0].Path)
+
+	// Other actions are supported
+	deny := data.CreateAuthorizationPolicyWithRules("deny", "DENY", nil, nil)
+	validations, valid = ProfileChecker{ObjectType: "authorizationpolicy", Object: deny, Release: "1.8"}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func legacyObject(name string) kubernetes.IstioObject {
+	return &kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: name},
+		Spec:       map[string]interface{}{},
+	}
+}
@@ -0,0 +1,86 @@
+package versions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+var releaseExpr = regexp.MustCompile(`^v?([0-9]+)\.([0-9]+)`)
+
+// Release is an Istio minor release, like 1.6
+type Release struct {
+	Major int
+	Minor int
+}
+
+// ParseRelease reads the release of an Istio version, like 1.6 or 1.6.3
+func ParseRelease(version string) (Release, bool) {
+	parts := releaseExpr.FindStringSubmatch(version)
+	if parts == nil {
+		return Release{}, false
+	}
+	major, _ := strconv.Atoi(parts[1])
+	minor, _ := strconv.Atoi(parts[2])
+	return Release{Major: major, Minor: minor}, true
+}
+
+// Before checks whether the release is older than the given version. Unparseable versions are never reached.
+func (r Release) Before(version string) bool {
+	other, ok := ParseRelease(version)
+	if !ok {
+		return true
+	}
+	return r.Major < other.Major || (r.Major == other.Major && r.Minor < other.Minor)
+}
+
+func (r Release) String() string {
+	return fmt.Sprintf("%d.%d", r.Major, r.Minor)
+}
+
+// Profile describes the life cycle of an Istio resource type or of one of its fields.
+// Empty versions mean the change never happened.
+type Profile struct {
+	Introduced  string
+	Deprecated  string
+	Removed     string
+	Replacement string
+}
+
+// FieldProfile is the Profile of a field of an Istio resource type
+type FieldProfile struct {
+	Profile
+	ObjectType string
+	// Path of the field under the spec, arrays are marked with [], i.e. http[]/mirror_percent
+	Path string
+	// Value of the field the profile applies to, any value when empty
+	Value string
+}
+
+// ResourceProfiles are the Profiles of the Istio resource types, indexed by object type
+var ResourceProfiles = map[string]Profile{
+	"authorizationpolicy":   {Introduced: "1.4"},
+	"peerauthentication":    {Introduced: "1.5"},
+	"requestauthentication": {Introduced: "1.5"},
+	"workloadentry":         {Introduced: "1.6"},
+	"policy":                {Deprecated: "1.5", Removed: "1.6", Replacement: "PeerAuthentication and RequestAuthentication"},
+	"meshpolicy":            {Deprecated: "1.5", Removed: "1.6", Replacement: "PeerAuthentication and RequestAuthentication"},
+	"rbacconfig":            {Deprecated: "1.1", Removed: "1.6", Replacement: "AuthorizationPolicy"},
+	"clusterrbacconfig":     {Deprecated: "1.4", Removed: "1.6", Replacement: "AuthorizationPolicy"},
+	"servicerole":           {Deprecated: "1.4", Removed: "1.6", Replacement: "AuthorizationPolicy"},
+	"servicerolebinding":    {Deprecated: "1.4", Removed: "1.6", Replacement: "AuthorizationPolicy"},
+}
+
+// FieldProfiles are the Profiles of the fields added or deprecated after the resource types were introduced
+var FieldProfiles = []FieldProfile{
+	{ObjectType: "virtualservice", Path: "http[]/mirror_percent", Profile: Profile{Deprecated: "1.5", Replacement: "mirrorPercentage"}},
+	{ObjectType: "virtualservice", Path: "http[]/mirrorPercent", Profile: Profile{Deprecated: "1.5", Replacement: "mirrorPercentage"}},
+	{ObjectType: "virtualservice", Path: "http[]/mirrorPercentage", Profile: Profile{Introduced: "1.5"}},
+	{ObjectType: "virtualservice", Path: "http[]/fault/delay/percent", Profile: Profile{Deprecated: "1.1", Replacement: "percentage"}},
+	{ObjectType: "virtualservice", Path: "http[]/match[]/withoutHeaders", Profile: Profile{Introduced: "1.6"}},
+	{ObjectType: "serviceentry", Path: "workloadSelector", Profile: Profile{Introduced: "1.6"}},
+	{ObjectType: "authorizationpolicy", Path: "action", Value: "DENY", Profile: Profile{Introduced: "1.5"}},
+	{ObjectType: "authorizationpolicy", Path: "action", Value: "CUSTOM", Profile: Profile{Introduced: "1.9"}},
+	{ObjectType: "authorizationpolicy", Path: "action", Value: "AUDIT", Profile: Profile{Introduced: "1.10"}},
+	{ObjectType: "authorizationpolicy", Path: "provider", Profile: Profile{Introduced: "1.9"}},
+}
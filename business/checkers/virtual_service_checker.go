@@ -62,6 +62,11 @@ func (in VirtualServiceChecker) runChecks(virtualService kubernetes.IstioObject)
 	enabledCheckers := []Checker{
 		virtual_services.RouteChecker{Route: virtualService},
 		virtual_services.SubsetPresenceChecker{Namespace: in.Namespace, Namespaces: in.Namespaces.GetNames(), DestinationRules: in.DestinationRules, VirtualService: virtualService},
+		virtual_services.ShadowedRouteChecker{VirtualService: virtualService},
+		virtual_services.RegexChecker{VirtualService: virtualService},
+		virtual_services.RetriesChecker{VirtualService: virtualService},
+		virtual_services.MirrorChecker{VirtualService: virtualService},
+		virtual_services.FaultChecker{VirtualService: virtualService},
 	}
 
 	for _, checker := range enabledCheckers {
@@ -0,0 +1,61 @@
+package virtual_services
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type FaultChecker struct {
+	VirtualService kubernetes.IstioObject
+}
+
+// Check warns about delays and aborts injected in all the requests of a route,
+// which usually are test configurations left behind.
+// When no percentage is defined, the fault is injected in all the requests.
+func (f FaultChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	routes, ok := f.VirtualService.GetSpec()["http"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	for routeIdx, rt := range routes {
+		route, ok := rt.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		fault, ok := route["fault"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, kind := range []string{"delay", "abort"} {
+			injection, ok := fault[kind].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if faultPercentage(injection) >= 100 {
+				check := models.Build("virtualservices.fault.fullpercentage", fmt.Sprintf("spec/http[%d]/fault/%s", routeIdx, kind))
+				checks = append(checks, &check)
+			}
+		}
+	}
+
+	return checks, valid
+}
+
+func faultPercentage(injection map[string]interface{}) float64 {
+	if percentage, ok := injection["percentage"].(map[string]interface{}); ok {
+		if value, ok := numberValue(percentage["value"]); ok {
+			return value
+		}
+		return 0
+	}
+	// Deprecated integer field
+	if percent, ok := numberValue(injection["percent"]); ok {
+		return percent
+	}
+	return 100
+}
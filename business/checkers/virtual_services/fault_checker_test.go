@@ -0,0 +1,46 @@
+package virtual_services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestPartialFaultInjection(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{"fault": map[string]interface{}{
+			"delay": map[string]interface{}{"fixedDelay": "7s", "percentage": map[string]interface{}{"value": float64(10)}},
+			"abort": map[string]interface{}{"httpStatus": float64(500), "percent": float64(5)},
+		}},
+	})
+
+	checks, valid := FaultChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestFullFaultInjection(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{"fault": map[string]interface{}{
+			"delay": map[string]interface{}{"fixedDelay": "7s", "percentage": map[string]interface{}{"value": float64(100)}},
+		}},
+		// No percentage means all the requests
+		map[string]interface{}{"fault": map[string]interface{}{
+			"abort": map[string]interface{}{"httpStatus": float64(500)},
+		}},
+	})
+
+	checks, valid := FaultChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Len(checks, 2)
+	assert.Equal(models.CheckMessage("virtualservices.fault.fullpercentage"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/http[0]/fault/delay", checks[0].Path)
+	assert.Equal("spec/http[1]/fault/abort", checks[1].Path)
+}
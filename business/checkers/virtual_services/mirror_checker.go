@@ -0,0 +1,39 @@
+package virtual_services
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type MirrorChecker struct {
+	VirtualService kubernetes.IstioObject
+}
+
+// Check warns about mirror destinations without subset, as the mirrored requests are sent to every version of the service
+func (m MirrorChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	routes, ok := m.VirtualService.GetSpec()["http"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	for routeIdx, rt := range routes {
+		route, ok := rt.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		mirror, ok := route["mirror"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if subset, ok := mirror["subset"].(string); !ok || subset == "" {
+			check := models.Build("virtualservices.mirror.nosubset", fmt.Sprintf("spec/http[%d]/mirror", routeIdx))
+			checks = append(checks, &check)
+		}
+	}
+
+	return checks, valid
+}
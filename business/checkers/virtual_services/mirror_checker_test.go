@@ -0,0 +1,37 @@
+package virtual_services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestMirrorWithSubset(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{"mirror": map[string]interface{}{"host": "reviews", "subset": "v2"}},
+	})
+
+	checks, valid := MirrorChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestMirrorWithoutSubset(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{},
+		map[string]interface{}{"mirror": map[string]interface{}{"host": "reviews"}},
+	})
+
+	checks, valid := MirrorChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("virtualservices.mirror.nosubset"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/http[1]/mirror", checks[0].Path)
+}
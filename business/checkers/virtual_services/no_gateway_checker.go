@@ -39,7 +39,7 @@ func (s NoGatewayChecker) ValidateVirtualServiceGateways(spec map[string]interfa
 					}
 
 					// Gateways should be using <namespace>/<gateway>
-					checkNomenclature(gate, index, validations)
+					checkNomenclature(gate, index, s.VirtualService, validations)
 
 					hostname := kubernetes.ParseGatewayAsHost(gate, namespace, clusterName).String()
 					for gw := range s.GatewayNames {
@@ -59,10 +59,33 @@ func (s NoGatewayChecker) ValidateVirtualServiceGateways(spec map[string]interfa
 	return valid
 }
 
-func checkNomenclature(gateway string, index int, validations *[]*models.IstioCheck) {
+func checkNomenclature(gateway string, index int, virtualService kubernetes.IstioObject, validations *[]*models.IstioCheck) {
 	if strings.Contains(gateway, ".") {
 		path := fmt.Sprintf("spec/gateways[%d]", index)
 		validation := models.Build("virtualservices.gateway.oldnomenclature", path)
+		validation.Fix = nomenclatureFix(index, virtualService)
 		*validations = append(*validations, &validation)
 	}
 }
+
+// nomenclatureFix proposes replacing the gateway at index by its <gateway namespace>/<gateway name> form
+func nomenclatureFix(index int, virtualService kubernetes.IstioObject) *models.IstioCheckFix {
+	if virtualService == nil {
+		return nil
+	}
+	meta := virtualService.GetObjectMeta()
+	gateways, ok := virtualService.DeepCopyIstioObject().GetSpec()["gateways"].([]interface{})
+	if !ok {
+		return nil
+	}
+	gateway, ok := gateways[index].(string)
+	if !ok {
+		return nil
+	}
+	gwHost := kubernetes.ParseGatewayAsHost(gateway, meta.Namespace, meta.ClusterName)
+	gateways[index] = gwHost.Namespace + "/" + gwHost.Service
+
+	return models.BuildPatchFix(fmt.Sprintf("Replace gateway %s by %s", gateway, gateways[index]),
+		models.BuildKey("virtualservice", meta.Name, meta.Namespace),
+		map[string]interface{}{"spec": map[string]interface{}{"gateways": gateways}})
+}
@@ -93,6 +93,8 @@ func TestFoundGatewayTwoPartNaming(t *testing.T) {
 	assert.Len(validations, 1)
 	assert.Equal(models.Unknown, validations[0].Severity)
 	assert.Equal(models.CheckMessage("virtualservices.gateway.oldnomenclature"), validations[0].Message)
+	assert.NotNil(validations[0].Fix)
+	assert.Equal(`{"spec":{"gateways":["test/my-gateway","mesh"]}}`, validations[0].Fix.Patch)
 }
 
 func TestFQDNFoundGateway(t *testing.T) {
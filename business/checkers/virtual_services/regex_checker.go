@@ -0,0 +1,85 @@
+package virtual_services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type RegexChecker struct {
+	VirtualService kubernetes.IstioObject
+}
+
+// Check validates the regular expressions of the http match requests.
+// Envoy uses the RE2 syntax by default, the same supported by the Go regexp package.
+func (r RegexChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	routes, ok := r.VirtualService.GetSpec()["http"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	for routeIdx, rt := range routes {
+		route, ok := rt.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		matches, ok := route["match"].([]interface{})
+		if !ok {
+			continue
+		}
+		for matchIdx, m := range matches {
+			match, ok := m.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			matchPath := fmt.Sprintf("spec/http[%d]/match[%d]", routeIdx, matchIdx)
+
+			for _, field := range []string{"uri", "scheme", "method", "authority"} {
+				if !validStringMatch(match[field]) {
+					valid = false
+					check := models.Build("virtualservices.match.invalidregex", fmt.Sprintf("%s/%s/regex", matchPath, field))
+					checks = append(checks, &check)
+				}
+			}
+
+			for _, field := range []string{"headers", "queryParams", "withoutHeaders"} {
+				stringMatches, ok := match[field].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				names := make([]string, 0, len(stringMatches))
+				for name := range stringMatches {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					if !validStringMatch(stringMatches[name]) {
+						valid = false
+						check := models.Build("virtualservices.match.invalidregex", fmt.Sprintf("%s/%s/%s/regex", matchPath, field, name))
+						checks = append(checks, &check)
+					}
+				}
+			}
+		}
+	}
+
+	return checks, valid
+}
+
+func validStringMatch(stringMatch interface{}) bool {
+	sm, ok := stringMatch.(map[string]interface{})
+	if !ok {
+		return true
+	}
+	regex, ok := sm["regex"].(string)
+	if !ok {
+		return true
+	}
+	_, err := regexp.Compile(regex)
+	return err == nil
+}
@@ -0,0 +1,47 @@
+package virtual_services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestValidRegex(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{
+			"uri":     map[string]interface{}{"regex": "/api/v[0-9]+/.*"},
+			"headers": map[string]interface{}{"end-user": map[string]interface{}{"regex": "^(jason|ana)$"}},
+		}}},
+	})
+
+	checks, valid := RegexChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestInvalidRegex(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/api"}}}},
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{
+			"uri":         map[string]interface{}{"regex": "/api/(v1"},
+			"queryParams": map[string]interface{}{"id": map[string]interface{}{"regex": "[0-9"}},
+			// Perl lookaheads are not supported by RE2
+			"headers": map[string]interface{}{"end-user": map[string]interface{}{"regex": "^(?!admin).*"}},
+		}}},
+	})
+
+	checks, valid := RegexChecker{VirtualService: vs}.Check()
+	assert.False(valid)
+	assert.Len(checks, 3)
+	assert.Equal(models.CheckMessage("virtualservices.match.invalidregex"), checks[0].Message)
+	assert.Equal(models.ErrorSeverity, checks[0].Severity)
+	assert.Equal("spec/http[1]/match[0]/uri/regex", checks[0].Path)
+	assert.Equal("spec/http[1]/match[0]/headers/end-user/regex", checks[1].Path)
+	assert.Equal("spec/http[1]/match[0]/queryParams/id/regex", checks[2].Path)
+}
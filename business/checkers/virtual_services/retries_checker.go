@@ -0,0 +1,80 @@
+package virtual_services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type RetriesChecker struct {
+	VirtualService kubernetes.IstioObject
+}
+
+// Check validates that all the retry attempts of a http route can be done before the route timeout expires
+func (r RetriesChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	routes, ok := r.VirtualService.GetSpec()["http"].([]interface{})
+	if !ok {
+		return checks, valid
+	}
+
+	for routeIdx, rt := range routes {
+		route, ok := rt.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		timeout, ok := parseDuration(route["timeout"])
+		if !ok {
+			continue
+		}
+		retries, ok := route["retries"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		perTryTimeout, ok := parseDuration(retries["perTryTimeout"])
+		if !ok {
+			continue
+		}
+		attempts, ok := numberValue(retries["attempts"])
+		if !ok {
+			continue
+		}
+
+		if time.Duration(attempts)*perTryTimeout > timeout {
+			check := models.Build("virtualservices.retries.exceedtimeout", fmt.Sprintf("spec/http[%d]/retries", routeIdx))
+			checks = append(checks, &check)
+		}
+	}
+
+	return checks, valid
+}
+
+func parseDuration(value interface{}) (time.Duration, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// numberValue reads a number from a spec parsed either from json (float64) or yaml (int64, float64)
+func numberValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
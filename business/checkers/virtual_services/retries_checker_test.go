@@ -0,0 +1,46 @@
+package virtual_services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestRetriesWithinTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{
+			"timeout": "10s",
+			"retries": map[string]interface{}{"attempts": float64(3), "perTryTimeout": "2s"},
+		},
+		// No timeout defined
+		map[string]interface{}{
+			"retries": map[string]interface{}{"attempts": float64(3), "perTryTimeout": "5s"},
+		},
+	})
+
+	checks, valid := RetriesChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestRetriesExceedTimeout(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{
+			"timeout": "3s",
+			"retries": map[string]interface{}{"attempts": int64(3), "perTryTimeout": "1500ms"},
+		},
+	})
+
+	checks, valid := RetriesChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal(models.CheckMessage("virtualservices.retries.exceedtimeout"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/http[0]/retries", checks[0].Path)
+}
@@ -32,11 +32,38 @@ func (route RouteChecker) Check() ([]*models.IstioCheck, bool) {
 	return checks, valid
 }
 
+// singleWeightFix proposes removing the weight of the only destination of a route, which receives all the requests
+func singleWeightFix(virtualService kubernetes.IstioObject, kind string, routeIdx int) *models.IstioCheckFix {
+	routes, ok := virtualService.DeepCopyIstioObject().GetSpec()[kind].([]interface{})
+	if !ok {
+		return nil
+	}
+	r, ok := routes[routeIdx].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	destinations, ok := r["route"].([]interface{})
+	if !ok || len(destinations) != 1 {
+		return nil
+	}
+	destination, ok := destinations[0].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	delete(destination, "weight")
+
+	meta := virtualService.GetObjectMeta()
+	return models.BuildPatchFix(fmt.Sprintf("Remove the weight of spec/%s[%d]/route[0]", kind, routeIdx),
+		models.BuildKey("virtualservice", meta.Name, meta.Namespace),
+		map[string]interface{}{"spec": map[string]interface{}{kind: routes}})
+}
+
 func (route RouteChecker) checkRoutesFor(kind string) ([]*models.IstioCheck, bool) {
 	validations := make([]*models.IstioCheck, 0)
 	valid := true
 
-	http := route.Route.GetSpec()[kind]
+	virtualService := route.Route
+	http := virtualService.GetSpec()[kind]
 	if http == nil {
 		return validations, valid
 	}
@@ -69,6 +96,7 @@ func (route RouteChecker) checkRoutesFor(kind string) ([]*models.IstioCheck, boo
 				valid = true
 				path := fmt.Sprintf("spec/%s[%d]/route[%d]/weight", kind, routeIdx, 0)
 				validation := models.Build("virtualservices.route.singleweight", path)
+				validation.Fix = singleWeightFix(virtualService, kind, routeIdx)
 				validations = append(validations, &validation)
 			}
 		}
@@ -35,6 +35,14 @@ func TestServiceMultipleChecks(t *testing.T) {
 	assert.Equal(validations[0].Message, models.CheckMessage("virtualservices.route.singleweight"))
 	assert.Equal(validations[0].Severity, models.WarningSeverity)
 	assert.Equal(validations[0].Path, "spec/http[0]/route[0]/weight")
+
+	// The fix removes the weight of the single destination
+	fix := validations[0].Fix
+	assert.NotNil(fix)
+	assert.Equal(models.BuildKey("virtualservice", "reviews-multiple", "test"), fix.Target)
+	assert.Empty(fix.Object)
+	assert.Contains(fix.Patch, `"subset":"v1"`)
+	assert.NotContains(fix.Patch, "weight")
 }
 
 func TestVSWithRepeatingSubsets(t *testing.T) {
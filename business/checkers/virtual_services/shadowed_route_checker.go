@@ -0,0 +1,74 @@
+package virtual_services
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+type ShadowedRouteChecker struct {
+	VirtualService kubernetes.IstioObject
+}
+
+// Check marks the routes that follow a catch-all route: routes are evaluated in order
+// and the first one matching a request is applied, so the following ones are never reached.
+func (s ShadowedRouteChecker) Check() ([]*models.IstioCheck, bool) {
+	checks, valid := make([]*models.IstioCheck, 0), true
+
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, ok := s.VirtualService.GetSpec()[protocol].([]interface{})
+		if !ok {
+			continue
+		}
+
+		catchAll := false
+		for routeIdx, r := range routes {
+			route, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if catchAll {
+				check := models.Build("virtualservices.route.unreachable", fmt.Sprintf("spec/%s[%d]", protocol, routeIdx))
+				checks = append(checks, &check)
+				continue
+			}
+			catchAll = isCatchAllRoute(route)
+		}
+	}
+
+	return checks, valid
+}
+
+// isCatchAllRoute checks whether the route matches all the requests:
+// it has no match conditions or one of them is empty or only matches the "/" uri prefix.
+func isCatchAllRoute(route map[string]interface{}) bool {
+	matches, ok := route["match"].([]interface{})
+	if !ok || len(matches) == 0 {
+		return true
+	}
+
+	for _, m := range matches {
+		match, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		catchAll := true
+		for field, value := range match {
+			switch field {
+			case "name":
+			case "uri":
+				uri, ok := value.(map[string]interface{})
+				if !ok || len(uri) != 1 || uri["prefix"] != "/" {
+					catchAll = false
+				}
+			default:
+				catchAll = false
+			}
+		}
+		if catchAll {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package virtual_services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestCatchAllRouteLast(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/api"}}}},
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/"}}}},
+	})
+
+	checks, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Empty(checks)
+}
+
+func TestRoutesAfterCatchAll(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{"headers": map[string]interface{}{"end-user": map[string]interface{}{"exact": "jason"}}}}},
+		map[string]interface{}{},
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/api"}}}},
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"exact": "/login"}}}},
+	})
+
+	checks, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Len(checks, 2)
+	assert.Equal(models.CheckMessage("virtualservices.route.unreachable"), checks[0].Message)
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+	assert.Equal("spec/http[2]", checks[0].Path)
+	assert.Equal("spec/http[3]", checks[1].Path)
+}
+
+func TestRoutesAfterCatchAllPrefix(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := fakeVirtualServiceWithHttp([]interface{}{
+		map[string]interface{}{"match": []interface{}{
+			map[string]interface{}{"uri": map[string]interface{}{"exact": "/login"}},
+			map[string]interface{}{"name": "all", "uri": map[string]interface{}{"prefix": "/"}},
+		}},
+		map[string]interface{}{"match": []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/api"}}}},
+	})
+
+	checks, valid := ShadowedRouteChecker{VirtualService: vs}.Check()
+	assert.True(valid)
+	assert.Len(checks, 1)
+	assert.Equal("spec/http[1]", checks[0].Path)
+}
+
+func fakeVirtualServiceWithHttp(routes []interface{}) kubernetes.IstioObject {
+	vs := data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})
+	vs.GetSpec()["http"] = routes
+	return vs
+}
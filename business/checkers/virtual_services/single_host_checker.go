@@ -18,7 +18,12 @@ func (s SingleHostChecker) Check() models.IstioValidations {
 	hostCounter := make(map[string]map[string]map[string]map[string][]*kubernetes.IstioObject)
 	validations := models.IstioValidations{}
 
+	delegates := s.getDelegates()
 	for _, vs := range s.VirtualServices {
+		// Hosts of delegate VirtualServices are ignored, their routes are merged into the root VirtualService
+		if delegates[models.BuildKey("virtualservice", vs.GetObjectMeta().Name, vs.GetObjectMeta().Namespace)] {
+			continue
+		}
 		for _, host := range s.getHosts(vs) {
 			storeHost(hostCounter, vs, host)
 		}
@@ -59,6 +64,39 @@ func (s SingleHostChecker) Check() models.IstioValidations {
 	return validations
 }
 
+// getDelegates returns the keys of the VirtualServices referenced as delegate from any http route
+func (s SingleHostChecker) getDelegates() map[models.IstioValidationKey]bool {
+	delegates := map[models.IstioValidationKey]bool{}
+
+	for _, vs := range s.VirtualServices {
+		routes, ok := vs.GetSpec()["http"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, r := range routes {
+			route, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			delegate, ok := route["delegate"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, ok := delegate["name"].(string)
+			if !ok {
+				continue
+			}
+			namespace, ok := delegate["namespace"].(string)
+			if !ok || namespace == "" {
+				namespace = vs.GetObjectMeta().Namespace
+			}
+			delegates[models.BuildKey("virtualservice", name, namespace)] = true
+		}
+	}
+
+	return delegates
+}
+
 func multipleVirtualServiceCheck(virtualService kubernetes.IstioObject, validations models.IstioValidations, references []*kubernetes.IstioObject) {
 	virtualServiceName := virtualService.GetObjectMeta().Name
 	key := models.IstioValidationKey{Name: virtualServiceName, Namespace: virtualService.GetObjectMeta().Namespace, ObjectType: "virtualservice"}
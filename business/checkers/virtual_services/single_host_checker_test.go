@@ -522,3 +522,24 @@ func presentReferences(t *testing.T, validation models.IstioValidation, serviceN
 		assert.Contains(validation.References, refKey)
 	}
 }
+
+func TestRepeatingHostWithDelegate(t *testing.T) {
+	root := buildVirtualService("virtual-1", "reviews")
+	root.GetSpec()["http"] = []interface{}{
+		map[string]interface{}{
+			"match":    []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": "/api"}}},
+			"delegate": map[string]interface{}{"name": "virtual-2", "namespace": "bookinfo"},
+		},
+	}
+	vss := []kubernetes.IstioObject{
+		root,
+		buildVirtualService("virtual-2", "reviews"),
+	}
+
+	validations := SingleHostChecker{
+		Namespace:       "bookinfo",
+		VirtualServices: vss,
+	}.Check()
+
+	emptyValidationTest(t, validations)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 )
@@ -68,6 +69,7 @@ func (checker SubsetPresenceChecker) Check() ([]*models.IstioCheck, bool) {
 				if !checker.subsetPresent(host, subset) {
 					path := fmt.Sprintf("spec/%s[%d]/route[%d]/destination", protocol, routeIdx, destWeightIdx)
 					validation := models.Build("virtualservices.subsetpresent.subsetnotfound", path)
+					validation.Fix = checker.subsetFix(host, subset)
 					validations = append(validations, &validation)
 				}
 			}
@@ -92,6 +94,43 @@ func (checker SubsetPresenceChecker) subsetPresent(host string, subset string) b
 	return false
 }
 
+// subsetFix proposes adding the subset, selecting the pods by the configured version label, to the DestinationRule of the host.
+// A new DestinationRule is proposed when there is none for the host.
+func (checker SubsetPresenceChecker) subsetFix(host string, subset string) *models.IstioCheckFix {
+	newSubset := map[string]interface{}{
+		"name":   subset,
+		"labels": map[string]interface{}{config.Get().IstioLabels.VersionLabelName: subset},
+	}
+
+	if destinationRules, ok := checker.getDestinationRules(host); ok {
+		dr := destinationRules[0]
+		subsets := make([]interface{}, 0)
+		if drSubsets, ok := dr.GetSpec()["subsets"].([]interface{}); ok {
+			subsets = append(subsets, drSubsets...)
+		}
+		subsets = append(subsets, newSubset)
+		target := models.BuildKey("destinationrule", dr.GetObjectMeta().Name, dr.GetObjectMeta().Namespace)
+		description := fmt.Sprintf("Add subset %s to DestinationRule %s", subset, dr.GetObjectMeta().Name)
+		return models.BuildPatchFix(description, target, map[string]interface{}{
+			"spec": map[string]interface{}{"subsets": subsets},
+		})
+	}
+
+	namespace := checker.VirtualService.GetObjectMeta().Namespace
+	name := kubernetes.GetHost(host, namespace, checker.VirtualService.GetObjectMeta().ClusterName, checker.Namespaces).Service
+	target := models.BuildKey("destinationrule", name, namespace)
+	description := fmt.Sprintf("Create DestinationRule %s with subset %s", name, subset)
+	return models.BuildCreateFix(description, target, map[string]interface{}{
+		"apiVersion": kubernetes.ApiNetworkingVersion,
+		"kind":       kubernetes.DestinationRuleType,
+		"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+		"spec": map[string]interface{}{
+			"host":    host,
+			"subsets": []interface{}{newSubset},
+		},
+	})
+}
+
 func (checker SubsetPresenceChecker) getDestinationRules(virtualServiceHost string) ([]kubernetes.IstioObject, bool) {
 	drs := make([]kubernetes.IstioObject, 0, len(checker.DestinationRules))
 
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
@@ -39,6 +41,51 @@ func TestSubsetsNotFound(t *testing.T) {
 	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-1.yaml", t)
 }
 
+func TestSubsetsNotFoundFix(t *testing.T) {
+	assert := assert.New(t)
+	vals, _ := subsetPresenceCheckerPrep("subset-presence-no-matching-subsets-1.yaml", t)
+
+	// The missing subset is appended to the existing DestinationRule
+	assert.Len(vals, 2)
+	fix := vals[0].Fix
+	assert.NotNil(fix)
+	assert.Equal(models.BuildKey("destinationrule", "testrule", "bookinfo"), fix.Target)
+	assert.Empty(fix.Object)
+	assert.Contains(fix.Patch, `{"labels":{"version":"v2"},"name":"v2"}`)
+	assert.Contains(fix.Patch, `{"labels":{"version":"not-v1"},"name":"not-v1"}`)
+}
+
+func TestSubsetsNotFoundCreateFix(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vals, _ := SubsetPresenceChecker{
+		Namespace:      "bookinfo",
+		Namespaces:     []string{"bookinfo"},
+		VirtualService: data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", 100), data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})),
+	}.Check()
+
+	// A new DestinationRule is proposed when the host has none
+	assert.Len(vals, 1)
+	fix := vals[0].Fix
+	assert.NotNil(fix)
+	assert.Equal(models.BuildKey("destinationrule", "reviews", "bookinfo"), fix.Target)
+	assert.Empty(fix.Patch)
+	assert.Contains(fix.Object, `"kind":"DestinationRule"`)
+	assert.Contains(fix.Object, `"subsets":[{"labels":{"version":"v1"},"name":"v1"}]`)
+
+	// Subsets select the pods by the configured version label
+	conf.IstioLabels.VersionLabelName = "app.kubernetes.io/version"
+	config.Set(conf)
+	vals, _ = SubsetPresenceChecker{
+		Namespace:      "bookinfo",
+		Namespaces:     []string{"bookinfo"},
+		VirtualService: data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", 100), data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})),
+	}.Check()
+	assert.Contains(vals[0].Fix.Object, `"subsets":[{"labels":{"app.kubernetes.io/version":"v1"},"name":"v1"}]`)
+}
+
 func TestSubsetsNotFoundSVCNS(t *testing.T) {
 	testSubsetPresenceValidationsFound("subset-presence-no-matching-subsets-2.yaml", t)
 }
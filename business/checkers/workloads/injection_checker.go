@@ -0,0 +1,53 @@
+package workloads
+
+import (
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+type InjectionChecker struct {
+	Namespace models.Namespace
+	Workload  models.WorkloadListItem
+	Pods      []core_v1.Pod
+}
+
+// Check reports the workloads opting out of the sidecar injection enabled for their namespace.
+// The opt-out is usually temporary, and leaves the workload out of the mesh policies.
+func (i InjectionChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+	if !IsInjectionEnabled(i.Namespace) {
+		return validations, true
+	}
+
+	conf := config.Get()
+	path := ""
+	if i.Workload.IstioInjectionAnnotation != nil && !*i.Workload.IstioInjectionAnnotation {
+		path = "spec/template/metadata/annotations"
+	} else {
+		// Newer Istio versions read the opt-out from the pod labels too
+		for _, pod := range i.Pods {
+			if pod.Labels[conf.ExternalServices.Istio.IstioInjectionAnnotation] == "false" {
+				path = "spec/template/metadata/labels"
+				break
+			}
+		}
+	}
+
+	if path != "" {
+		validation := models.Build("workload.injection.disabled", path)
+		validations = append(validations, &validation)
+	}
+
+	return validations, true
+}
+
+// IsInjectionEnabled checks whether the namespace is labeled for sidecar injection, either by the injection label or by a revision
+func IsInjectionEnabled(namespace models.Namespace) bool {
+	if namespace.Labels[config.Get().IstioLabels.InjectionLabelName] == "enabled" {
+		return true
+	}
+	_, revision := namespace.Labels["istio.io/rev"]
+	return revision
+}
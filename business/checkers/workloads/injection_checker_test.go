@@ -0,0 +1,62 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestInjectionDisabledByAnnotation(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	disabled := false
+	validations, valid := InjectionChecker{
+		Namespace: models.Namespace{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled"}},
+		Workload:  models.WorkloadListItem{Name: "reviews-v1", IstioInjectionAnnotation: &disabled},
+	}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workload.injection.disabled"), validations[0].Message)
+	assert.Equal("spec/template/metadata/annotations", validations[0].Path)
+}
+
+func TestInjectionDisabledByLabel(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	pod := fakePod("reviews-v1-1", false, nil, nil)
+	pod.Labels["sidecar.istio.io/inject"] = "false"
+
+	validations, valid := InjectionChecker{
+		Namespace: models.Namespace{Name: "bookinfo", Labels: map[string]string{"istio.io/rev": "1-8"}},
+		Workload:  models.WorkloadListItem{Name: "reviews-v1"},
+		Pods:      []core_v1.Pod{pod},
+	}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal("spec/template/metadata/labels", validations[0].Path)
+}
+
+func TestInjectionDisabledInNamespaceWithoutInjection(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	disabled := false
+	validations, valid := InjectionChecker{
+		Namespace: models.Namespace{Name: "bookinfo", Labels: map[string]string{"istio-injection": "disabled"}},
+		Workload:  models.WorkloadListItem{Name: "reviews-v1", IstioInjectionAnnotation: &disabled},
+	}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
@@ -0,0 +1,35 @@
+package workloads
+
+import (
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+type LabelsChecker struct {
+	Workload models.WorkloadListItem
+}
+
+// Check validates that the workloads in the mesh have the app and version labels.
+// Istio telemetry and Kiali rely on them to identify the applications and their versions.
+func (l LabelsChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+	if !l.Workload.IstioSidecar {
+		return validations, true
+	}
+
+	conf := config.Get()
+	missing := make([]string, 0, 2)
+	if !l.Workload.AppLabel {
+		missing = append(missing, conf.IstioLabels.AppLabelName)
+	}
+	if !l.Workload.VersionLabel {
+		missing = append(missing, conf.IstioLabels.VersionLabelName)
+	}
+	for _, label := range missing {
+		validation := models.Build("workload.labels.missing", "spec/template/metadata/labels")
+		validation.Detail = label
+		validations = append(validations, &validation)
+	}
+
+	return validations, true
+}
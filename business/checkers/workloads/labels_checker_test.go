@@ -0,0 +1,55 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestWorkloadWithLabels(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := LabelsChecker{
+		Workload: models.WorkloadListItem{Name: "reviews-v1", IstioSidecar: true, AppLabel: true, VersionLabel: true},
+	}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestWorkloadWithoutLabels(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := LabelsChecker{
+		Workload: models.WorkloadListItem{Name: "reviews-v1", IstioSidecar: true, AppLabel: true},
+	}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workload.labels.missing"), validations[0].Message)
+	assert.Equal("version", validations[0].Detail)
+	assert.Equal(models.WarningSeverity, validations[0].Severity)
+	assert.Equal("spec/template/metadata/labels", validations[0].Path)
+
+	// One check is reported per missing label
+	validations, _ = LabelsChecker{
+		Workload: models.WorkloadListItem{Name: "reviews-v1", IstioSidecar: true},
+	}.Check()
+	assert.Len(validations, 2)
+	assert.Equal("app", validations[0].Detail)
+	assert.Equal("version", validations[1].Detail)
+
+	// Workloads out of the mesh are not checked
+	validations, valid = LabelsChecker{
+		Workload: models.WorkloadListItem{Name: "reviews-v1"},
+	}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
@@ -0,0 +1,86 @@
+package workloads
+
+import (
+	"fmt"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/models"
+)
+
+// ProxyUid is the UID of the sidecar proxy. Istio excludes the traffic of this UID from the redirection to the proxy.
+const ProxyUid = 1337
+
+type PodsChecker struct {
+	Namespace models.Namespace
+	Workload  models.WorkloadListItem
+	Pods      []core_v1.Pod
+}
+
+// Check validates the spec of the pods of the workloads in the mesh: pods using the host network can't be injected,
+// and application containers can't run with the UID of the proxy.
+// Only the first pod reporting each problem is checked, as all the pods of a workload share the same template.
+func (p PodsChecker) Check() ([]*models.IstioCheck, bool) {
+	validations := make([]*models.IstioCheck, 0)
+	valid := true
+
+	// The host network only matters when the workload is meant to be injected
+	checkHostNetwork := p.Workload.IstioSidecar || IsInjectionEnabled(p.Namespace)
+	hostNetwork, proxyUid := false, false
+	for _, pod := range p.Pods {
+		if checkHostNetwork && pod.Spec.HostNetwork && !hostNetwork {
+			hostNetwork = true
+			validation := models.Build("workload.pod.hostnetwork", "spec/template/spec/hostNetwork")
+			validations = append(validations, &validation)
+		}
+
+		if proxyUid {
+			continue
+		}
+		mPod := models.Pod{}
+		mPod.Parse(&pod)
+		if !mPod.HasIstioSidecar() {
+			continue
+		}
+		for _, path := range proxyUidPaths(pod, mPod.IstioContainers) {
+			proxyUid = true
+			valid = false
+			validation := models.Build("workload.pod.proxyuid", path)
+			validations = append(validations, &validation)
+		}
+	}
+
+	return validations, valid
+}
+
+// proxyUidPaths returns the paths of the application containers running with the UID of the proxy,
+// set on the container or inherited from the pod security context
+func proxyUidPaths(pod core_v1.Pod, istioContainers []*models.ContainerInfo) []string {
+	paths := make([]string, 0)
+
+	podUid := pod.Spec.SecurityContext != nil && pod.Spec.SecurityContext.RunAsUser != nil && *pod.Spec.SecurityContext.RunAsUser == ProxyUid
+	for i, c := range pod.Spec.Containers {
+		if isIstioContainer(c.Name, istioContainers) {
+			continue
+		}
+		if c.SecurityContext != nil && c.SecurityContext.RunAsUser != nil {
+			if *c.SecurityContext.RunAsUser == ProxyUid {
+				paths = append(paths, fmt.Sprintf("spec/template/spec/containers[%d]/securityContext/runAsUser", i))
+			}
+		} else if podUid {
+			paths = append(paths, "spec/template/spec/securityContext/runAsUser")
+			break
+		}
+	}
+
+	return paths
+}
+
+func isIstioContainer(name string, istioContainers []*models.ContainerInfo) bool {
+	for _, c := range istioContainers {
+		if c.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package workloads
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func TestValidPods(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	validations, valid := PodsChecker{Pods: []core_v1.Pod{fakePod("reviews-v1-1", true, nil, nil)}}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestHostNetworkPods(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	pod := fakePod("reviews-v1-1", false, nil, nil)
+	pod.Spec.HostNetwork = true
+	injected := models.Namespace{Name: "bookinfo", Labels: map[string]string{"istio-injection": "enabled"}}
+
+	validations, valid := PodsChecker{Namespace: injected, Pods: []core_v1.Pod{pod, pod}}.Check()
+	assert.True(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workload.pod.hostnetwork"), validations[0].Message)
+	assert.Equal("spec/template/spec/hostNetwork", validations[0].Path)
+
+	// A workload with a sidecar is in the mesh whatever its namespace
+	validations, _ = PodsChecker{Workload: models.WorkloadListItem{IstioSidecar: true}, Pods: []core_v1.Pod{pod}}.Check()
+	assert.Len(validations, 1)
+
+	// Out of the mesh, the host network is fine
+	validations, valid = PodsChecker{Namespace: models.Namespace{Name: "default"}, Pods: []core_v1.Pod{pod}}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestProxyUidContainer(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	uid := int64(ProxyUid)
+	validations, valid := PodsChecker{Pods: []core_v1.Pod{fakePod("reviews-v1-1", true, nil, &uid)}}.Check()
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal(models.CheckMessage("workload.pod.proxyuid"), validations[0].Message)
+	assert.Equal(models.ErrorSeverity, validations[0].Severity)
+	assert.Equal("spec/template/spec/containers[0]/securityContext/runAsUser", validations[0].Path)
+
+	// Without sidecar, the UID doesn't affect the traffic
+	validations, valid = PodsChecker{Pods: []core_v1.Pod{fakePod("reviews-v1-1", false, nil, &uid)}}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func TestProxyUidPod(t *testing.T) {
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	assert := assert.New(t)
+
+	uid, otherUid := int64(ProxyUid), int64(1000)
+	validations, valid := PodsChecker{Pods: []core_v1.Pod{fakePod("reviews-v1-1", true, &uid, nil)}}.Check()
+	assert.False(valid)
+	assert.Len(validations, 1)
+	assert.Equal("spec/template/spec/securityContext/runAsUser", validations[0].Path)
+
+	// The container security context takes precedence
+	validations, valid = PodsChecker{Pods: []core_v1.Pod{fakePod("reviews-v1-1", true, &uid, &otherUid)}}.Check()
+	assert.True(valid)
+	assert.Empty(validations)
+}
+
+func fakePod(name string, withSidecar bool, podUid, containerUid *int64) core_v1.Pod {
+	conf := config.NewConfig()
+
+	pod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{"app": "reviews", "version": "v1"},
+			Annotations: map[string]string{},
+		},
+		Spec: core_v1.PodSpec{
+			Containers: []core_v1.Container{{Name: "reviews"}},
+		},
+	}
+	if withSidecar {
+		pod.Annotations[conf.ExternalServices.Istio.IstioSidecarAnnotation] = "{\"version\":\"\",\"initContainers\":[\"istio-init\"],\"containers\":[\"istio-proxy\"],\"volumes\":[\"istio-envoy\",\"istio-certs\"]}"
+		pod.Spec.Containers = append(pod.Spec.Containers, core_v1.Container{Name: "istio-proxy", SecurityContext: &core_v1.SecurityContext{RunAsUser: int64Ptr(ProxyUid)}})
+	}
+	if podUid != nil {
+		pod.Spec.SecurityContext = &core_v1.PodSecurityContext{RunAsUser: podUid}
+	}
+	if containerUid != nil {
+		pod.Spec.Containers[0].SecurityContext = &core_v1.SecurityContext{RunAsUser: containerUid}
+	}
+	return pod
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
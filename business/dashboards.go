@@ -191,12 +191,17 @@ func (in *DashboardsService) GetDashboard(params models.DashboardQuery, template
 		return nil, err
 	}
 
+	// Labels filters are written as is in the queries
+	if _, err := in.buildMatchers(params.Namespace, params.LabelsFilters); err != nil {
+		return nil, err
+	}
 	filters := in.buildLabels(params.Namespace, params.LabelsFilters)
 	aggLabels := append(params.AdditionalLabels, models.ConvertAggregations(dashboard.Spec)...)
 	if len(aggLabels) == 0 {
 		// Prevent null in json
 		aggLabels = []models.Aggregation{}
 	}
+	variables := in.resolveVariables(promClient, dashboard.Spec.Variables, params)
 
 	wg := sync.WaitGroup{}
 	wg.Add(len(dashboard.Spec.Items) + 1)
@@ -233,7 +238,14 @@ func (in *DashboardsService) GetDashboard(params models.DashboardQuery, template
 			for _, ref := range metrics {
 				var converted []models.Metric
 				var err error
-				if chart.DataType == v1alpha1.Raw {
+				if ref.Expression != "" {
+					var query string
+					query, err = in.buildExpression(ref.Expression, variables, params)
+					if err == nil {
+						metric := promClient.FetchQueryRange(query, &params.RangeQuery)
+						converted, err = models.ConvertMetric(ref.DisplayName, metric, conversionParams)
+					}
+				} else if chart.DataType == v1alpha1.Raw {
 					aggregator := params.RawDataAggregator
 					if chart.Aggregator != "" {
 						aggregator = chart.Aggregator
@@ -278,6 +290,7 @@ func (in *DashboardsService) GetDashboard(params models.DashboardQuery, template
 		Charts:        filledCharts,
 		Aggregations:  aggLabels,
 		ExternalLinks: externalLinks,
+		Variables:     variables,
 	}, nil
 }
 
@@ -0,0 +1,170 @@
+package business
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+
+	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+var variableRefRegexp = regexp.MustCompile(`\$(\{[A-Za-z_]\w*\}|[A-Za-z_]\w*)`)
+
+// resolveVariables computes the options and selected values of the dashboard variables.
+// Options are the values of the variable label among the series of the namespace (matching the labels filters),
+// selected values are the ones from the query that are among the options, or the default value, or the first option.
+func (in *DashboardsService) resolveVariables(promClient prometheus.ClientInterface, spec []v1alpha1.MonitoringDashboardVariable, params models.DashboardQuery) []models.Variable {
+	matchers, matchersErr := in.buildMatchers(params.Namespace, params.LabelsFilters)
+	variables := make([]models.Variable, len(spec))
+	for i, v := range spec {
+		variables[i] = models.Variable{
+			Name:        v.Name,
+			DisplayName: v.DisplayName,
+			Multi:       v.Multi,
+			Options:     []string{},
+			Values:      []string{},
+		}
+		if variables[i].DisplayName == "" {
+			variables[i].DisplayName = v.Name
+		}
+		if options, err := in.fetchVariableOptions(promClient, v, matchers, matchersErr, params); err != nil {
+			log.Errorf("cannot load options of dashboard variable %s: %v", v.Name, err)
+		} else {
+			variables[i].Options = options
+		}
+		if values := allowedValues(params.Variables[v.Name], variables[i].Options); len(values) > 0 {
+			variables[i].Values = values
+		} else if v.Default != "" {
+			variables[i].Values = []string{v.Default}
+		} else if len(variables[i].Options) > 0 {
+			variables[i].Values = []string{variables[i].Options[0]}
+		}
+		if !v.Multi && len(variables[i].Values) > 1 {
+			variables[i].Values = variables[i].Values[:1]
+		}
+	}
+	return variables
+}
+
+// allowedValues returns the values that are among the options, the other ones are dropped
+func allowedValues(values, options []string) []string {
+	allowed := []string{}
+	for _, value := range values {
+		for _, option := range options {
+			if value == option {
+				allowed = append(allowed, value)
+				break
+			}
+		}
+	}
+	return allowed
+}
+
+// fetchVariableOptions returns the values of the variable label among the series of its metric, restricted by the matchers
+func (in *DashboardsService) fetchVariableOptions(promClient prometheus.ClientInterface, v v1alpha1.MonitoringDashboardVariable, matchers []*labels.Matcher, matchersErr error, params models.DashboardQuery) ([]string, error) {
+	if matchersErr != nil {
+		return nil, matchersErr
+	}
+	// The metric is optional, but must be a bare metric name so that it cannot escape the matchers
+	if v.Metric != "" && !model.IsValidMetricName(model.LabelValue(v.Metric)) {
+		return nil, fmt.Errorf("invalid metric name %q", v.Metric)
+	}
+	if !model.LabelName(v.Label).IsValid() {
+		return nil, fmt.Errorf("invalid label name %q", v.Label)
+	}
+	selector := (&promql.VectorSelector{Name: v.Metric, LabelMatchers: matchers}).String()
+	return promClient.GetLabelValuesForSeries(v.Label, []string{selector}, params.Start, params.End)
+}
+
+// buildExpression substitutes the variables in a chart expression, then scopes all its selectors
+// to the namespace and labels filters of the dashboard
+func (in *DashboardsService) buildExpression(expression string, variables []models.Variable, params models.DashboardQuery) (string, error) {
+	values := map[string]string{
+		"namespace":     escapeLabelValue(params.Namespace),
+		"rate_interval": params.RateInterval,
+	}
+	for _, v := range variables {
+		if v.Multi {
+			quoted := make([]string, len(v.Values))
+			for i, value := range v.Values {
+				quoted[i] = regexp.QuoteMeta(value)
+			}
+			values[v.Name] = escapeLabelValue(strings.Join(quoted, "|"))
+		} else {
+			values[v.Name] = escapeLabelValue(strings.Join(v.Values, ""))
+		}
+	}
+	var unknown []string
+	substituted := variableRefRegexp.ReplaceAllStringFunc(expression, func(ref string) string {
+		name := strings.Trim(ref, "${}")
+		if value, ok := values[name]; ok {
+			return value
+		}
+		unknown = append(unknown, ref)
+		return ref
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("unknown variables in expression: %s", strings.Join(unknown, ", "))
+	}
+	matchers, err := in.buildMatchers(params.Namespace, params.LabelsFilters)
+	if err != nil {
+		return "", err
+	}
+	return scopeExpression(substituted, matchers)
+}
+
+// buildMatchers returns the label matchers enforced on every selector of the chart expressions.
+// Label names are validated, as the matchers are also written in the queries of the other charts.
+func (in *DashboardsService) buildMatchers(namespace string, labelsFilters map[string]string) ([]*labels.Matcher, error) {
+	namespaceLabel := in.namespaceLabel
+	if namespaceLabel == "" {
+		namespaceLabel = defaultNamespaceLabel
+	}
+	matchers := []*labels.Matcher{{Type: labels.MatchEqual, Name: namespaceLabel, Value: namespace}}
+	keys := make([]string, 0, len(labelsFilters))
+	for k := range labelsFilters {
+		if !model.LabelName(k).IsValid() {
+			return nil, fmt.Errorf("invalid label name %q in labels filters", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		matchers = append(matchers, &labels.Matcher{Type: labels.MatchEqual, Name: k, Value: labelsFilters[k]})
+	}
+	return matchers, nil
+}
+
+// escapeLabelValue escapes a value substituted in a string literal of an expression.
+// Quotes are escaped in hexadecimal, which is valid whatever the quotes of the literal.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\x22`, `'`, `\x27`, "`", `\x60`).Replace(value)
+}
+
+// scopeExpression parses a PromQL expression and adds the given matchers to every selector of its syntax tree.
+// Since matchers are ANDed, an expression cannot select series that don't match them,
+// whatever other matchers it already has.
+func scopeExpression(expression string, matchers []*labels.Matcher) (string, error) {
+	expr, err := promql.ParseExpr(expression)
+	if err != nil {
+		return "", err
+	}
+	promql.Inspect(expr, func(node promql.Node, _ []promql.Node) error {
+		switch n := node.(type) {
+		case *promql.VectorSelector:
+			n.LabelMatchers = append(append([]*labels.Matcher{}, matchers...), n.LabelMatchers...)
+		case *promql.MatrixSelector:
+			n.LabelMatchers = append(append([]*labels.Matcher{}, matchers...), n.LabelMatchers...)
+		}
+		return nil
+	})
+	return expr.String(), nil
+}
@@ -0,0 +1,151 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
+	"github.com/kiali/kiali/models"
+)
+
+func TestScopeExpression(t *testing.T) {
+	assert := assert.New(t)
+	matchers := []*labels.Matcher{{Type: labels.MatchEqual, Name: "namespace", Value: "ns"}}
+
+	cases := map[string]string{
+		`my_metric`:                 `my_metric{namespace="ns"}`,
+		`my_metric{app="foo"}`:      `my_metric{app="foo",namespace="ns"}`,
+		`{__name__=~"jvm_.*"}`:      `{__name__=~"jvm_.*",namespace="ns"}`,
+		`my_metric{namespace="x"}`:  `my_metric{namespace="ns",namespace="x"}`,
+		`up offset 1h > bool 0.5e3`: `up{namespace="ns"} offset 1h > bool 500`,
+		`sum by (pod, app) (rate(a{b="}"}[1m])) / on(pod) group_left(app) b`:     `sum by(pod, app) (rate(a{b="}",namespace="ns"}[1m])) / on(pod) group_left(app) b{namespace="ns"}`,
+		`label_replace(up, "dst", "$1", "src", "(.*)") or vector(1)`:             `label_replace(up{namespace="ns"}, "dst", "$1", "src", "(.*)") or vector(1)`,
+		`histogram_quantile(0.99, sum without(le_x)(rate(h_bucket{a="1"}[5m])))`: `histogram_quantile(0.99, sum without(le_x) (rate(h_bucket{a="1",namespace="ns"}[5m])))`,
+		// Comments and strings can't hide selectors
+		"up{a=\"b\" # \"\n} or secret_metric # \"}":                                `up{a="b",namespace="ns"} or secret_metric{namespace="ns"}`,
+		"up{a='}', b=`\"}`} or secret_metric":                                      `up{a="}",b="\"}",namespace="ns"} or secret_metric{namespace="ns"}`,
+		"sum(rate(a[5m])) # or secret_metric":                                      `sum(rate(a{namespace="ns"}[5m]))`,
+		`max_over_time(a[5m] offset 1m) or count(b{c="d"} and on() (e or sum(f)))`: `max_over_time(a{namespace="ns"}[5m] offset 1m) or count(b{c="d",namespace="ns"} and on() (e{namespace="ns"} or sum(f{namespace="ns"})))`,
+	}
+	for expr, expected := range cases {
+		scoped, err := scopeExpression(expr, matchers)
+		assert.Nil(err, expr)
+		assert.Equal(expected, scoped)
+	}
+
+	for _, invalid := range []string{`my_metric{app="foo"`, `my_metric}`, `rate(a[5m)`, `a{b="c}`, `up{a="b" # "}`} {
+		_, err := scopeExpression(invalid, matchers)
+		assert.NotNil(err, invalid)
+	}
+}
+
+func TestBuildExpression(t *testing.T) {
+	assert := assert.New(t)
+	service, _, _ := setupService()
+
+	query := models.DashboardQuery{
+		Namespace:     "my-namespace",
+		LabelsFilters: map[string]string{"app": "my-app"},
+	}
+	query.FillDefaults()
+	query.RateInterval = "5m"
+	variables := []models.Variable{
+		{Name: "pod", Values: []string{`my"pod`}},
+		{Name: "area", Multi: true, Values: []string{"heap", "non.heap"}},
+	}
+
+	expr, err := service.buildExpression(`sum(rate(jvm_gc{pod="$pod",area=~"${area}"}[$rate_interval]))`, variables, query)
+
+	assert.Nil(err)
+	assert.Equal(`sum(rate(jvm_gc{app="my-app",area=~"heap|non\\.heap",kubernetes_namespace="my-namespace",pod="my\"pod"}[5m]))`, expr)
+
+	// Values can't close the string literal they are substituted in, whatever its quotes
+	variables[0].Values = []string{"a'\"`} or secret_metric{b=\"c"}
+	for _, pattern := range []string{`"$pod"`, `'$pod'`, "`$pod`"} {
+		expr, err = service.buildExpression(`jvm_gc{pod=`+pattern+`}`, variables, query)
+		assert.Nil(err, pattern)
+		parsed, _ := promql.ParseExpr(expr)
+		assert.IsType(&promql.VectorSelector{}, parsed, expr)
+	}
+
+	_, err = service.buildExpression(`rate(jvm_gc{pod="$pod"}[1m]) / ${unknown}`, variables, query)
+	assert.EqualError(err, "unknown variables in expression: ${unknown}")
+
+	// Labels filters cannot inject selectors
+	query.LabelsFilters = map[string]string{`a="b"} or istio_requests_total{c!`: "d"}
+	_, err = service.buildExpression(`up`, variables, query)
+	assert.NotNil(err)
+}
+
+func TestResolveVariablesRejectsInvalidMetric(t *testing.T) {
+	assert := assert.New(t)
+	service, _, prom := setupService()
+
+	query := models.DashboardQuery{Namespace: "my-namespace"}
+	query.FillDefaults()
+	variables := service.resolveVariables(prom, []v1alpha1.MonitoringDashboardVariable{
+		{Name: "pod", Label: "pod", Metric: `{__name__="istio_requests_total"} #`},
+	}, query)
+
+	assert.Len(variables, 1)
+	assert.Empty(variables[0].Options)
+	prom.AssertNotCalled(t, "GetLabelValuesForSeries", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetDashboardWithVariables(t *testing.T) {
+	assert := assert.New(t)
+
+	service, k8s, prom := setupService()
+	k8s.On("GetDashboard", "my-namespace", "dashboard1").Return(&v1alpha1.MonitoringDashboard{
+		ObjectMeta: v1.ObjectMeta{Name: "dashboard1"},
+		Spec: v1alpha1.MonitoringDashboardSpec{
+			Title: "Dashboard 1",
+			Variables: []v1alpha1.MonitoringDashboardVariable{
+				{Name: "pod", Label: "pod", Metric: "jvm_gc"},
+				{Name: "area", DisplayName: "Area", Label: "area", Multi: true, Default: "heap"},
+			},
+			Items: []v1alpha1.MonitoringDashboardItem{
+				{
+					Chart: v1alpha1.MonitoringDashboardChart{
+						Name: "GC",
+						Unit: "seconds",
+						Metrics: []v1alpha1.MonitoringDashboardMetric{
+							{DisplayName: "GC time", Expression: `rate(jvm_gc{pod="$pod",area=~"$area"}[1m])`},
+						},
+						Thresholds: []v1alpha1.MonitoringDashboardThreshold{
+							{Value: 0.5, Label: "Slow GC", Severity: "warning"},
+						},
+					},
+				},
+			},
+		},
+	}, nil)
+
+	query := models.DashboardQuery{
+		Namespace: "my-namespace",
+		Variables: map[string][]string{"area": {"heap", "nonheap", `"} or secret_metric{a="`}},
+	}
+	query.FillDefaults()
+	prom.On("GetLabelValuesForSeries", "pod", []string{`jvm_gc{kubernetes_namespace="my-namespace"}`}, mock.Anything, mock.Anything).Return([]string{"pod-a", "pod-b"}, nil)
+	prom.On("GetLabelValuesForSeries", "area", []string{`{kubernetes_namespace="my-namespace"}`}, mock.Anything, mock.Anything).Return([]string{"heap", "nonheap"}, nil)
+	prom.MockQueryRange(`rate(jvm_gc{area=~"heap|nonheap",kubernetes_namespace="my-namespace",pod="pod-a"}[1m])`, &query.RangeQuery, 7)
+
+	dashboard, err := service.GetDashboard(query, "dashboard1")
+
+	assert.Nil(err)
+	assert.Len(dashboard.Variables, 2)
+	assert.Equal("pod", dashboard.Variables[0].DisplayName)
+	assert.Equal([]string{"pod-a", "pod-b"}, dashboard.Variables[0].Options)
+	assert.Equal([]string{"pod-a"}, dashboard.Variables[0].Values)
+	assert.Equal([]string{"heap", "nonheap"}, dashboard.Variables[1].Values)
+	assert.Len(dashboard.Charts, 1)
+	assert.Empty(dashboard.Charts[0].Error)
+	assert.Len(dashboard.Charts[0].Metrics, 1)
+	assert.Equal(float64(7), dashboard.Charts[0].Metrics[0].Datapoints[0].Value)
+	assert.Equal([]models.Threshold{{Value: 0.5, Label: "Slow GC", Severity: "warning"}}, dashboard.Charts[0].Thresholds)
+}
@@ -0,0 +1,557 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
+	"github.com/kiali/kiali/models"
+)
+
+// Subset of the Grafana dashboard JSON model used for the conversion, covering both the legacy "rows" schema
+// and the "panels" schema of Grafana 5+
+type grafanaDashboard struct {
+	Title      string         `json:"title"`
+	Panels     []grafanaPanel `json:"panels"`
+	Rows       []grafanaRow   `json:"rows"`
+	Templating struct {
+		List []grafanaVariable `json:"list"`
+	} `json:"templating"`
+}
+
+type grafanaRow struct {
+	Title    string         `json:"title"`
+	Collapse bool           `json:"collapse"`
+	Panels   []grafanaPanel `json:"panels"`
+}
+
+type grafanaPanel struct {
+	Title     string `json:"title"`
+	Type      string `json:"type"`
+	Collapsed bool   `json:"collapsed"`
+	GridPos   struct {
+		W int `json:"w"`
+	} `json:"gridPos"`
+	Span         float64          `json:"span"`
+	Bars         bool             `json:"bars"`
+	LibraryPanel *json.RawMessage `json:"libraryPanel"`
+	Targets      []grafanaTarget  `json:"targets"`
+	Panels       []grafanaPanel   `json:"panels"`
+	Yaxes        []struct {
+		Format string `json:"format"`
+	} `json:"yaxes"`
+	FieldConfig struct {
+		Defaults struct {
+			Unit       string   `json:"unit"`
+			Min        *float64 `json:"min"`
+			Max        *float64 `json:"max"`
+			Thresholds struct {
+				Steps []struct {
+					Value *float64 `json:"value"`
+					Color string   `json:"color"`
+				} `json:"steps"`
+			} `json:"thresholds"`
+			Custom struct {
+				DrawStyle   string  `json:"drawStyle"`
+				FillOpacity float64 `json:"fillOpacity"`
+			} `json:"custom"`
+		} `json:"defaults"`
+	} `json:"fieldConfig"`
+}
+
+type grafanaTarget struct {
+	Expr         string `json:"expr"`
+	LegendFormat string `json:"legendFormat"`
+	RefID        string `json:"refId"`
+	Hide         bool   `json:"hide"`
+}
+
+type grafanaVariable struct {
+	Name       string          `json:"name"`
+	Label      string          `json:"label"`
+	Type       string          `json:"type"`
+	Query      json.RawMessage `json:"query"`
+	Multi      bool            `json:"multi"`
+	IncludeAll bool            `json:"includeAll"`
+	Current    struct {
+		Value json.RawMessage `json:"value"`
+	} `json:"current"`
+}
+
+// Grafana units mapped to the base units of Kiali charts, with the scale of the values
+var grafanaUnits = map[string]struct {
+	unit  string
+	scale float64
+}{
+	"s":           {unit: "seconds", scale: 1},
+	"ms":          {unit: "seconds", scale: 0.001},
+	"µs":          {unit: "seconds", scale: 0.000001},
+	"ns":          {unit: "seconds", scale: 0.000000001},
+	"bytes":       {unit: "bytes", scale: 1},
+	"decbytes":    {unit: "bytes", scale: 1},
+	"kbytes":      {unit: "bytes", scale: 1024},
+	"mbytes":      {unit: "bytes", scale: 1024 * 1024},
+	"Bps":         {unit: "bitrate", scale: 8},
+	"bps":         {unit: "bitrate", scale: 1},
+	"binBps":      {unit: "bitrate", scale: 8},
+	"reqps":       {unit: "ops", scale: 1},
+	"ops":         {unit: "ops", scale: 1},
+	"percent":     {unit: "%", scale: 1},
+	"percentunit": {unit: "%", scale: 100},
+}
+
+// Panels types rendered as time series
+var grafanaChartPanels = map[string]bool{
+	"graph":      true,
+	"timeseries": true,
+	"stat":       true,
+	"singlestat": true,
+	"gauge":      true,
+	"bargauge":   true,
+	"barchart":   true,
+}
+
+var (
+	grafanaVariableRefRegexp = regexp.MustCompile(`\$\{([A-Za-z_]\w*)(?::\w+)?\}|\[\[([A-Za-z_]\w*)(?::\w+)?\]\]|\$([A-Za-z_]\w*)`)
+	grafanaLabelValuesRegexp = regexp.MustCompile(`^label_values\(\s*(?:([a-zA-Z_:][\w:]*)?\s*(?:\{[^}]*\})?\s*,)?\s*(\w+)\s*\)$`)
+	simpleSumRegexp          = regexp.MustCompile(`(?s)^sum\s*(?:by\s*\(([\w\s,]*)\)\s*)?\((.*)\)$`)
+	simpleSumBySuffixRegexp  = regexp.MustCompile(`(?s)^sum\s*\((.*)\)\s*by\s*\(([\w\s,]*)\)$`)
+	simpleHistogramRegexp    = regexp.MustCompile(`(?s)^histogram_quantile\s*\(\s*[\d.]+\s*,(.*)\)$`)
+	simpleRateRegexp         = regexp.MustCompile(`^i?rate\s*\(\s*([a-zA-Z_:][\w:]*)\s*(?:\{([^{}]*)\})?\s*\[[^\[\]]+\]\s*\)$`)
+	simpleSelectorRegexp     = regexp.MustCompile(`^([a-zA-Z_:][\w:]*)\s*(?:\{([^{}]*)\})?$`)
+	simpleMatcherRegexp      = regexp.MustCompile(`(\w+)\s*(?:=~|!=|!~|=)\s*"((?:[^"\\]|\\.)*)"`)
+	kubernetesNameRegexp     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// Keywords and operators of PromQL that must not be taken for metric names
+var promQLKeywords = map[string]bool{
+	"and": true, "or": true, "unless": true, "bool": true, "offset": true, "inf": true, "nan": true,
+	"sum": true, "min": true, "max": true, "avg": true, "group": true, "stddev": true, "stdvar": true,
+	"count": true, "count_values": true, "bottomk": true, "topk": true, "quantile": true,
+}
+
+// Labels holding the namespace, which Kiali enforces on every query
+var grafanaNamespaceLabels = map[string]bool{
+	"namespace":            true,
+	"kubernetes_namespace": true,
+}
+
+type grafanaConverter struct {
+	// Grafana variable names, mapped to their reference in Kiali expressions
+	variables map[string]string
+	skipped   []models.SkippedGrafanaItem
+}
+
+// ConvertGrafanaDashboard converts a Grafana dashboard JSON into a MonitoringDashboard resource.
+// Panels are mapped to charts, PromQL targets to metrics with aggregations when they are simple enough, or to
+// expressions otherwise, and query variables to dashboard variables.
+// The Grafana items that cannot be converted are reported in the result.
+func ConvertGrafanaDashboard(raw []byte, opts models.GrafanaConversionOptions) (*models.GrafanaDashboardConversion, error) {
+	var wrapper struct {
+		Dashboard *json.RawMessage `json:"dashboard"`
+	}
+	// Dashboards from the Grafana API are wrapped along with their metadata
+	if err := json.Unmarshal(raw, &wrapper); err == nil && wrapper.Dashboard != nil {
+		raw = *wrapper.Dashboard
+	}
+	var grafana grafanaDashboard
+	if err := json.Unmarshal(raw, &grafana); err != nil {
+		return nil, fmt.Errorf("invalid Grafana dashboard: %v", err)
+	}
+	if len(grafana.Panels) == 0 && len(grafana.Rows) == 0 {
+		return nil, fmt.Errorf("invalid Grafana dashboard: no panels found")
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = strings.Trim(kubernetesNameRegexp.ReplaceAllString(strings.ToLower(grafana.Title), "-"), "-")
+		if name == "" {
+			name = "grafana-dashboard"
+		}
+	}
+	runtime := opts.Runtime
+	if runtime == "" {
+		runtime = grafana.Title
+	}
+
+	converter := grafanaConverter{
+		variables: map[string]string{
+			"__rate_interval": "$rate_interval",
+			"__interval":      "$rate_interval",
+		},
+		skipped: []models.SkippedGrafanaItem{},
+	}
+	dashboard := v1alpha1.MonitoringDashboard{
+		TypeMeta: meta_v1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "MonitoringDashboard",
+		},
+		ObjectMeta: meta_v1.ObjectMeta{Name: name},
+		Spec: v1alpha1.MonitoringDashboardSpec{
+			Title:     grafana.Title,
+			Runtime:   runtime,
+			Items:     []v1alpha1.MonitoringDashboardItem{},
+			Variables: converter.convertVariables(grafana.Templating.List),
+		},
+	}
+	for _, panel := range grafana.Panels {
+		dashboard.Spec.Items = append(dashboard.Spec.Items, converter.convertPanel(panel, false)...)
+	}
+	for _, row := range grafana.Rows {
+		for _, panel := range row.Panels {
+			dashboard.Spec.Items = append(dashboard.Spec.Items, converter.convertPanel(panel, row.Collapse)...)
+		}
+	}
+
+	return &models.GrafanaDashboardConversion{
+		Dashboard: dashboard,
+		Skipped:   converter.skipped,
+	}, nil
+}
+
+func (in *grafanaConverter) skip(kind, title, expression, reason string) {
+	in.skipped = append(in.skipped, models.SkippedGrafanaItem{Kind: kind, Title: title, Expression: expression, Reason: reason})
+}
+
+// convertVariables converts the Grafana "label_values" query variables. Namespace variables are mapped to the
+// built-in $namespace, and interval variables to the built-in $rate_interval.
+func (in *grafanaConverter) convertVariables(list []grafanaVariable) []v1alpha1.MonitoringDashboardVariable {
+	variables := []v1alpha1.MonitoringDashboardVariable{}
+	for _, v := range list {
+		switch v.Type {
+		case "datasource":
+			// Not applicable: Kiali queries its configured Prometheus
+			continue
+		case "interval":
+			in.variables[v.Name] = "$rate_interval"
+			continue
+		case "query":
+		default:
+			in.skip(models.GrafanaVariable, v.Name, "", fmt.Sprintf("variables of type %q are not supported", v.Type))
+			continue
+		}
+		query := grafanaVariableQuery(v.Query)
+		match := grafanaLabelValuesRegexp.FindStringSubmatch(query)
+		if match == nil {
+			in.skip(models.GrafanaVariable, v.Name, query, "only label_values queries are supported")
+			continue
+		}
+		if grafanaNamespaceLabels[match[2]] || v.Name == "namespace" {
+			in.variables[v.Name] = "$namespace"
+			continue
+		}
+		in.variables[v.Name] = "$" + v.Name
+		variables = append(variables, v1alpha1.MonitoringDashboardVariable{
+			Name:        v.Name,
+			DisplayName: v.Label,
+			Label:       match[2],
+			Metric:      match[1],
+			Multi:       v.Multi || v.IncludeAll,
+			Default:     grafanaVariableDefault(v.Current.Value),
+		})
+	}
+	return variables
+}
+
+// grafanaVariableQuery returns the query of a variable, which is either a string or an object depending on Grafana versions
+func grafanaVariableQuery(raw json.RawMessage) string {
+	var query string
+	if err := json.Unmarshal(raw, &query); err == nil {
+		return strings.TrimSpace(query)
+	}
+	var object struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(raw, &object); err == nil {
+		return strings.TrimSpace(object.Query)
+	}
+	return ""
+}
+
+// grafanaVariableDefault returns the current value of a variable, which is either a string or a list of strings
+func grafanaVariableDefault(raw json.RawMessage) string {
+	var values []string
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		values = []string{value}
+	} else if err := json.Unmarshal(raw, &values); err != nil {
+		return ""
+	}
+	if len(values) == 0 || values[0] == "$__all" || values[0] == "All" {
+		return ""
+	}
+	return values[0]
+}
+
+func (in *grafanaConverter) convertPanel(panel grafanaPanel, collapsed bool) []v1alpha1.MonitoringDashboardItem {
+	if panel.Type == "row" {
+		items := []v1alpha1.MonitoringDashboardItem{}
+		for _, nested := range panel.Panels {
+			items = append(items, in.convertPanel(nested, panel.Collapsed)...)
+		}
+		return items
+	}
+	if panel.LibraryPanel != nil {
+		in.skip(models.GrafanaPanel, panel.Title, "", "library panels are not supported")
+		return nil
+	}
+	if !grafanaChartPanels[panel.Type] {
+		in.skip(models.GrafanaPanel, panel.Title, "", fmt.Sprintf("panels of type %q are not supported", panel.Type))
+		return nil
+	}
+
+	type convertedTarget struct {
+		grafanaTarget
+		simpleTarget
+		simple bool
+	}
+	targets := []convertedTarget{}
+	for _, target := range panel.Targets {
+		if target.Hide {
+			continue
+		}
+		if strings.TrimSpace(target.Expr) == "" {
+			in.skip(models.GrafanaTarget, panel.Title, "", "not a PromQL target")
+			continue
+		}
+		expr, err := in.translateExpression(target.Expr)
+		if err != nil {
+			in.skip(models.GrafanaTarget, panel.Title, target.Expr, err.Error())
+			continue
+		}
+		target.Expr = expr
+		simple, ok := parseSimpleTarget(expr)
+		targets = append(targets, convertedTarget{grafanaTarget: target, simpleTarget: simple, simple: ok})
+	}
+	if len(targets) == 0 {
+		in.skip(models.GrafanaPanel, panel.Title, "", "no convertible PromQL target")
+		return nil
+	}
+
+	chart := in.convertChartSettings(panel)
+	chart.StartCollapsed = collapsed
+	chart.Metrics = []v1alpha1.MonitoringDashboardMetric{}
+
+	// Metrics of a chart share their data type and aggregations, so expressions are used unless all targets agree
+	allSimple := true
+	for _, t := range targets {
+		if !t.simple || t.dataType != targets[0].dataType || strings.Join(t.byLabels, ",") != strings.Join(targets[0].byLabels, ",") {
+			allSimple = false
+			break
+		}
+	}
+	if allSimple {
+		chart.DataType = targets[0].dataType
+		chart.Aggregations = []v1alpha1.MonitoringDashboardAggregation{}
+		for _, label := range targets[0].byLabels {
+			chart.Aggregations = append(chart.Aggregations, v1alpha1.MonitoringDashboardAggregation{Label: label, DisplayName: label})
+		}
+	} else {
+		chart.DataType = v1alpha1.Raw
+	}
+	seen := map[string]bool{}
+	for _, t := range targets {
+		metric := v1alpha1.MonitoringDashboardMetric{DisplayName: grafanaDisplayName(t.grafanaTarget, panel.Title, len(targets))}
+		if allSimple {
+			// Histogram quantiles of the same metric are all rendered from a single Kiali metric
+			if seen[t.metric] {
+				continue
+			}
+			seen[t.metric] = true
+			metric.MetricName = t.metric
+		} else {
+			metric.Expression = t.Expr
+		}
+		chart.Metrics = append(chart.Metrics, metric)
+	}
+	return []v1alpha1.MonitoringDashboardItem{{Chart: chart}}
+}
+
+func (in *grafanaConverter) convertChartSettings(panel grafanaPanel) v1alpha1.MonitoringDashboardChart {
+	defaults := panel.FieldConfig.Defaults
+	chart := v1alpha1.MonitoringDashboardChart{
+		Name:  panel.Title,
+		Spans: 6,
+	}
+	// Grafana grid has 24 columns, or 12 in the legacy schema, while Kiali has 12
+	if panel.GridPos.W > 0 {
+		chart.Spans = (panel.GridPos.W + 1) / 2
+	} else if panel.Span > 0 {
+		chart.Spans = int(math.Ceil(panel.Span))
+	}
+	if chart.Spans > 12 {
+		chart.Spans = 12
+	}
+
+	if panel.Bars || panel.Type == "bargauge" || panel.Type == "barchart" || defaults.Custom.DrawStyle == "bars" {
+		chartType := "bar"
+		chart.ChartType = &chartType
+	} else if defaults.Custom.FillOpacity > 0 {
+		chartType := "area"
+		chart.ChartType = &chartType
+	}
+
+	unit := defaults.Unit
+	if unit == "" && len(panel.Yaxes) > 0 {
+		unit = panel.Yaxes[0].Format
+	}
+	scale := 1.0
+	if mapped, ok := grafanaUnits[unit]; ok {
+		chart.Unit = mapped.unit
+		scale = mapped.scale
+		if scale != 1 {
+			chart.UnitScale = scale
+		}
+	} else if unit != "short" && unit != "none" {
+		chart.Unit = unit
+	}
+
+	chart.Min = grafanaBound(defaults.Min)
+	chart.Max = grafanaBound(defaults.Max)
+	for _, step := range defaults.Thresholds.Steps {
+		// The base step has no value; green steps denote a healthy range
+		if step.Value == nil || strings.Contains(step.Color, "green") {
+			continue
+		}
+		severity := "warning"
+		if strings.Contains(step.Color, "red") {
+			severity = "critical"
+		}
+		chart.Thresholds = append(chart.Thresholds, v1alpha1.MonitoringDashboardThreshold{
+			Value:    *step.Value * scale,
+			Severity: severity,
+		})
+	}
+	return chart
+}
+
+func grafanaBound(value *float64) *int {
+	if value == nil || *value != math.Trunc(*value) {
+		return nil
+	}
+	bound := int(*value)
+	return &bound
+}
+
+func grafanaDisplayName(target grafanaTarget, panelTitle string, count int) string {
+	if target.LegendFormat != "" && target.LegendFormat != "__auto" && !strings.Contains(target.LegendFormat, "{{") {
+		return target.LegendFormat
+	}
+	if count > 1 && target.RefID != "" {
+		return fmt.Sprintf("%s (%s)", panelTitle, target.RefID)
+	}
+	return panelTitle
+}
+
+// translateExpression replaces the Grafana variables of an expression by their Kiali counterpart
+func (in *grafanaConverter) translateExpression(expr string) (string, error) {
+	var unknown []string
+	translated := grafanaVariableRefRegexp.ReplaceAllStringFunc(expr, func(ref string) string {
+		match := grafanaVariableRefRegexp.FindStringSubmatch(ref)
+		name := match[1] + match[2] + match[3]
+		if kiali, ok := in.variables[name]; ok {
+			return kiali
+		}
+		unknown = append(unknown, ref)
+		return ref
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("unsupported variables: %s", strings.Join(unknown, ", "))
+	}
+	return strings.TrimSpace(translated), nil
+}
+
+// simpleTarget is a PromQL target that can be expressed with a metric name, a data type and aggregations
+type simpleTarget struct {
+	dataType string
+	metric   string
+	byLabels []string
+}
+
+// parseSimpleTarget recognizes the expressions of the form sum [by (labels)] (rate(metric{filters}[interval])),
+// sum [by (labels)] (metric{filters}) and histogram_quantile(q, sum by (le, labels) (rate(metric_bucket{filters}[interval]))),
+// where filters are all enforced by Kiali anyway. Kiali always aggregates the simple targets, so the targets without
+// an explicit sum, having a series per label set, are not simple.
+func parseSimpleTarget(expr string) (simpleTarget, bool) {
+	if match := simpleHistogramRegexp.FindStringSubmatch(expr); match != nil {
+		inner, ok := parseSimpleTarget(strings.TrimSpace(match[1]))
+		if !ok || inner.dataType != v1alpha1.Rate || !strings.HasSuffix(inner.metric, "_bucket") {
+			return simpleTarget{}, false
+		}
+		byLabels := []string{}
+		hasLe := false
+		for _, label := range inner.byLabels {
+			if label == "le" {
+				hasLe = true
+			} else {
+				byLabels = append(byLabels, label)
+			}
+		}
+		if !hasLe {
+			return simpleTarget{}, false
+		}
+		return simpleTarget{
+			dataType: v1alpha1.Histogram,
+			metric:   strings.TrimSuffix(inner.metric, "_bucket"),
+			byLabels: byLabels,
+		}, true
+	}
+
+	target := simpleTarget{byLabels: []string{}}
+	var match []string
+	if match = simpleSumBySuffixRegexp.FindStringSubmatch(expr); match != nil && balancedParentheses(match[1]) {
+		match = []string{match[0], match[2], match[1]}
+	} else if match = simpleSumRegexp.FindStringSubmatch(expr); match == nil || !balancedParentheses(match[2]) {
+		return simpleTarget{}, false
+	}
+	for _, label := range strings.Split(match[1], ",") {
+		if label = strings.TrimSpace(label); label != "" && !grafanaNamespaceLabels[label] {
+			target.byLabels = append(target.byLabels, label)
+		}
+	}
+	sort.Strings(target.byLabels)
+	expr = strings.TrimSpace(match[2])
+
+	var matchers string
+	if match := simpleRateRegexp.FindStringSubmatch(expr); match != nil {
+		target.dataType = v1alpha1.Rate
+		target.metric, matchers = match[1], match[2]
+	} else if match := simpleSelectorRegexp.FindStringSubmatch(expr); match != nil && !promQLKeywords[strings.ToLower(match[1])] {
+		target.dataType = v1alpha1.Raw
+		target.metric, matchers = match[1], match[2]
+	} else {
+		return simpleTarget{}, false
+	}
+
+	// Only namespace filters can be dropped, since Kiali applies them; any other filter needs an expression
+	for _, matcher := range simpleMatcherRegexp.FindAllStringSubmatch(matchers, -1) {
+		if !grafanaNamespaceLabels[matcher[1]] && matcher[2] != "$namespace" {
+			return simpleTarget{}, false
+		}
+	}
+	if strings.Trim(simpleMatcherRegexp.ReplaceAllString(matchers, ""), " ,") != "" {
+		return simpleTarget{}, false
+	}
+	return target, true
+}
+
+func balancedParentheses(expr string) bool {
+	depth := 0
+	for _, c := range expr {
+		if c == '(' {
+			depth++
+		} else if c == ')' {
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
@@ -0,0 +1,175 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
+	"github.com/kiali/kiali/models"
+)
+
+const grafanaDashboardJSON = `{
+	"dashboard": {
+		"title": "JVM (Micrometer)",
+		"templating": {
+			"list": [
+				{"name": "datasource", "type": "datasource", "query": "prometheus"},
+				{"name": "ns", "type": "query", "query": "label_values(namespace)"},
+				{"name": "pod", "label": "Pod", "type": "query", "multi": true, "current": {"value": ["$__all"]},
+					"query": {"query": "label_values(jvm_memory_used_bytes{namespace=\"$ns\"}, pod)"}},
+				{"name": "area", "type": "custom", "query": "heap,nonheap"}
+			]
+		},
+		"panels": [
+			{
+				"title": "Request rate", "type": "timeseries", "gridPos": {"w": 12},
+				"fieldConfig": {"defaults": {"unit": "reqps"}},
+				"targets": [{"expr": "sum by (uri, status) (rate(http_server_requests_seconds_count{namespace=\"$ns\"}[$__rate_interval]))", "legendFormat": "{{uri}}"}]
+			},
+			{
+				"title": "Latency", "type": "graph", "gridPos": {"w": 24}, "yaxes": [{"format": "ms"}],
+				"fieldConfig": {"defaults": {"min": 0, "thresholds": {"steps": [{"value": null, "color": "green"}, {"value": 500, "color": "orange"}, {"value": 1000, "color": "red"}]}}},
+				"targets": [
+					{"expr": "histogram_quantile(0.99, sum(rate(http_server_requests_seconds_bucket{namespace=~\"$ns\"}[5m])) by (le))", "refId": "A"},
+					{"expr": "histogram_quantile(0.5, sum(rate(http_server_requests_seconds_bucket{namespace=~\"$ns\"}[5m])) by (le))", "refId": "B"}
+				]
+			},
+			{
+				"title": "Heap", "type": "timeseries", "fieldConfig": {"defaults": {"unit": "bytes", "custom": {"fillOpacity": 10}}},
+				"targets": [
+					{"expr": "sum(jvm_memory_used_bytes{pod=~\"$pod\",area=\"heap\"})", "legendFormat": "Used", "refId": "A"},
+					{"expr": "sum(jvm_memory_max_bytes{pod=~\"$pod\",area=\"heap\"})", "legendFormat": "Max", "refId": "B"},
+					{"expr": "sum(jvm_memory_committed_bytes{area=\"$area\"})", "refId": "C"}
+				]
+			},
+			{"title": "Notes", "type": "text"},
+			{
+				"title": "GC", "type": "row", "collapsed": true,
+				"panels": [
+					{"title": "GC pauses", "type": "timeseries", "targets": [{"expr": "sum(increase(jvm_gc_pause_seconds_count[$__range]))"}]},
+					{"title": "Threads", "type": "stat", "gridPos": {"w": 6}, "targets": [{"expr": "jvm_threads_live_threads"}]}
+				]
+			}
+		]
+	},
+	"meta": {"slug": "jvm-micrometer"}
+}`
+
+func TestConvertGrafanaDashboard(t *testing.T) {
+	assert := assert.New(t)
+
+	conversion, err := ConvertGrafanaDashboard([]byte(grafanaDashboardJSON), models.GrafanaConversionOptions{Runtime: "JVM"})
+	assert.Nil(err)
+
+	dashboard := conversion.Dashboard
+	assert.Equal("monitoring.kiali.io/v1alpha1", dashboard.APIVersion)
+	assert.Equal("MonitoringDashboard", dashboard.Kind)
+	assert.Equal("jvm-micrometer", dashboard.Name)
+	assert.Equal("JVM (Micrometer)", dashboard.Spec.Title)
+	assert.Equal("JVM", dashboard.Spec.Runtime)
+
+	// Namespace variable is the built-in $namespace
+	assert.Equal([]v1alpha1.MonitoringDashboardVariable{
+		{Name: "pod", DisplayName: "Pod", Label: "pod", Metric: "jvm_memory_used_bytes", Multi: true},
+	}, dashboard.Spec.Variables)
+
+	assert.Len(dashboard.Spec.Items, 4)
+
+	rate := dashboard.Spec.Items[0].Chart
+	assert.Equal("Request rate", rate.Name)
+	assert.Equal(v1alpha1.Rate, rate.DataType)
+	assert.Equal("ops", rate.Unit)
+	assert.Equal(6, rate.Spans)
+	assert.Equal([]v1alpha1.MonitoringDashboardMetric{{MetricName: "http_server_requests_seconds_count", DisplayName: "Request rate"}}, rate.Metrics)
+	assert.Equal([]v1alpha1.MonitoringDashboardAggregation{{Label: "status", DisplayName: "status"}, {Label: "uri", DisplayName: "uri"}}, rate.Aggregations)
+
+	latency := dashboard.Spec.Items[1].Chart
+	assert.Equal(v1alpha1.Histogram, latency.DataType)
+	assert.Equal("seconds", latency.Unit)
+	assert.Equal(0.001, latency.UnitScale)
+	assert.Equal(12, latency.Spans)
+	assert.Equal(0, *latency.Min)
+	assert.Nil(latency.Max)
+	assert.Equal([]v1alpha1.MonitoringDashboardMetric{{MetricName: "http_server_requests_seconds", DisplayName: "Latency (A)"}}, latency.Metrics)
+	assert.Equal([]v1alpha1.MonitoringDashboardThreshold{{Value: 0.5, Severity: "warning"}, {Value: 1, Severity: "critical"}}, latency.Thresholds)
+
+	heap := dashboard.Spec.Items[2].Chart
+	assert.Equal("bytes", heap.Unit)
+	assert.Equal("area", *heap.ChartType)
+	assert.Equal([]v1alpha1.MonitoringDashboardMetric{
+		{DisplayName: "Used", Expression: `sum(jvm_memory_used_bytes{pod=~"$pod",area="heap"})`},
+		{DisplayName: "Max", Expression: `sum(jvm_memory_max_bytes{pod=~"$pod",area="heap"})`},
+	}, heap.Metrics)
+
+	threads := dashboard.Spec.Items[3].Chart
+	assert.Equal(v1alpha1.Raw, threads.DataType)
+	assert.Equal(3, threads.Spans)
+	assert.True(threads.StartCollapsed)
+	// Without sum, the series are kept apart with an expression
+	assert.Equal([]v1alpha1.MonitoringDashboardMetric{{Expression: "jvm_threads_live_threads", DisplayName: "Threads"}}, threads.Metrics)
+
+	assert.Equal([]models.SkippedGrafanaItem{
+		{Kind: models.GrafanaVariable, Title: "area", Reason: `variables of type "custom" are not supported`},
+		{Kind: models.GrafanaTarget, Title: "Heap", Expression: `sum(jvm_memory_committed_bytes{area="$area"})`, Reason: "unsupported variables: $area"},
+		{Kind: models.GrafanaPanel, Title: "Notes", Reason: `panels of type "text" are not supported`},
+		{Kind: models.GrafanaTarget, Title: "GC pauses", Expression: "sum(increase(jvm_gc_pause_seconds_count[$__range]))", Reason: "unsupported variables: $__range"},
+		{Kind: models.GrafanaPanel, Title: "GC pauses", Reason: "no convertible PromQL target"},
+	}, conversion.Skipped)
+}
+
+func TestConvertGrafanaDashboardIsScopable(t *testing.T) {
+	assert := assert.New(t)
+	service, _, _ := setupService()
+
+	conversion, err := ConvertGrafanaDashboard([]byte(grafanaDashboardJSON), models.GrafanaConversionOptions{Name: "jvm"})
+	assert.Nil(err)
+	assert.Equal("jvm", conversion.Dashboard.Name)
+
+	// Converted expressions are valid for custom dashboards
+	query := models.DashboardQuery{Namespace: "my-namespace"}
+	query.FillDefaults()
+	variables := []models.Variable{{Name: "pod", Multi: true, Values: []string{"pod-a"}}}
+	for _, item := range conversion.Dashboard.Spec.Items {
+		for _, metric := range item.Chart.Metrics {
+			if metric.Expression != "" {
+				_, err := service.buildExpression(metric.Expression, variables, query)
+				assert.Nil(err, metric.Expression)
+			}
+		}
+	}
+}
+
+func TestParseSimpleTarget(t *testing.T) {
+	assert := assert.New(t)
+
+	simple, ok := parseSimpleTarget(`sum(rate(istio_requests_total{namespace="$namespace"}[$rate_interval])) by (response_code, namespace)`)
+	assert.True(ok)
+	assert.Equal(simpleTarget{dataType: v1alpha1.Rate, metric: "istio_requests_total", byLabels: []string{"response_code"}}, simple)
+
+	simple, ok = parseSimpleTarget(`sum(up)`)
+	assert.True(ok)
+	assert.Equal(simpleTarget{dataType: v1alpha1.Raw, metric: "up", byLabels: []string{}}, simple)
+
+	for _, expr := range []string{
+		`sum(rate(a[1m])) / sum(rate(b[1m]))`,
+		`sum(rate(a{code="500"}[1m]))`,
+		`avg(a)`,
+		`histogram_quantile(0.9, sum(rate(a_bucket[1m])))`,
+		`sum without (pod) (a)`,
+		// Kiali would sum the series of the targets not aggregated
+		`up`,
+		`rate(a{namespace="$namespace"}[1m])`,
+	} {
+		_, ok := parseSimpleTarget(expr)
+		assert.False(ok, expr)
+	}
+}
+
+func TestConvertGrafanaDashboardInvalid(t *testing.T) {
+	_, err := ConvertGrafanaDashboard([]byte(`{"title": "Empty"}`), models.GrafanaConversionOptions{})
+	assert.EqualError(t, err, "invalid Grafana dashboard: no panels found")
+
+	_, err = ConvertGrafanaDashboard([]byte(`[]`), models.GrafanaConversionOptions{})
+	assert.NotNil(t, err)
+}
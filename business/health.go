@@ -1,6 +1,7 @@
 package business
 
 import (
+	"math"
 	"time"
 
 	"github.com/prometheus/common/model"
@@ -29,7 +30,16 @@ func (in *HealthService) GetServiceHealth(namespace, service, rateInterval strin
 	defer promtimer.ObserveNow(&err)
 
 	rqHealth, err := in.getServiceRequestsHealth(namespace, service, rateInterval, queryTime)
-	return models.ServiceHealth{Requests: rqHealth}, err
+	latency, latencyErr := in.getLatencyHealth(namespace, "service", []string{service}, serviceLatencySelectors(namespace, service), rateInterval, queryTime)
+	if latencyErr != nil {
+		log.Errorf("Error fetching latency of service %s/%s: %s", namespace, service, latencyErr)
+	}
+	slos, sloErr := in.businessLayer.SLO.GetServiceSLOs(namespace, service, queryTime)
+	if sloErr != nil {
+		log.Errorf("Error evaluating SLOs of service %s/%s: %s", namespace, service, sloErr)
+	}
+	anomalies := in.getAnomalies(namespace, "service", service, rateInterval, queryTime)[service]
+	return models.ServiceHealth{Requests: rqHealth, Latency: latency[service], SLOs: slos, Anomalies: anomalies}, err
 }
 
 // GetAppHealth returns an app health from just Namespace and app name (thus, it fetches data from K8S and Prometheus)
@@ -50,7 +60,14 @@ func (in *HealthService) GetAppHealth(namespace, app, rateInterval string, query
 		return models.AppHealth{}, err
 	}
 
-	return in.getAppHealth(namespace, app, rateInterval, queryTime, ws)
+	health, err := in.getAppHealth(namespace, app, rateInterval, queryTime, ws)
+	slos, sloErr := in.businessLayer.SLO.GetAppSLOs(namespace, app, queryTime)
+	if sloErr != nil {
+		log.Errorf("Error evaluating SLOs of app %s/%s: %s", namespace, app, sloErr)
+	}
+	health.SLOs = slos
+	health.Anomalies = in.getAnomalies(namespace, "app", app, rateInterval, queryTime)[app]
+	return health, err
 }
 
 func (in *HealthService) getAppHealth(namespace, app, rateInterval string, queryTime time.Time, ws models.Workloads) (models.AppHealth, error) {
@@ -71,10 +88,21 @@ func (in *HealthService) getAppHealth(namespace, app, rateInterval string, query
 		rate, err := in.getAppRequestsHealth(namespace, app, rateInterval, queryTime)
 		health.Requests = rate
 		errRate = err
+
+		// Same label than the namespace app health, as for the request rates of apps
+		latency, err := in.getLatencyHealth(namespace, "app", []string{app}, itemLatencySelectors(namespace, app, "canonical_service"), rateInterval, queryTime)
+		if err != nil {
+			log.Errorf("Error fetching latency of app %s/%s: %s", namespace, app, err)
+		}
+		health.Latency = latency[app]
 	}
 
 	// Deployment status
-	health.WorkloadStatuses = ws.CastWorkloadStatuses()
+	var restarts models.ContainerRestarts
+	if hasPods(ws) {
+		restarts = in.getContainerRestarts(namespace, rateInterval, queryTime)
+	}
+	health.WorkloadStatuses = ws.CastWorkloadStatuses(healthSince(rateInterval, queryTime), restarts)
 
 	return health, errRate
 }
@@ -90,7 +118,11 @@ func (in *HealthService) GetWorkloadHealth(namespace, workload, workloadType, ra
 		return models.WorkloadHealth{}, err
 	}
 
-	status := w.CastWorkloadStatus()
+	var restarts models.ContainerRestarts
+	if len(w.Pods) > 0 {
+		restarts = in.getContainerRestarts(namespace, rateInterval, queryTime)
+	}
+	status := w.CastWorkloadStatus(healthSince(rateInterval, queryTime), restarts)
 
 	// Perf: do not bother fetching request rate if workload has no sidecar
 	if !w.IstioSidecar {
@@ -113,9 +145,20 @@ func (in *HealthService) GetWorkloadHealth(namespace, workload, workloadType, ra
 
 	// Add Telemetry info
 	rate, err := in.getWorkloadRequestsHealth(namespace, workload, rateInterval, queryTime)
+	latency, latencyErr := in.getLatencyHealth(namespace, "workload", []string{workload}, itemLatencySelectors(namespace, workload, "workload"), rateInterval, queryTime)
+	if latencyErr != nil {
+		log.Errorf("Error fetching latency of workload %s/%s: %s", namespace, workload, latencyErr)
+	}
+	slos, sloErr := in.businessLayer.SLO.GetWorkloadSLOs(namespace, workload, queryTime)
+	if sloErr != nil {
+		log.Errorf("Error evaluating SLOs of workload %s/%s: %s", namespace, workload, sloErr)
+	}
 	return models.WorkloadHealth{
 		WorkloadStatus: status,
 		Requests:       rate,
+		Latency:        latency[workload],
+		SLOs:           slos,
+		Anomalies:      in.getAnomalies(namespace, "workload", workload, rateInterval, queryTime)[workload],
 	}, err
 }
 
@@ -140,12 +183,20 @@ func (in *HealthService) getNamespaceAppHealth(namespace string, appEntities nam
 	sidecarPresent := false
 
 	// Prepare all data
+	since := healthSince(rateInterval, queryTime)
+	var restarts models.ContainerRestarts
+	for _, entities := range appEntities {
+		if entities != nil && hasPods(entities.Workloads) {
+			restarts = in.getContainerRestarts(namespace, rateInterval, queryTime)
+			break
+		}
+	}
 	for app, entities := range appEntities {
 		if app != "" {
 			h := models.EmptyAppHealth()
 			allHealth[app] = &h
 			if entities != nil {
-				h.WorkloadStatuses = entities.Workloads.CastWorkloadStatuses()
+				h.WorkloadStatuses = entities.Workloads.CastWorkloadStatuses(since, restarts)
 				for _, w := range entities.Workloads {
 					if w.IstioSidecar {
 						sidecarPresent = true
@@ -163,6 +214,22 @@ func (in *HealthService) getNamespaceAppHealth(namespace string, appEntities nam
 		errRate = err
 		// Fill with collected request rates
 		fillAppRequestRates(allHealth, rates)
+
+		apps := make([]string, 0, len(allHealth))
+		for app := range allHealth {
+			apps = append(apps, app)
+		}
+		latency, err := in.getLatencyHealth(namespace, "app", apps, itemLatencySelectors(namespace, "", "canonical_service"), rateInterval, queryTime)
+		if err != nil {
+			log.Errorf("Error fetching latency of apps in namespace %s: %s", namespace, err)
+		}
+		slos := in.businessLayer.SLO.getNamespaceSLOs(namespace, "app", apps, nil, queryTime)
+		anomalies := in.getAnomalies(namespace, "app", "", rateInterval, queryTime)
+		for app, health := range allHealth {
+			health.Latency = latency[app]
+			health.SLOs = slos[app]
+			health.Anomalies = anomalies[app]
+		}
 	}
 
 	return allHealth, errRate
@@ -213,6 +280,24 @@ func (in *HealthService) getNamespaceServiceHealth(namespace string, services []
 		}
 	}
 
+	names := make([]string, 0, len(services))
+	annotations := make(map[string]map[string]string, len(services))
+	for _, service := range services {
+		names = append(names, service.Name)
+		annotations[service.Name] = service.Annotations
+	}
+	latency, err := in.getLatencyHealth(namespace, "service", names, serviceLatencySelectors(namespace, ""), rateInterval, queryTime)
+	if err != nil {
+		log.Errorf("Error fetching latency of services in namespace %s: %s", namespace, err)
+	}
+	slos := in.businessLayer.SLO.getNamespaceSLOs(namespace, "service", names, annotations, queryTime)
+	anomalies := in.getAnomalies(namespace, "service", "", rateInterval, queryTime)
+	for service, health := range allHealth {
+		health.Latency = latency[service]
+		health.SLOs = slos[service]
+		health.Anomalies = anomalies[service]
+	}
+
 	return allHealth
 }
 
@@ -235,9 +320,14 @@ func (in *HealthService) getNamespaceWorkloadHealth(namespace string, ws models.
 	hasSidecar := false
 
 	allHealth := make(models.NamespaceWorkloadHealth)
+	since := healthSince(rateInterval, queryTime)
+	var restarts models.ContainerRestarts
+	if hasPods(ws) {
+		restarts = in.getContainerRestarts(namespace, rateInterval, queryTime)
+	}
 	for _, w := range ws {
 		allHealth[w.Name] = models.EmptyWorkloadHealth()
-		allHealth[w.Name].WorkloadStatus = w.CastWorkloadStatus()
+		allHealth[w.Name].WorkloadStatus = w.CastWorkloadStatus(since, restarts)
 		if w.IstioSidecar {
 			hasSidecar = true
 		}
@@ -250,11 +340,72 @@ func (in *HealthService) getNamespaceWorkloadHealth(namespace string, ws models.
 		rates, err = in.prom.GetAllRequestRates(namespace, rateInterval, queryTime)
 		// Fill with collected request rates
 		fillWorkloadRequestRates(allHealth, rates)
+
+		names := make([]string, 0, len(ws))
+		for _, w := range ws {
+			names = append(names, w.Name)
+		}
+		latency, latencyErr := in.getLatencyHealth(namespace, "workload", names, itemLatencySelectors(namespace, "", "workload"), rateInterval, queryTime)
+		if latencyErr != nil {
+			log.Errorf("Error fetching latency of workloads in namespace %s: %s", namespace, latencyErr)
+		}
+		slos := in.businessLayer.SLO.getNamespaceSLOs(namespace, "workload", names, nil, queryTime)
+		anomalies := in.getAnomalies(namespace, "workload", "", rateInterval, queryTime)
+		for workload, health := range allHealth {
+			health.Latency = latency[workload]
+			health.SLOs = slos[workload]
+			health.Anomalies = anomalies[workload]
+		}
 	}
 
 	return allHealth, err
 }
 
+// getContainerRestarts returns the restarts of the containers of a namespace in the rate interval, nil when they are
+// unknown because kube-state-metrics isn't scraped. Errors are logged, the health being still relevant without restarts.
+func (in *HealthService) getContainerRestarts(namespace, rateInterval string, queryTime time.Time) models.ContainerRestarts {
+	samples, err := in.prom.GetContainerRestarts(namespace, rateInterval, queryTime)
+	if err != nil {
+		log.Errorf("Error fetching container restarts in namespace %s: %s", namespace, err)
+		return nil
+	}
+	if len(samples) == 0 {
+		return nil
+	}
+	restarts := models.ContainerRestarts{}
+	for _, sample := range samples {
+		if math.IsNaN(float64(sample.Value)) {
+			continue
+		}
+		pod := string(sample.Metric["pod"])
+		if restarts[pod] == nil {
+			restarts[pod] = map[string]int32{}
+		}
+		restarts[pod][string(sample.Metric["container"])] = int32(sample.Value)
+	}
+	return restarts
+}
+
+// hasPods checks whether some workload has pods
+func hasPods(ws models.Workloads) bool {
+	for _, w := range ws {
+		if len(w.Pods) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// healthSince returns the start of the rate interval, from which the pod-level signals are reported
+func healthSince(rateInterval string, queryTime time.Time) time.Time {
+	interval, err := model.ParseDuration(rateInterval)
+	if err != nil {
+		log.Debugf("Invalid rate interval %s: %v", rateInterval, err)
+		return queryTime
+	}
+	return queryTime.Add(-time.Duration(interval))
+}
+
 // fillAppRequestRates aggregates requests rates from metrics fetched from Prometheus, and stores the result in the health map.
 func fillAppRequestRates(allHealth models.NamespaceAppHealth, rates model.Vector) {
 	lblDest := model.LabelName("destination_canonical_service")
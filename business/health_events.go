@@ -0,0 +1,295 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// healthObjectKey identifies an app, service or workload of a namespace
+type healthObjectKey struct {
+	kind string
+	name string
+}
+
+// healthObjectState is the notified health status of an object, and the new status waiting for the debounce
+type healthObjectState struct {
+	status       string
+	pending      string
+	pendingSince time.Time
+}
+
+// HealthEventsStore detects the health changes of the objects evaluated by the background health evaluator
+// and keeps the latest events in memory
+type HealthEventsStore struct {
+	debounce  time.Duration
+	maxEvents int
+	mutex     sync.RWMutex
+	lastId    int64
+	events    []models.HealthEvent
+	states    map[string]map[healthObjectKey]*healthObjectState
+}
+
+// Global health events, only set when the background health evaluator is enabled.
+// It is guarded by healthEventsMutex, as it is read by the handlers while the evaluator is started or stopped.
+var healthEvents *HealthEventsStore
+var healthEventsStop chan bool
+var healthEventsMutex sync.RWMutex
+
+func NewHealthEventsStore(debounce time.Duration, maxEvents int) *HealthEventsStore {
+	return &HealthEventsStore{
+		debounce:  debounce,
+		maxEvents: maxEvents,
+		events:    []models.HealthEvent{},
+		states:    map[string]map[healthObjectKey]*healthObjectState{},
+	}
+}
+
+// Observe records the health statuses of all the objects of a namespace, and returns the changes notified.
+// The first status of an object is not a change. A new status is notified once it lasted for the debounce duration,
+// and nodata statuses are ignored. Objects not observed anymore are forgotten.
+func (in *HealthEventsStore) Observe(namespace string, statuses map[healthObjectKey]string, timestamp time.Time) []models.HealthEvent {
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+
+	previous := in.states[namespace]
+	states := make(map[healthObjectKey]*healthObjectState, len(statuses))
+	changes := []models.HealthEvent{}
+	for key, status := range statuses {
+		state, found := previous[key]
+		if !found {
+			if status == models.NoDataStatus {
+				continue
+			}
+			states[key] = &healthObjectState{status: status}
+			continue
+		}
+		states[key] = state
+		if status == models.NoDataStatus {
+			continue
+		}
+		if status == state.status {
+			state.pending = ""
+			continue
+		}
+		if status != state.pending {
+			state.pending = status
+			state.pendingSince = timestamp
+		}
+		if timestamp.Sub(state.pendingSince) < in.debounce {
+			continue
+		}
+
+		event := models.HealthEvent{
+			Timestamp: timestamp,
+			Namespace: namespace,
+			Kind:      key.kind,
+			Name:      key.name,
+			From:      state.status,
+			To:        status,
+		}
+		changes = append(changes, event)
+		state.status = status
+		state.pending = ""
+	}
+	in.states[namespace] = states
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return changes[i].Kind < changes[j].Kind
+		}
+		return changes[i].Name < changes[j].Name
+	})
+	for i := range changes {
+		in.lastId++
+		changes[i].Id = in.lastId
+	}
+
+	in.events = append(in.events, changes...)
+	if len(in.events) > in.maxEvents {
+		in.events = append([]models.HealthEvent{}, in.events[len(in.events)-in.maxEvents:]...)
+	}
+	return changes
+}
+
+// Get returns the events of a namespace newer than the given event id, oldest first
+func (in *HealthEventsStore) Get(namespace string, since int64) models.HealthEvents {
+	in.mutex.RLock()
+	defer in.mutex.RUnlock()
+
+	events := models.HealthEvents{}
+	for _, event := range in.events {
+		if event.Namespace == namespace && event.Id > since {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// notifyHealthEvents sends the events to the sinks routed to their namespace
+func notifyHealthEvents(sinks []config.HealthNotificationSink, namespace string, events []models.HealthEvent) {
+	if len(events) == 0 {
+		return
+	}
+	for _, sink := range sinks {
+		if !matchesHealthConfig(sink.Namespaces, namespace) {
+			continue
+		}
+
+		var body []byte
+		var err error
+		switch sink.Type {
+		case "slack":
+			text := ""
+			for _, event := range events {
+				text += event.String() + "\n"
+			}
+			body, err = json.Marshal(map[string]string{"text": text})
+		case "webhook", "":
+			body, err = json.Marshal(events)
+		default:
+			err = fmt.Errorf("unknown sink type %s", sink.Type)
+		}
+
+		if err == nil {
+			timeout := time.Duration(sink.Timeout) * time.Second
+			if timeout <= 0 {
+				timeout = 10 * time.Second
+			}
+			var code int
+			_, code, err = httputil.HttpPost(sink.URL, &sink.Auth, body, "application/json", timeout)
+			if err == nil && (code < 200 || code >= 300) {
+				err = fmt.Errorf("HTTP status %d", code)
+			}
+		}
+		if err != nil {
+			log.Warningf("Health notifications: cannot notify sink [%s]: %s", sink.Name, err)
+		}
+	}
+}
+
+// StartHealthNotifications starts the background health evaluator when it is enabled.
+// It evaluates the health of all the namespaces accessible by the Kiali ServiceAccount on each interval.
+func StartHealthNotifications() {
+	conf := config.Get().HealthConfig.Notifications
+	healthEventsMutex.Lock()
+	defer healthEventsMutex.Unlock()
+	if !conf.Enabled || healthEvents != nil {
+		return
+	}
+
+	healthEvents = NewHealthEventsStore(time.Duration(conf.Debounce)*time.Second, conf.MaxEvents)
+	healthEventsStop = make(chan bool)
+
+	go func(store *HealthEventsStore, stop chan bool) {
+		interval := time.Duration(conf.Interval) * time.Second
+		if interval <= 0 {
+			interval = time.Minute
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			layer, err := getKialiSALayer()
+			if err != nil {
+				log.Errorf("Health notifications: error initializing the business layer: %s", err)
+			} else {
+				layer.Health.recordHealthEvents(store, conf)
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}(healthEvents, healthEventsStop)
+}
+
+// StopHealthNotifications stops the background health evaluator
+func StopHealthNotifications() {
+	healthEventsMutex.Lock()
+	defer healthEventsMutex.Unlock()
+	if healthEventsStop != nil {
+		close(healthEventsStop)
+		healthEventsStop = nil
+	}
+	healthEvents = nil
+}
+
+// recordHealthEvents evaluates the health of all the accessible namespaces and notifies the changes
+func (in *HealthService) recordHealthEvents(store *HealthEventsStore, conf config.HealthNotificationsConfig) {
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		log.Errorf("Health notifications: error fetching namespaces: %s", err)
+		return
+	}
+
+	for _, ns := range namespaces {
+		statuses, err := in.getNamespaceHealthStatuses(ns.Name, conf.RateInterval, util.Clock.Now())
+		if err != nil {
+			log.Errorf("Health notifications: error evaluating the health of namespace [%s]: %s", ns.Name, err)
+			continue
+		}
+		changes := store.Observe(ns.Name, statuses, util.Clock.Now())
+		notifyHealthEvents(conf.Sinks, ns.Name, changes)
+	}
+}
+
+// getNamespaceHealthStatuses evaluates the health status of all the apps, services and workloads of a namespace
+func (in *HealthService) getNamespaceHealthStatuses(namespace, rateInterval string, queryTime time.Time) (map[healthObjectKey]string, error) {
+	statuses := map[healthObjectKey]string{}
+
+	apps, err := in.GetNamespaceAppHealth(namespace, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	for name, health := range apps {
+		statuses[healthObjectKey{kind: "app", name: name}] = appHealthStatus(namespace, name, health)
+	}
+
+	services, err := in.GetNamespaceServiceHealth(namespace, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	for name, health := range services {
+		statuses[healthObjectKey{kind: "service", name: name}] = serviceHealthStatus(namespace, name, health)
+	}
+
+	workloads, err := in.GetNamespaceWorkloadHealth(namespace, rateInterval, queryTime)
+	if err != nil {
+		return nil, err
+	}
+	for name, health := range workloads {
+		statuses[healthObjectKey{kind: "workload", name: name}] = workloadHealthStatus(namespace, name, health)
+	}
+
+	return statuses, nil
+}
+
+// GetHealthEvents returns the health changes of a namespace detected by the background health evaluator,
+// newer than the given event id
+func (in *HealthService) GetHealthEvents(namespace string, since int64) (models.HealthEvents, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	healthEventsMutex.RLock()
+	store := healthEvents
+	healthEventsMutex.RUnlock()
+	if store == nil {
+		return nil, errors.NewServiceUnavailable("the health notifications are not enabled")
+	}
+	return store.Get(namespace, since), nil
+}
@@ -0,0 +1,218 @@
+package business
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/util"
+)
+
+func TestHealthEventsStore(t *testing.T) {
+	assert := assert.New(t)
+
+	reviews := healthObjectKey{kind: "workload", name: "reviews-v2"}
+	ratings := healthObjectKey{kind: "service", name: "ratings"}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := NewHealthEventsStore(2*time.Minute, 10)
+
+	// First statuses are not changes
+	changes := store.Observe("bookinfo", map[healthObjectKey]string{reviews: models.HealthyStatus, ratings: models.NoDataStatus}, start)
+	assert.Empty(changes)
+
+	// A new status is debounced
+	changes = store.Observe("bookinfo", map[healthObjectKey]string{reviews: models.FailureStatus, ratings: models.HealthyStatus}, start.Add(time.Minute))
+	assert.Empty(changes)
+
+	// No data keeps the pending status
+	changes = store.Observe("bookinfo", map[healthObjectKey]string{reviews: models.NoDataStatus, ratings: models.DegradedStatus}, start.Add(2*time.Minute))
+	assert.Empty(changes)
+
+	changes = store.Observe("bookinfo", map[healthObjectKey]string{reviews: models.FailureStatus, ratings: models.DegradedStatus}, start.Add(3*time.Minute))
+	assert.Len(changes, 1)
+	assert.Equal(models.HealthEvent{Id: 1, Timestamp: start.Add(3 * time.Minute), Namespace: "bookinfo", Kind: "workload", Name: "reviews-v2", From: models.HealthyStatus, To: models.FailureStatus}, changes[0])
+
+	// Flapping objects are not notified
+	changes = store.Observe("bookinfo", map[healthObjectKey]string{reviews: models.HealthyStatus, ratings: models.DegradedStatus}, start.Add(4*time.Minute))
+	assert.Len(changes, 1)
+	assert.Equal("ratings", changes[0].Name)
+	assert.Equal(models.DegradedStatus, changes[0].To)
+	changes = store.Observe("bookinfo", map[healthObjectKey]string{reviews: models.FailureStatus, ratings: models.DegradedStatus}, start.Add(5*time.Minute))
+	assert.Empty(changes)
+	changes = store.Observe("bookinfo", map[healthObjectKey]string{reviews: models.FailureStatus, ratings: models.DegradedStatus}, start.Add(10*time.Minute))
+	assert.Empty(changes)
+
+	events := store.Get("bookinfo", 0)
+	assert.Len(events, 2)
+	assert.Len(store.Get("bookinfo", 1), 1)
+	assert.Empty(store.Get("other", 0))
+}
+
+func TestGetHealthEventsWhileStarting(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.HealthConfig.Notifications.Enabled = true
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}}, nil)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			StartHealthNotifications()
+			StopHealthNotifications()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			if _, err := layer.Health.GetHealthEvents("bookinfo", 0); err != nil {
+				assert.True(errors.IsServiceUnavailable(err))
+			}
+		}
+	}()
+	wg.Wait()
+
+	_, err := layer.Health.GetHealthEvents("bookinfo", 0)
+	assert.True(errors.IsServiceUnavailable(err))
+}
+
+func TestNotifyHealthEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	bodies := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies[r.URL.Path] = body
+		assert.Equal("application/json", r.Header.Get("Content-Type"))
+	}))
+	defer server.Close()
+
+	sinks := []config.HealthNotificationSink{
+		{Name: "all", Type: "webhook", URL: server.URL + "/webhook"},
+		{Name: "chat", Type: "slack", URL: server.URL + "/slack", Namespaces: "^bookinfo$"},
+		{Name: "others", Type: "webhook", URL: server.URL + "/others", Namespaces: "^travels$"},
+	}
+	events := []models.HealthEvent{
+		{Id: 1, Namespace: "bookinfo", Kind: "workload", Name: "reviews-v2", From: models.HealthyStatus, To: models.FailureStatus},
+	}
+
+	notifyHealthEvents(sinks, "bookinfo", events)
+
+	assert.Len(bodies, 2)
+	notified := []models.HealthEvent{}
+	assert.NoError(json.Unmarshal(bodies["/webhook"], &notified))
+	assert.Equal(events, notified)
+	slack := map[string]string{}
+	assert.NoError(json.Unmarshal(bodies["/slack"], &slack))
+	assert.Equal("Health of workload bookinfo/reviews-v2 changed from healthy to failure\n", slack["text"])
+}
+
+func TestHealthStatus(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	requests := models.NewEmptyRequestHealth()
+	assert.Equal(models.NoDataStatus, serviceHealthStatus("bookinfo", "reviews", &models.ServiceHealth{Requests: requests}))
+
+	// 4xx are degraded from 10% and fail from 20%, 5xx fail from 10%
+	requests.Inbound["http"] = map[string]float64{"200": 8.5, "404": 1.5}
+	assert.Equal(models.DegradedStatus, serviceHealthStatus("bookinfo", "reviews", &models.ServiceHealth{Requests: requests}))
+	requests.Outbound["http"] = map[string]float64{"200": 8, "503": 2}
+	assert.Equal(models.FailureStatus, appHealthStatus("bookinfo", "reviews", &models.AppHealth{Requests: requests}))
+
+	// The default tolerances apply to the objects whose matching rate only has latency tolerances
+	conf := config.NewConfig()
+	conf.HealthConfig.Rate = []config.Rate{{Namespace: "bookinfo", Kind: ".*", Name: ".*", Tolerance: []config.Tolerance{
+		{Direction: ".*", Protocol: "http", Quantile: 0.99, LatencyDegraded: 500, LatencyFailure: 1000},
+	}}}
+	config.Set(conf)
+	assert.Equal(models.FailureStatus, appHealthStatus("bookinfo", "reviews", &models.AppHealth{Requests: requests}))
+	config.Set(config.NewConfig())
+
+	// Replicas and pods
+	workload := &models.WorkloadHealth{
+		Requests:       models.NewEmptyRequestHealth(),
+		WorkloadStatus: &models.WorkloadStatus{DesiredReplicas: 2, CurrentReplicas: 2, AvailableReplicas: 2},
+	}
+	assert.Equal(models.HealthyStatus, workloadHealthStatus("bookinfo", "reviews-v1", workload))
+	workload.WorkloadStatus.Pods = &models.PodsHealth{Status: models.DegradedStatus}
+	assert.Equal(models.DegradedStatus, workloadHealthStatus("bookinfo", "reviews-v1", workload))
+	workload.WorkloadStatus.AvailableReplicas = 0
+	assert.Equal(models.FailureStatus, workloadHealthStatus("bookinfo", "reviews-v1", workload))
+}
+
+func TestRecordHealthEventsWithAnomalies(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.HealthConfig.Anomalies.Enabled = true
+	conf.HealthConfig.Anomalies.Baselines = []string{"1w"}
+	config.Set(conf)
+
+	start := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: start}
+	defer func() { util.Clock = util.RealClock{} }()
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProjects", mock.AnythingOfType("string")).Return([]osproject_v1.Project{{ObjectMeta: meta_v1.ObjectMeta{Name: "tutorial"}}}, nil)
+	k8s.On("GetProject", "tutorial").Return(&osproject_v1.Project{}, nil)
+	k8s.MockEmptyWorkloads("tutorial")
+	k8s.On("GetPods", "tutorial", mock.AnythingOfType("string")).Return([]core_v1.Pod{}, nil)
+	k8s.MockServices("tutorial", []string{"httpbin"})
+	prom.On("GetNamespaceServicesRequestRates", "tutorial", "5m", mock.AnythingOfType("time.Time")).Return(serviceRates, nil)
+
+	labels := `reporter="destination",destination_service_namespace="tutorial"`
+	sample := func(rate float64) model.Vector {
+		return model.Vector{&model.Sample{Metric: model.Metric{"destination_service_name": "httpbin"}, Value: model.SampleValue(rate)}}
+	}
+	prom.On("GetRequestSignals", labels, "destination_service_name", 0.95, "5m", "1w", mock.AnythingOfType("time.Time")).
+		Return(prometheus.RequestSignals{Rates: sample(15.4)}, nil)
+	// The traffic drops in the second evaluation
+	prom.On("GetRequestSignals", labels, "destination_service_name", 0.95, "5m", "", mock.AnythingOfType("time.Time")).
+		Return(prometheus.RequestSignals{Rates: sample(15.4)}, nil).Once()
+	prom.On("GetRequestSignals", labels, "destination_service_name", 0.95, "5m", "", mock.AnythingOfType("time.Time")).
+		Return(prometheus.RequestSignals{Rates: sample(1)}, nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	store := NewHealthEventsStore(0, 10)
+	notifications := config.HealthNotificationsConfig{RateInterval: "5m"}
+
+	layer.Health.recordHealthEvents(store, notifications)
+	assert.Empty(store.Get("tutorial", 0))
+
+	util.Clock = util.ClockMock{Time: start.Add(time.Minute)}
+	layer.Health.recordHealthEvents(store, notifications)
+	events := store.Get("tutorial", 0)
+	assert.Len(events, 1)
+	assert.Equal("service", events[0].Kind)
+	assert.Equal("httpbin", events[0].Name)
+	assert.Equal(models.HealthyStatus, events[0].From)
+	assert.Equal(models.DegradedStatus, events[0].To)
+}
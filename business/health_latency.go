@@ -0,0 +1,149 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// latencySelector selects the requests of one direction of the objects of a namespace
+type latencySelector struct {
+	direction string
+	// Prometheus labels of the requests
+	labels string
+	// Label holding the name of the object
+	nameLabel string
+}
+
+func serviceLatencySelectors(namespace, service string) []latencySelector {
+	labels := fmt.Sprintf(`reporter="destination",destination_service_namespace="%s"`, namespace)
+	if service != "" {
+		labels += fmt.Sprintf(`,destination_service_name="%s"`, service)
+	}
+	return []latencySelector{{direction: "inbound", labels: labels, nameLabel: "destination_service_name"}}
+}
+
+// itemLatencySelectors selects the inbound and outbound requests of the apps or workloads of a namespace,
+// identified by the destination_<itemLabelSuffix> and source_<itemLabelSuffix> labels
+func itemLatencySelectors(namespace, item, itemLabelSuffix string) []latencySelector {
+	lblIn := fmt.Sprintf(`reporter="destination",destination_workload_namespace="%s"`, namespace)
+	lblOut := fmt.Sprintf(`reporter="source",source_workload_namespace="%s"`, namespace)
+	if item != "" {
+		lblIn += fmt.Sprintf(`,destination_%s="%s"`, itemLabelSuffix, item)
+		lblOut += fmt.Sprintf(`,source_%s="%s"`, itemLabelSuffix, item)
+	}
+	return []latencySelector{
+		{direction: "inbound", labels: lblIn, nameLabel: "destination_" + itemLabelSuffix},
+		{direction: "outbound", labels: lblOut, nameLabel: "source_" + itemLabelSuffix},
+	}
+}
+
+// latencyQuery identifies the requests sharing a latency computation
+type latencyQuery struct {
+	quantile  float64
+	protocol  string
+	direction string
+}
+
+// getLatencyHealth evaluates the latency tolerances of the named objects of a namespace. Prometheus is queried once
+// per distinct quantile, protocol and direction of the tolerances, and only when there are some.
+func (in *HealthService) getLatencyHealth(namespace, kind string, names []string, selectors []latencySelector, rateInterval string, queryTime time.Time) (map[string][]models.LatencyHealth, error) {
+	allHealth := make(map[string][]models.LatencyHealth, len(names))
+	queried := map[latencyQuery]bool{}
+	queries := []latencyQuery{}
+	for _, name := range names {
+		for _, tolerance := range latencyTolerances(namespace, kind, name) {
+			for _, selector := range selectors {
+				if !matchesHealthConfig(tolerance.Direction, selector.direction) {
+					continue
+				}
+				allHealth[name] = append(allHealth[name], models.LatencyHealth{
+					Quantile:  tolerance.Quantile,
+					Protocol:  tolerance.Protocol,
+					Direction: selector.direction,
+					Degraded:  tolerance.LatencyDegraded,
+					Failure:   tolerance.LatencyFailure,
+					Status:    models.NoDataStatus,
+				})
+				query := latencyQuery{quantile: tolerance.Quantile, protocol: tolerance.Protocol, direction: selector.direction}
+				if !queried[query] {
+					queried[query] = true
+					queries = append(queries, query)
+				}
+			}
+		}
+	}
+
+	for _, query := range queries {
+		var selector latencySelector
+		for _, s := range selectors {
+			if s.direction == query.direction {
+				selector = s
+			}
+		}
+		labels := selector.labels
+		if query.protocol != "" {
+			labels += fmt.Sprintf(`,request_protocol=~"%s"`, query.protocol)
+		}
+		latencies, err := in.prom.GetRequestLatencyQuantile(labels, selector.nameLabel, query.quantile, rateInterval, queryTime)
+		if err != nil {
+			return allHealth, err
+		}
+
+		for _, sample := range latencies {
+			if math.IsNaN(float64(sample.Value)) {
+				continue
+			}
+			name := string(sample.Metric[model.LabelName(selector.nameLabel)])
+			health := allHealth[name]
+			for i := range health {
+				if health[i].Quantile == query.quantile && health[i].Protocol == query.protocol && health[i].Direction == query.direction {
+					latency := float64(sample.Value)
+					health[i].Latency = &latency
+					health[i].Status = latencyStatus(latency, health[i].Degraded, health[i].Failure)
+				}
+			}
+		}
+	}
+
+	return allHealth, nil
+}
+
+func latencyStatus(latency, degraded, failure float64) string {
+	if failure > 0 && latency >= failure {
+		return models.FailureStatus
+	}
+	if degraded > 0 && latency >= degraded {
+		return models.DegradedStatus
+	}
+	return models.HealthyStatus
+}
+
+// latencyTolerances returns the latency tolerances of the first rate configuration matching the object,
+// like request tolerances do
+func latencyTolerances(namespace, kind, name string) []config.Tolerance {
+	tolerances := []config.Tolerance{}
+	if rate := healthRate(namespace, kind, name); rate != nil {
+		for _, tolerance := range rate.Tolerance {
+			if tolerance.Quantile > 0 {
+				tolerances = append(tolerances, tolerance)
+			}
+		}
+	}
+	return tolerances
+}
+
+func matchesHealthConfig(expr, value string) bool {
+	matched, err := regexp.MatchString(expr, value)
+	if err != nil {
+		log.Warningf("Invalid expression %q of the health_config: %v", expr, err)
+	}
+	return matched
+}
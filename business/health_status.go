@@ -0,0 +1,175 @@
+package business
+
+import (
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// healthStatusRank orders the health statuses from the best to the worst
+var healthStatusRank = map[string]int{
+	models.NoDataStatus:   0,
+	models.HealthyStatus:  1,
+	models.DegradedStatus: 2,
+	models.FailureStatus:  3,
+}
+
+func worstHealthStatus(statuses ...string) string {
+	worst := models.NoDataStatus
+	for _, status := range statuses {
+		if healthStatusRank[status] > healthStatusRank[worst] {
+			worst = status
+		}
+	}
+	return worst
+}
+
+// healthRate returns the first rate configuration matching the object, nil if none
+func healthRate(namespace, kind, name string) *config.Rate {
+	for _, rate := range config.Get().HealthConfig.Rate {
+		if matchesHealthConfig(rate.Namespace, namespace) && matchesHealthConfig(rate.Kind, kind) && matchesHealthConfig(rate.Name, name) {
+			return &rate
+		}
+	}
+	return nil
+}
+
+// requestTolerances returns the error tolerances of the rate configuration matching the object. The default tolerances
+// apply when no configuration matches, or when the matching one only has latency tolerances.
+func requestTolerances(namespace, kind, name string) []config.Tolerance {
+	tolerances := []config.Tolerance{}
+	if rate := healthRate(namespace, kind, name); rate != nil {
+		for _, tolerance := range rate.Tolerance {
+			if tolerance.Quantile == 0 {
+				tolerances = append(tolerances, tolerance)
+			}
+		}
+	}
+	if len(tolerances) == 0 {
+		return config.DefaultTolerances()
+	}
+	return tolerances
+}
+
+// requestsHealthStatus compares the error ratios of the requests to the request tolerances of the object.
+// It is nodata without requests.
+func requestsHealthStatus(namespace, kind, name string, requests models.RequestHealth) string {
+	status := models.NoDataStatus
+	tolerances := requestTolerances(namespace, kind, name)
+	directions := map[string]map[string]map[string]float64{"inbound": requests.Inbound, "outbound": requests.Outbound}
+	for direction, protocols := range directions {
+		for protocol, codes := range protocols {
+			total := 0.0
+			for _, value := range codes {
+				total += value
+			}
+			if total == 0 {
+				continue
+			}
+			status = worstHealthStatus(status, models.HealthyStatus)
+			for _, tolerance := range tolerances {
+				if !matchesHealthConfig(tolerance.Direction, direction) || !matchesHealthConfig(tolerance.Protocol, protocol) {
+					continue
+				}
+				errors := 0.0
+				for code, value := range codes {
+					if matchesHealthConfig(tolerance.Code, code) {
+						errors += value
+					}
+				}
+				ratio := errors / total * 100
+				if tolerance.Failure > 0 && ratio >= float64(tolerance.Failure) {
+					status = worstHealthStatus(status, models.FailureStatus)
+				} else if tolerance.Degraded > 0 && ratio >= float64(tolerance.Degraded) {
+					status = worstHealthStatus(status, models.DegradedStatus)
+				}
+			}
+		}
+	}
+	return status
+}
+
+// replicasHealthStatus rolls up the replicas and the pod-level signals of a workload
+func replicasHealthStatus(ws *models.WorkloadStatus) string {
+	if ws == nil {
+		return models.NoDataStatus
+	}
+	status := models.NoDataStatus
+	switch {
+	case ws.DesiredReplicas > 0 && ws.AvailableReplicas == 0:
+		status = models.FailureStatus
+	case ws.AvailableReplicas < ws.DesiredReplicas || ws.CurrentReplicas > ws.DesiredReplicas:
+		status = models.DegradedStatus
+	case ws.DesiredReplicas > 0:
+		status = models.HealthyStatus
+	}
+	if ws.Pods != nil {
+		status = worstHealthStatus(status, ws.Pods.Status)
+	}
+	return status
+}
+
+func latencyHealthStatus(latency []models.LatencyHealth) string {
+	status := models.NoDataStatus
+	for _, l := range latency {
+		status = worstHealthStatus(status, l.Status)
+	}
+	return status
+}
+
+func sloHealthStatus(slos []models.SLOStatus) string {
+	status := models.NoDataStatus
+	for _, slo := range slos {
+		switch slo.Status {
+		case models.SLOHealthy:
+			status = worstHealthStatus(status, models.HealthyStatus)
+		case models.SLOBurning:
+			status = worstHealthStatus(status, models.DegradedStatus)
+		case models.SLOBreached:
+			status = worstHealthStatus(status, models.FailureStatus)
+		}
+	}
+	return status
+}
+
+// anomaliesHealthStatus degrades the objects whose requests deviate from their baselines
+func anomaliesHealthStatus(anomalies []models.Anomaly) string {
+	status := models.NoDataStatus
+	for _, anomaly := range anomalies {
+		if anomaly.Anomalous {
+			status = worstHealthStatus(status, models.DegradedStatus)
+		}
+	}
+	return status
+}
+
+// appHealthStatus rolls up the health of an app: healthy, degraded, failure or nodata
+func appHealthStatus(namespace, app string, health *models.AppHealth) string {
+	status := worstHealthStatus(
+		requestsHealthStatus(namespace, "app", app, health.Requests),
+		latencyHealthStatus(health.Latency),
+		sloHealthStatus(health.SLOs),
+		anomaliesHealthStatus(health.Anomalies))
+	for _, ws := range health.WorkloadStatuses {
+		status = worstHealthStatus(status, replicasHealthStatus(ws))
+	}
+	return status
+}
+
+// serviceHealthStatus rolls up the health of a service: healthy, degraded, failure or nodata
+func serviceHealthStatus(namespace, service string, health *models.ServiceHealth) string {
+	return worstHealthStatus(
+		requestsHealthStatus(namespace, "service", service, health.Requests),
+		latencyHealthStatus(health.Latency),
+		sloHealthStatus(health.SLOs),
+		anomaliesHealthStatus(health.Anomalies))
+}
+
+// workloadHealthStatus rolls up the health of a workload: healthy, degraded, failure or nodata
+func workloadHealthStatus(namespace, workload string, health *models.WorkloadHealth) string {
+	return worstHealthStatus(
+		requestsHealthStatus(namespace, "workload", workload, health.Requests),
+		latencyHealthStatus(health.Latency),
+		sloHealthStatus(health.SLOs),
+		anomaliesHealthStatus(health.Anomalies),
+		replicasHealthStatus(health.WorkloadStatus))
+}
@@ -1,6 +1,7 @@
 package business
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/prometheustest"
 )
 
@@ -33,6 +35,7 @@ func TestGetServiceHealth(t *testing.T) {
 	prom.MockServiceRequestRates("ns", "httpbin", serviceRates)
 	k8s.On("IsOpenShift").Return(true)
 	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetService", "ns", "httpbin").Return(&core_v1.Service{}, nil)
 
 	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
 
@@ -59,6 +62,7 @@ func TestGetAppHealth(t *testing.T) {
 	// Setup mocks
 	k8s := new(kubetest.K8SClientMock)
 	prom := new(prometheustest.PromClientMock)
+	prom.MockContainerRestarts("ns", model.Vector{})
 	conf := config.NewConfig()
 	config.Set(conf)
 
@@ -96,12 +100,54 @@ func TestGetAppHealth(t *testing.T) {
 	assert.Equal(result, health.Requests.Outbound)
 }
 
+func TestGetAppHealthLatency(t *testing.T) {
+	assert := assert.New(t)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	prom.MockContainerRestarts("ns", model.Vector{})
+	conf := config.NewConfig()
+	conf.HealthConfig.Rate = []config.Rate{
+		{
+			Namespace: "ns",
+			Kind:      "app",
+			Name:      "reviews",
+			Tolerance: []config.Tolerance{{Protocol: "http", Direction: "inbound", Quantile: 0.99, LatencyDegraded: 500, LatencyFailure: 1000}},
+		},
+	}
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	k8s.MockEmptyWorkloads("ns")
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetDeployments", "ns").Return(fakeDeploymentsHealthReview(), nil)
+	k8s.On("GetPods", "ns", "app=reviews").Return(fakePodsHealthReview(), nil)
+	k8s.On("GetProxyStatus").Return([]*kubernetes.ProxyStatus{}, nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom.MockAppRequestRates("ns", "reviews", otherRatesIn, otherRatesOut)
+	// Same label than the namespace app health
+	prom.On("GetRequestLatencyQuantile", `reporter="destination",destination_workload_namespace="ns",destination_canonical_service="reviews",request_protocol=~"http"`, "destination_canonical_service", 0.99, "1m", queryTime).
+		Return(model.Vector{&model.Sample{Metric: model.Metric{"destination_canonical_service": "reviews"}, Value: model.SampleValue(750)}}, nil)
+
+	health, _ := hs.GetAppHealth("ns", "reviews", "1m", queryTime)
+
+	prom.AssertNumberOfCalls(t, "GetRequestLatencyQuantile", 1)
+	assert.Len(health.Latency, 1)
+	assert.Equal(750.0, *health.Latency[0].Latency)
+	assert.Equal(models.DegradedStatus, health.Latency[0].Status)
+}
+
 func TestGetWorkloadHealth(t *testing.T) {
 	assert := assert.New(t)
 
 	// Setup mocks
 	k8s := new(kubetest.K8SClientMock)
 	prom := new(prometheustest.PromClientMock)
+	prom.MockContainerRestarts("ns", model.Vector{&model.Sample{Metric: model.Metric{"pod": "reviews-v1", "container": "reviews"}, Value: model.SampleValue(2)}})
 	conf := config.NewConfig()
 	config.Set(conf)
 
@@ -121,6 +167,8 @@ func TestGetWorkloadHealth(t *testing.T) {
 
 	k8s.AssertNumberOfCalls(t, "GetDeployment", 1)
 	prom.AssertNumberOfCalls(t, "GetWorkloadRequestRates", 1)
+	// Restarts in the rate interval are known
+	assert.NotNil(health.WorkloadStatus.Pods.App.Restarts)
 	var result = map[string]map[string]float64{
 		"http": {
 			"500": 1.6,
@@ -146,6 +194,7 @@ func TestGetAppHealthWithoutIstio(t *testing.T) {
 	// Setup mocks
 	k8s := new(kubetest.K8SClientMock)
 	prom := new(prometheustest.PromClientMock)
+	prom.MockContainerRestarts("ns", model.Vector{})
 	conf := config.NewConfig()
 	config.Set(conf)
 
@@ -173,6 +222,7 @@ func TestGetWorkloadHealthWithoutIstio(t *testing.T) {
 	// Setup mocks
 	k8s := new(kubetest.K8SClientMock)
 	prom := new(prometheustest.PromClientMock)
+	prom.MockContainerRestarts("ns", model.Vector{})
 	conf := config.NewConfig()
 	config.Set(conf)
 
@@ -192,12 +242,15 @@ func TestGetWorkloadHealthWithoutIstio(t *testing.T) {
 	prom.AssertNumberOfCalls(t, "GetWorkloadRequestRates", 0)
 	assert.Equal(emptyResult, health.Requests.Inbound)
 	assert.Equal(emptyResult, health.Requests.Outbound)
+	// Restarts in the rate interval are unknown without kube-state-metrics
+	assert.Nil(health.WorkloadStatus.Pods.App.Restarts)
 }
 
 func TestGetNamespaceAppHealthWithoutIstio(t *testing.T) {
 	// Setup mocks
 	k8s := new(kubetest.K8SClientMock)
 	prom := new(prometheustest.PromClientMock)
+	prom.MockContainerRestarts("ns", model.Vector{})
 	conf := config.NewConfig()
 	config.Set(conf)
 
@@ -253,6 +306,89 @@ func TestGetNamespaceServiceHealthWithNA(t *testing.T) {
 	assert.Equal(emptyResult, health["httpbin"].Requests.Outbound)
 }
 
+func TestGetNamespaceServiceHealthLatency(t *testing.T) {
+	assert := assert.New(t)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	conf.HealthConfig.Rate = []config.Rate{
+		{
+			Namespace: "tutorial",
+			Kind:      "service",
+			Name:      "reviews|httpbin",
+			Tolerance: []config.Tolerance{
+				{Protocol: "http", Direction: ".*", Quantile: 0.99, LatencyDegraded: 500, LatencyFailure: 1000},
+				// Request tolerances are not evaluated
+				{Code: "^5\\d\\d$", Protocol: "http", Direction: ".*", Failure: 10},
+			},
+		},
+	}
+	config.Set(conf)
+
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.MockServices("tutorial", []string{"reviews", "httpbin", "details"})
+	prom.On("GetNamespaceServicesRequestRates", "tutorial", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(model.Vector{}, nil)
+	prom.On("GetRequestLatencyQuantile", `reporter="destination",destination_service_namespace="tutorial",request_protocol=~"http"`, "destination_service_name", 0.99, "1m", mock.AnythingOfType("time.Time")).
+		Return(model.Vector{
+			&model.Sample{Metric: model.Metric{"destination_service_name": "reviews"}, Value: model.SampleValue(750)},
+			&model.Sample{Metric: model.Metric{"destination_service_name": "httpbin"}, Value: model.SampleValue(math.NaN())},
+			&model.Sample{Metric: model.Metric{"destination_service_name": "details"}, Value: model.SampleValue(2000)},
+		}, nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetNamespaceServiceHealth("tutorial", "1m", time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC))
+
+	assert.Nil(err)
+	prom.AssertNumberOfCalls(t, "GetRequestLatencyQuantile", 1)
+	assert.Len(health, 3)
+	assert.Len(health["reviews"].Latency, 1)
+	assert.Equal("inbound", health["reviews"].Latency[0].Direction)
+	assert.Equal(750.0, *health["reviews"].Latency[0].Latency)
+	assert.Equal(models.DegradedStatus, health["reviews"].Latency[0].Status)
+	// No request
+	assert.Len(health["httpbin"].Latency, 1)
+	assert.Nil(health["httpbin"].Latency[0].Latency)
+	assert.Equal(models.NoDataStatus, health["httpbin"].Latency[0].Status)
+	// No latency tolerance
+	assert.Empty(health["details"].Latency)
+}
+
+func TestGetNamespaceServiceHealthSLOs(t *testing.T) {
+	assert := assert.New(t)
+
+	// Setup mocks
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	conf := config.NewConfig()
+	conf.HealthConfig.SLO = []config.SLO{
+		{Id: "availability", Namespace: "tutorial", Kind: "service", Name: "reviews", Type: "availability", Objective: 99, Window: "1h"},
+	}
+	config.Set(conf)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", mock.AnythingOfType("string")).Return(&osproject_v1.Project{}, nil)
+	k8s.MockServices("tutorial", []string{"reviews", "httpbin"})
+	prom.On("GetNamespaceServicesRequestRates", "tutorial", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(model.Vector{}, nil)
+	prom.On("GetSLOErrorRatios", `reporter="destination",destination_service_name="reviews",destination_service_namespace="tutorial"`, 0.0, []string{"1h"}, queryTime).
+		Return(map[string]float64{"1h": 0.02}, nil)
+
+	hs := HealthService{k8s: k8s, prom: prom, businessLayer: NewWithBackends(k8s, prom, nil)}
+
+	health, err := hs.GetNamespaceServiceHealth("tutorial", "1m", queryTime)
+
+	assert.Nil(err)
+	prom.AssertNumberOfCalls(t, "GetSLOErrorRatios", 1)
+	assert.Len(health["reviews"].SLOs, 1)
+	assert.Equal(models.SLOBreached, health["reviews"].SLOs[0].Status)
+	// No objective
+	assert.Empty(health["httpbin"].SLOs)
+}
+
 var (
 	sampleReviewsToHttpbin200 = model.Sample{
 		Metric: model.Metric{
@@ -12,12 +12,14 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 	"github.com/kiali/kiali/util"
 )
 
 type IstioConfigService struct {
 	k8s           kubernetes.ClientInterface
+	prom          prometheus.ClientInterface
 	businessLayer *Layer
 }
 
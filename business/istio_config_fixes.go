@@ -0,0 +1,56 @@
+package business
+
+import (
+	"fmt"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// ApplyValidationFix applies the fix of a check found on an Istio object. The check is identified by its code and path,
+// and its fix is computed again from the current validations, so only fixes proposed by the checkers can be applied.
+// It returns the fix applied and the resulting target object.
+func (in *IstioConfigService) ApplyValidationFix(namespace, objectType, object, code, path string) (*models.IstioCheckFix, models.IstioConfigDetails, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "ApplyValidationFix")
+	defer promtimer.ObserveNow(&err)
+
+	validations, err := in.businessLayer.Validations.GetIstioObjectValidations(namespace, objectType, object)
+	if err != nil {
+		return nil, models.IstioConfigDetails{}, err
+	}
+
+	var fix *models.IstioCheckFix
+	for _, validation := range validations {
+		for _, check := range validation.Checks {
+			if check.Code == code && check.Path == path && check.Fix != nil {
+				fix = check.Fix
+			}
+		}
+	}
+	if fix == nil {
+		err = kubernetes.NewNotFound(fmt.Sprintf("%s/%s:%s:%s", objectType, object, code, path), "", "fixes")
+		return nil, models.IstioConfigDetails{}, err
+	}
+
+	resourceType := ""
+	for plural, singular := range models.ObjectTypeSingular {
+		if singular == fix.Target.ObjectType {
+			resourceType = plural
+		}
+	}
+	api := GetIstioAPI(resourceType)
+	if api == "" {
+		err = fmt.Errorf("object type not managed: %s", fix.Target.ObjectType)
+		return nil, models.IstioConfigDetails{}, err
+	}
+
+	var details models.IstioConfigDetails
+	if fix.Patch != "" {
+		details, err = in.UpdateIstioConfigDetail(api, fix.Target.Namespace, resourceType, fix.Target.Name, fix.Patch)
+	} else {
+		details, err = in.CreateIstioConfigDetail(api, fix.Target.Namespace, resourceType, []byte(fix.Object))
+	}
+	return fix, details, err
+}
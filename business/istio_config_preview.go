@@ -0,0 +1,311 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+type routeDestination struct {
+	host   string
+	subset string
+}
+
+// PreviewIstioConfigDetail computes the effects of creating (name is "") or patching (name is set) an Istio object
+// without applying the change: the validations of the object and the objects related to it, the checks introduced
+// or resolved and, for VirtualServices, an estimation of the traffic shifted between destinations.
+func (in *IstioConfigService) PreviewIstioConfigDetail(namespace, resourceType, name string, body []byte) (models.IstioConfigPreview, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "PreviewIstioConfigDetail")
+	defer promtimer.ObserveNow(&err)
+
+	preview := models.IstioConfigPreview{}
+
+	var current, proposed kubernetes.IstioObject
+	if name == "" {
+		var createJson string
+		if createJson, err = in.ParseJsonForCreate(resourceType, body); err != nil {
+			return preview, errors2.NewBadRequest(err.Error())
+		}
+		proposed = &kubernetes.GenericIstioObject{}
+		if err = json.Unmarshal([]byte(createJson), proposed); err != nil {
+			return preview, errors2.NewBadRequest(err.Error())
+		}
+	} else {
+		if current, err = in.k8s.GetIstioObject(namespace, resourceType, name); err != nil {
+			return preview, err
+		}
+		if proposed, err = patchIstioObject(current, body); err != nil {
+			return preview, errors2.NewBadRequest(err.Error())
+		}
+	}
+
+	meta := proposed.GetObjectMeta()
+	if meta.Name == "" {
+		err = errors2.NewBadRequest("proposed object has no name")
+		return preview, err
+	}
+	meta.Namespace = namespace
+	proposed.SetObjectMeta(meta)
+
+	currentValidations, proposedValidations, err := in.businessLayer.Validations.GetValidationsPreview(namespace, resourceType, proposed)
+	if err != nil {
+		return preview, err
+	}
+
+	key := models.BuildKey(models.ObjectTypeSingular[resourceType], meta.Name, namespace)
+	currentValidations = currentValidations.FilterByReferences(key)
+	proposedValidations = proposedValidations.FilterByReferences(key)
+	preview.Validations = proposedValidations
+	preview.ValidationsDiff = currentValidations.Diff(proposedValidations)
+
+	if resourceType == kubernetes.VirtualServices {
+		preview.TrafficImpact, err = in.getTrafficImpact(namespace, current, proposed)
+	}
+
+	return preview, err
+}
+
+// patchIstioObject applies a JSON Merge Patch on a copy of the object
+func patchIstioObject(object kubernetes.IstioObject, jsonPatch []byte) (kubernetes.IstioObject, error) {
+	var patch interface{}
+	if err := json.Unmarshal(jsonPatch, &patch); err != nil {
+		return nil, err
+	}
+
+	marshalled, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	var target interface{}
+	if err = json.Unmarshal(marshalled, &target); err != nil {
+		return nil, err
+	}
+
+	patched, err := json.Marshal(util.MergePatch(target, patch))
+	if err != nil {
+		return nil, err
+	}
+	result := &kubernetes.GenericIstioObject{}
+	if err = json.Unmarshal(patched, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// getTrafficImpact estimates how the requests currently received by the VirtualService hosts
+// would be distributed among the route destinations before and after the change.
+// Requests are assumed to be evenly distributed among the route rules, as matches can't be evaluated.
+func (in *IstioConfigService) getTrafficImpact(namespace string, current, proposed kubernetes.IstioObject) ([]models.TrafficShift, error) {
+	shifts := make([]models.TrafficShift, 0)
+
+	currentWeights := routeWeights(current, namespace)
+	proposedWeights := routeWeights(proposed, namespace)
+
+	rate, err := in.getHostsRequestRate(namespace, append(virtualServiceHosts(current), virtualServiceHosts(proposed)...))
+	if err != nil {
+		return shifts, err
+	}
+
+	var destinationRules []kubernetes.IstioObject
+	if IsResourceCached(namespace, kubernetes.DestinationRules) {
+		destinationRules, err = kialiCache.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
+	} else {
+		destinationRules, err = in.k8s.GetIstioObjects(namespace, kubernetes.DestinationRules, "")
+	}
+	if err != nil {
+		return shifts, err
+	}
+	workloads, err := in.businessLayer.Workload.GetWorkloadList(namespace)
+	if err != nil {
+		return shifts, err
+	}
+
+	destinations := map[routeDestination]bool{}
+	for d := range currentWeights {
+		destinations[d] = true
+	}
+	for d := range proposedWeights {
+		destinations[d] = true
+	}
+
+	for d := range destinations {
+		shifts = append(shifts, models.TrafficShift{
+			Host:           d.host,
+			Subset:         d.subset,
+			Workloads:      destinationWorkloads(d, namespace, destinationRules, workloads),
+			CurrentWeight:  currentWeights[d],
+			ProposedWeight: proposedWeights[d],
+			CurrentRate:    rate * currentWeights[d] / 100,
+			ProposedRate:   rate * proposedWeights[d] / 100,
+		})
+	}
+
+	sort.Slice(shifts, func(i, j int) bool {
+		if shifts[i].Host == shifts[j].Host {
+			return shifts[i].Subset < shifts[j].Subset
+		}
+		return shifts[i].Host < shifts[j].Host
+	})
+
+	return shifts, nil
+}
+
+// getHostsRequestRate sums the rate of requests, as reported by the clients, sent to the services of the namespace
+func (in *IstioConfigService) getHostsRequestRate(namespace string, hosts []string) (float64, error) {
+	rate := 0.0
+	if in.prom == nil {
+		return rate, nil
+	}
+
+	services := map[string]bool{}
+	for _, h := range hosts {
+		host := kubernetes.ParseHost(h, namespace, "")
+		if host.CompleteInput && host.Namespace == namespace {
+			services[host.Service] = true
+		}
+	}
+
+	for svc := range services {
+		vector, err := in.prom.GetServiceRequestRates(namespace, svc, "1m", util.Clock.Now())
+		if err != nil {
+			return rate, err
+		}
+		for _, sample := range vector {
+			if sample.Metric["reporter"] == "source" {
+				rate += float64(sample.Value)
+			}
+		}
+	}
+	return rate, nil
+}
+
+func virtualServiceHosts(vs kubernetes.IstioObject) []string {
+	hosts := make([]string, 0)
+	if vs == nil {
+		return hosts
+	}
+	if hs, ok := vs.GetSpec()["hosts"].([]interface{}); ok {
+		for _, h := range hs {
+			if host, ok := h.(string); ok {
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	return hosts
+}
+
+// routeWeights returns the share of requests (0-100) received by each destination of a VirtualService
+func routeWeights(vs kubernetes.IstioObject, namespace string) map[routeDestination]float64 {
+	weights := map[routeDestination]float64{}
+	if vs == nil {
+		return weights
+	}
+
+	// Redirect and direct response routes don't send requests to any destination
+	routes := make([][]interface{}, 0)
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		if rs, ok := vs.GetSpec()[protocol].([]interface{}); ok {
+			for _, r := range rs {
+				if route, ok := r.(map[string]interface{}); ok {
+					if dws, ok := route["route"].([]interface{}); ok && len(dws) > 0 {
+						routes = append(routes, dws)
+					}
+				}
+			}
+		}
+	}
+
+	for _, dws := range routes {
+		for _, dw := range dws {
+			destinationWeight, ok := dw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			destination, ok := destinationWeight["destination"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			host, ok := destination["host"].(string)
+			if !ok {
+				continue
+			}
+			subset, _ := destination["subset"].(string)
+
+			// A single destination without weight receives all the requests
+			weight := 100.0
+			if w, ok := destinationWeight["weight"]; ok {
+				weight = toFloat(w)
+			} else if len(dws) > 1 {
+				weight = 0
+			}
+
+			d := routeDestination{host: kubernetes.ParseHost(host, namespace, "").String(), subset: subset}
+			weights[d] += weight / float64(len(routes))
+		}
+	}
+
+	return weights
+}
+
+// destinationWorkloads returns the workloads matching the host service name and the subset labels
+func destinationWorkloads(d routeDestination, namespace string, destinationRules []kubernetes.IstioObject, workloads models.WorkloadList) []string {
+	names := make([]string, 0)
+	host := kubernetes.ParseHost(d.host, namespace, "")
+	if !host.CompleteInput || host.Namespace != namespace {
+		return names
+	}
+
+	selector := labels.Set{config.Get().IstioLabels.AppLabelName: host.Service}
+	if d.subset != "" {
+		for _, dr := range destinationRules {
+			drHost, ok := dr.GetSpec()["host"].(string)
+			if !ok || kubernetes.ParseHost(drHost, namespace, "").String() != host.String() {
+				continue
+			}
+			if subsets, ok := dr.GetSpec()["subsets"].([]interface{}); ok {
+				for _, s := range subsets {
+					subset, ok := s.(map[string]interface{})
+					if !ok || subset["name"] != d.subset {
+						continue
+					}
+					if sLabels, ok := subset["labels"].(map[string]interface{}); ok {
+						for k, v := range sLabels {
+							selector[k] = fmt.Sprintf("%v", v)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, wk := range workloads.Workloads {
+		if labels.SelectorFromSet(selector).Matches(labels.Set(wk.Labels)) {
+			names = append(names, wk.Name)
+		}
+	}
+	return names
+}
+
+func toFloat(value interface{}) float64 {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestPatchIstioObject(t *testing.T) {
+	assert := assert.New(t)
+
+	vs := data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", -1),
+		data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"}))
+
+	patched, err := patchIstioObject(vs, []byte(`{"spec":{"http":[{"route":[{"destination":{"host":"reviews","subset":"v2"}}]}]}}`))
+	assert.NoError(err)
+	assert.Equal("reviews", patched.GetObjectMeta().Name)
+	assert.Equal("bookinfo", patched.GetObjectMeta().Namespace)
+	assert.Equal([]interface{}{"reviews"}, patched.GetSpec()["hosts"])
+	route := patched.GetSpec()["http"].([]interface{})[0].(map[string]interface{})["route"].([]interface{})
+	assert.Equal("v2", route[0].(map[string]interface{})["destination"].(map[string]interface{})["subset"])
+
+	// The original object is left untouched
+	route = vs.GetSpec()["http"].([]interface{})[0].(map[string]interface{})["route"].([]interface{})
+	assert.Equal("v1", route[0].(map[string]interface{})["destination"].(map[string]interface{})["subset"])
+
+	_, err = patchIstioObject(vs, []byte(`{"spec":`))
+	assert.Error(err)
+}
+
+func TestRouteWeights(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	vs := data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v2", 20),
+		data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", 80),
+			data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})))
+	vs.GetSpec()["http"] = append(vs.GetSpec()["http"].([]interface{}), map[string]interface{}{
+		"route": []interface{}{data.CreateRoute("reviews", "v3", -1)},
+	}, map[string]interface{}{
+		"redirect": map[string]interface{}{"uri": "/v4"},
+	})
+
+	weights := routeWeights(vs, "bookinfo")
+	assert.Len(weights, 3)
+	assert.Equal(40.0, weights[routeDestination{host: "reviews.bookinfo.svc.cluster.local", subset: "v1"}])
+	assert.Equal(10.0, weights[routeDestination{host: "reviews.bookinfo.svc.cluster.local", subset: "v2"}])
+	assert.Equal(50.0, weights[routeDestination{host: "reviews.bookinfo.svc.cluster.local", subset: "v3"}])
+
+	assert.Empty(routeWeights(nil, "bookinfo"))
+}
+
+func TestDestinationWorkloads(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	drs := []kubernetes.IstioObject{
+		data.AddSubsetToDestinationRule(data.CreateSubset("v1", "v1"),
+			data.AddSubsetToDestinationRule(data.CreateSubset("v2", "v2"),
+				data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews"))),
+	}
+	workloads := data.CreateWorkloadList("bookinfo",
+		data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+		data.CreateWorkloadListItem("reviews-v2", map[string]string{"app": "reviews", "version": "v2"}),
+		data.CreateWorkloadListItem("ratings-v1", map[string]string{"app": "ratings", "version": "v1"}),
+	)
+
+	assert.Equal([]string{"reviews-v1"}, destinationWorkloads(routeDestination{host: "reviews", subset: "v1"}, "bookinfo", drs, workloads))
+	assert.Equal([]string{"reviews-v1", "reviews-v2"}, destinationWorkloads(routeDestination{host: "reviews.bookinfo.svc.cluster.local"}, "bookinfo", drs, workloads))
+	assert.Empty(destinationWorkloads(routeDestination{host: "reviews.other.svc.cluster.local"}, "bookinfo", drs, workloads))
+}
+
+func TestPreviewIstioConfigDetailUnsupportedType(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	_, err := layer.IstioConfig.PreviewIstioConfigDetail("bookinfo", kubernetes.EnvoyFilters, "", []byte(`{"metadata":{"name":"filter"}}`))
+	assert.True(errors.IsBadRequest(err))
+}
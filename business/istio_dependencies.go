@@ -0,0 +1,407 @@
+package business
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// GetIstioDependencyGraph returns the references between the Istio objects of the namespace and the objects they depend on:
+// Gateways, DestinationRules, Services, ServiceEntries, WorkloadEntries and workloads.
+// Gateways and DestinationRules of other namespaces are included when referenced, as well as the VirtualServices of
+// other namespaces referencing objects of this namespace, i.e. binding one of its Gateways.
+func (in *IstioValidationsService) GetIstioDependencyGraph(namespace string) (models.IstioDependencyGraph, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetIstioDependencyGraph")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return models.IstioDependencyGraph{}, err
+	}
+
+	var istioDetails kubernetes.IstioDetails
+	var services []core_v1.Service
+	var namespaces models.Namespaces
+	var workloads models.WorkloadList
+	var gatewaysPerNamespace [][]kubernetes.IstioObject
+	var mtlsDetails kubernetes.MTLSDetails
+	var rbacDetails kubernetes.RBACDetails
+	var workloadEntries []kubernetes.IstioObject
+	var virtualServicesPerNamespace [][]kubernetes.IstioObject
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	wg.Add(9)
+	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
+	go in.fetchServices(&services, namespace, errChan, &wg)
+	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchWorkloads(&workloads, namespace, errChan, &wg)
+	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &wg)
+	go in.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
+	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
+	go fetchIstioObjects(&workloadEntries, namespace, func(namespace string) ([]kubernetes.IstioObject, error) {
+		return in.k8s.GetIstioObjects(namespace, kubernetes.WorkloadEntries, "")
+	}, &wg, errChan)
+	go in.fetchOtherVirtualServices(&virtualServicesPerNamespace, namespace, errChan, &wg)
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return models.IstioDependencyGraph{}, err
+		}
+	}
+
+	gateways := []kubernetes.IstioObject{}
+	for _, gws := range gatewaysPerNamespace {
+		gateways = append(gateways, gws...)
+	}
+
+	otherVirtualServices := []kubernetes.IstioObject{}
+	for _, vss := range virtualServicesPerNamespace {
+		otherVirtualServices = append(otherVirtualServices, vss...)
+	}
+
+	builder := dependencyGraphBuilder{
+		namespace:            namespace,
+		namespaces:           namespaces.GetNames(),
+		services:             services,
+		workloads:            workloads,
+		gateways:             gateways,
+		destinationRules:     append(append([]kubernetes.IstioObject{}, istioDetails.DestinationRules...), mtlsDetails.DestinationRules...),
+		serviceEntries:       istioDetails.ServiceEntries,
+		workloadEntries:      workloadEntries,
+		otherVirtualServices: otherVirtualServices,
+	}
+	return builder.build(istioDetails, mtlsDetails.PeerAuthentications, rbacDetails.AuthorizationPolicies), nil
+}
+
+// fetchOtherVirtualServices fetches the VirtualServices of the accessible namespaces other than the given one
+func (in *IstioValidationsService) fetchOtherVirtualServices(virtualServicesPerNamespace *[][]kubernetes.IstioObject, namespace string, errChan chan error, wg *sync.WaitGroup) {
+	defer wg.Done()
+	nss, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		select {
+		case errChan <- err:
+		default:
+		}
+		return
+	}
+
+	vsss := make([][]kubernetes.IstioObject, len(nss))
+	*virtualServicesPerNamespace = vsss
+	for i, ns := range nss {
+		if ns.Name == namespace {
+			continue
+		}
+		// businessLayer.Namespace.GetNamespaces() is invoked before, so, namespace used are under the user's view
+		if IsResourceCached(ns.Name, kubernetes.VirtualServices) {
+			if vsss[i], err = kialiCache.GetIstioObjects(ns.Name, kubernetes.VirtualServices, ""); err != nil {
+				select {
+				case errChan <- err:
+				default:
+				}
+			}
+			continue
+		}
+		wg.Add(1)
+		go fetchIstioObjects(&vsss[i], ns.Name, func(namespace string) ([]kubernetes.IstioObject, error) {
+			return in.k8s.GetIstioObjects(namespace, kubernetes.VirtualServices, "")
+		}, wg, errChan)
+	}
+}
+
+// dependencyGraphBuilder resolves the references declared by the Istio objects of a namespace
+type dependencyGraphBuilder struct {
+	namespace        string
+	namespaces       []string
+	services         []core_v1.Service
+	workloads        models.WorkloadList
+	gateways         []kubernetes.IstioObject
+	destinationRules []kubernetes.IstioObject
+	serviceEntries   []kubernetes.IstioObject
+	workloadEntries  []kubernetes.IstioObject
+	// VirtualServices of the other namespaces, only part of the graph by their references to this namespace
+	otherVirtualServices []kubernetes.IstioObject
+	edges                []models.IstioDependencyEdge
+}
+
+func (b *dependencyGraphBuilder) build(istioDetails kubernetes.IstioDetails, peerAuthns, authPolicies []kubernetes.IstioObject) models.IstioDependencyGraph {
+	b.edges = []models.IstioDependencyEdge{}
+	objects := []models.IstioValidationKey{}
+	addObjects := func(objectType string, objs []kubernetes.IstioObject) {
+		for _, obj := range objs {
+			if obj.GetObjectMeta().Namespace == b.namespace {
+				objects = append(objects, istioObjectKey(objectType, obj))
+			}
+		}
+	}
+
+	for _, vs := range istioDetails.VirtualServices {
+		b.addVirtualServiceEdges(vs)
+	}
+	for _, vs := range b.otherVirtualServices {
+		from := len(b.edges)
+		b.addVirtualServiceEdges(vs)
+		edges := b.edges[:from]
+		for _, edge := range b.edges[from:] {
+			if edge.Target.Namespace == b.namespace {
+				edges = append(edges, edge)
+			}
+		}
+		b.edges = edges
+	}
+	for _, dr := range istioDetails.DestinationRules {
+		if host, ok := dr.GetSpec()["host"].(string); ok {
+			b.addHostEdges(istioObjectKey("destinationrule", dr), host, dr.GetObjectMeta().Namespace, models.DependencyHost, "spec/host")
+		}
+	}
+	for _, se := range istioDetails.ServiceEntries {
+		b.addServiceEntryEdges(se)
+	}
+	for _, sc := range istioDetails.Sidecars {
+		b.addSidecarEdges(sc)
+	}
+	for _, ap := range authPolicies {
+		b.addSelectorEdges(istioObjectKey("authorizationpolicy", ap), ap, common.GetSelectorLabels(ap), "spec/selector")
+	}
+	for _, pa := range peerAuthns {
+		b.addSelectorEdges(istioObjectKey("peerauthentication", pa), pa, common.GetSelectorLabels(pa), "spec/selector")
+	}
+	for _, ra := range istioDetails.RequestAuthentications {
+		b.addSelectorEdges(istioObjectKey("requestauthentication", ra), ra, common.GetSelectorLabels(ra), "spec/selector")
+	}
+
+	addObjects("virtualservice", istioDetails.VirtualServices)
+	addObjects("destinationrule", istioDetails.DestinationRules)
+	addObjects("serviceentry", istioDetails.ServiceEntries)
+	addObjects("gateway", istioDetails.Gateways)
+	addObjects("sidecar", istioDetails.Sidecars)
+	addObjects("requestauthentication", istioDetails.RequestAuthentications)
+	addObjects("peerauthentication", peerAuthns)
+	addObjects("authorizationpolicy", authPolicies)
+	addObjects("workloadentry", b.workloadEntries)
+	for _, svc := range b.services {
+		objects = append(objects, models.BuildKey("service", svc.Name, svc.Namespace))
+	}
+	for _, wk := range b.workloads.Workloads {
+		objects = append(objects, models.BuildKey("workload", wk.Name, b.namespace))
+	}
+
+	return models.NewIstioDependencyGraph(objects, b.edges)
+}
+
+func (b *dependencyGraphBuilder) addEdge(source, target models.IstioValidationKey, kind, path string) {
+	b.edges = append(b.edges, models.IstioDependencyEdge{Source: source, Target: target, Kind: kind, Path: path})
+}
+
+// addVirtualServiceEdges links a VirtualService with its Gateways, and its route destinations with the Services,
+// ServiceEntries and DestinationRule subsets they resolve to
+func (b *dependencyGraphBuilder) addVirtualServiceEdges(vs kubernetes.IstioObject) {
+	vsKey := istioObjectKey("virtualservice", vs)
+	vsNamespace := vs.GetObjectMeta().Namespace
+
+	if gws, ok := vs.GetSpec()["gateways"].([]interface{}); ok {
+		for i, g := range gws {
+			gwName, ok := g.(string)
+			if !ok || gwName == "mesh" {
+				continue
+			}
+			gwHost := kubernetes.ParseGatewayAsHost(gwName, vsNamespace, identityDomain())
+			for _, gw := range b.gateways {
+				if gw.GetObjectMeta().Name == gwHost.Service && gw.GetObjectMeta().Namespace == gwHost.Namespace {
+					b.addEdge(vsKey, istioObjectKey("gateway", gw), models.DependencyGateway, fmt.Sprintf("spec/gateways[%d]", i))
+				}
+			}
+		}
+	}
+
+	for _, protocol := range []string{"http", "tcp", "tls"} {
+		routes, ok := vs.GetSpec()[protocol].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, r := range routes {
+			route, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			destinations, ok := route["route"].([]interface{})
+			if !ok {
+				continue
+			}
+			for j, d := range destinations {
+				destinationWeight, ok := d.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				destination, ok := destinationWeight["destination"].(map[string]interface{})
+				if !ok {
+					continue
+				}
+				host, ok := destination["host"].(string)
+				if !ok {
+					continue
+				}
+				path := fmt.Sprintf("spec/%s[%d]/route[%d]/destination", protocol, i, j)
+				b.addHostEdges(vsKey, host, vsNamespace, models.DependencyHost, path+"/host")
+				if subset, ok := destination["subset"].(string); ok && subset != "" {
+					b.addSubsetEdges(vsKey, host, vsNamespace, subset, path+"/subset")
+				}
+			}
+		}
+	}
+}
+
+// addHostEdges links an object with the Services and ServiceEntries a host resolves to, with the given kind of reference
+func (b *dependencyGraphBuilder) addHostEdges(source models.IstioValidationKey, host, namespace, kind, path string) {
+	fqdn := b.fqdn(host, namespace)
+	for _, svc := range b.services {
+		if fqdn == fmt.Sprintf("%s.%s.%s", svc.Name, svc.Namespace, identityDomain()) {
+			b.addEdge(source, models.BuildKey("service", svc.Name, svc.Namespace), kind, path)
+		}
+	}
+	for _, se := range b.serviceEntries {
+		if seHosts, ok := se.GetSpec()["hosts"].([]interface{}); ok {
+			for _, h := range seHosts {
+				if seHost, ok := h.(string); ok && (seHost == host || b.fqdn(seHost, se.GetObjectMeta().Namespace) == fqdn) {
+					b.addEdge(source, istioObjectKey("serviceentry", se), kind, path)
+					break
+				}
+			}
+		}
+	}
+}
+
+// addSubsetEdges links a route destination with the DestinationRules defining its subset
+func (b *dependencyGraphBuilder) addSubsetEdges(source models.IstioValidationKey, host, namespace, subset, path string) {
+	fqdn := b.fqdn(host, namespace)
+	seen := map[models.IstioValidationKey]bool{}
+	for _, dr := range b.destinationRules {
+		drKey := istioObjectKey("destinationrule", dr)
+		if seen[drKey] {
+			continue
+		}
+		drHost, ok := dr.GetSpec()["host"].(string)
+		if !ok || b.fqdn(drHost, dr.GetObjectMeta().Namespace) != fqdn {
+			continue
+		}
+		if subsets, ok := dr.GetSpec()["subsets"].([]interface{}); ok {
+			for _, s := range subsets {
+				if ss, ok := s.(map[string]interface{}); ok && ss["name"] == subset {
+					seen[drKey] = true
+					b.addEdge(source, drKey, models.DependencySubset, path)
+					break
+				}
+			}
+		}
+	}
+}
+
+// addServiceEntryEdges links a ServiceEntry with the WorkloadEntries selected by its workloadSelector
+func (b *dependencyGraphBuilder) addServiceEntryEdges(se kubernetes.IstioObject) {
+	selectorLabels := common.GetWorkloadSelectorLabels(se)
+	if len(selectorLabels) == 0 {
+		return
+	}
+	selector := labels.SelectorFromSet(selectorLabels)
+	for _, we := range b.workloadEntries {
+		if we.GetObjectMeta().Namespace != se.GetObjectMeta().Namespace {
+			continue
+		}
+		weLabels := map[string]string{}
+		if ls, ok := we.GetSpec()["labels"].(map[string]interface{}); ok {
+			for k, v := range ls {
+				if value, ok := v.(string); ok {
+					weLabels[k] = value
+				}
+			}
+		}
+		if selector.Matches(labels.Set(weLabels)) {
+			b.addEdge(istioObjectKey("serviceentry", se), istioObjectKey("workloadentry", we), models.DependencyWorkloadEntry, "spec/workloadSelector")
+		}
+	}
+}
+
+// addSidecarEdges links a Sidecar with the workloads it applies to and with the hosts imported by its egress listeners.
+// Wildcard hosts are not resolved.
+func (b *dependencyGraphBuilder) addSidecarEdges(sc kubernetes.IstioObject) {
+	scKey := istioObjectKey("sidecar", sc)
+	b.addSelectorEdges(scKey, sc, common.GetWorkloadSelectorLabels(sc), "spec/workloadSelector")
+
+	egress, ok := sc.GetSpec()["egress"].([]interface{})
+	if !ok {
+		return
+	}
+	for i, e := range egress {
+		listener, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		hosts, ok := listener["hosts"].([]interface{})
+		if !ok {
+			continue
+		}
+		for j, h := range hosts {
+			importedHost, ok := h.(string)
+			if !ok {
+				continue
+			}
+			// Hosts are declared as namespace/dnsName
+			hParts := strings.Split(importedHost, "/")
+			if len(hParts) != 2 || hParts[0] == "*" || hParts[0] == "~" || strings.HasPrefix(hParts[1], "*") {
+				continue
+			}
+			namespace := hParts[0]
+			if namespace == "." {
+				namespace = sc.GetObjectMeta().Namespace
+			}
+			b.addHostEdges(scKey, hParts[1], namespace, models.DependencyEgress, fmt.Sprintf("spec/egress[%d]/hosts[%d]", i, j))
+		}
+	}
+}
+
+// addSelectorEdges links an object with the workloads of its namespace selected by its labels.
+// Objects without selector apply to all the workloads of the namespace, except the mesh-wide ones of the root namespace.
+func (b *dependencyGraphBuilder) addSelectorEdges(source models.IstioValidationKey, obj kubernetes.IstioObject, selectorLabels map[string]string, path string) {
+	if obj.GetObjectMeta().Namespace != b.namespace {
+		return
+	}
+	if len(selectorLabels) == 0 {
+		if b.namespace == config.Get().IstioNamespace {
+			return
+		}
+		path = "spec"
+	}
+	selector := labels.SelectorFromSet(selectorLabels)
+	for _, wk := range b.workloads.Workloads {
+		if selector.Matches(labels.Set(wk.Labels)) {
+			b.addEdge(source, models.BuildKey("workload", wk.Name, b.namespace), models.DependencySelector, path)
+		}
+	}
+}
+
+func (b *dependencyGraphBuilder) fqdn(host, namespace string) string {
+	return kubernetes.GetHost(host, namespace, identityDomain(), b.namespaces).String()
+}
+
+func identityDomain() string {
+	return config.Get().ExternalServices.Istio.IstioIdentityDomain
+}
+
+func istioObjectKey(objectType string, obj kubernetes.IstioObject) models.IstioValidationKey {
+	return models.BuildKey(objectType, obj.GetObjectMeta().Name, obj.GetObjectMeta().Namespace)
+}
@@ -0,0 +1,125 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestBuildIstioDependencyGraph(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	gateway := data.CreateEmptyGateway("ingress", "istio-system", map[string]string{"istio": "ingressgateway"})
+	vs := data.AddGatewaysToVirtualService([]string{"istio-system/ingress", "mesh"},
+		data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", -1),
+			data.AddRoutesToVirtualService("tcp", data.CreateRoute("payments.external.com", "", -1),
+				data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"}))))
+	dr := data.AddSubsetToDestinationRule(data.CreateSubset("v1", "v1"),
+		data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews.bookinfo.svc.cluster.local"))
+	se := data.CreateEmptyMeshExternalServiceEntry("payments", "bookinfo", []string{"payments.external.com"})
+	se.GetSpec()["workloadSelector"] = map[string]interface{}{"labels": map[string]interface{}{"app": "payments"}}
+	we := (&kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "payments-vm", Namespace: "bookinfo"},
+		Spec:       map[string]interface{}{"address": "10.0.0.1", "labels": map[string]interface{}{"app": "payments"}},
+	}).DeepCopyIstioObject()
+	sc := data.AddHostsToSidecar([]interface{}{"./reviews.bookinfo.svc.cluster.local", "istio-system/*"},
+		data.AddSelectorToSidecar(map[string]interface{}{"labels": map[string]interface{}{"app": "productpage"}},
+			data.CreateSidecar("productpage", "bookinfo")))
+	ap := data.CreateAuthorizationPolicyWithRules("allow-reviews", "ALLOW", map[string]interface{}{"app": "reviews"}, nil)
+
+	builder := dependencyGraphBuilder{
+		namespace:  "bookinfo",
+		namespaces: []string{"bookinfo", "istio-system"},
+		services: []core_v1.Service{
+			{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}},
+			{ObjectMeta: meta_v1.ObjectMeta{Name: "productpage", Namespace: "bookinfo"}},
+		},
+		workloads: data.CreateWorkloadList("bookinfo",
+			data.CreateWorkloadListItem("reviews-v1", map[string]string{"app": "reviews", "version": "v1"}),
+			data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage", "version": "v1"}),
+		),
+		gateways:         []kubernetes.IstioObject{gateway},
+		destinationRules: []kubernetes.IstioObject{dr, dr},
+		serviceEntries:   []kubernetes.IstioObject{se},
+		workloadEntries:  []kubernetes.IstioObject{we},
+	}
+	graph := builder.build(kubernetes.IstioDetails{
+		VirtualServices:  []kubernetes.IstioObject{vs},
+		DestinationRules: []kubernetes.IstioObject{dr},
+		ServiceEntries:   []kubernetes.IstioObject{se},
+		Sidecars:         []kubernetes.IstioObject{sc},
+	}, nil, []kubernetes.IstioObject{ap})
+
+	vsKey := models.BuildKey("virtualservice", "reviews", "bookinfo")
+	drKey := models.BuildKey("destinationrule", "reviews", "bookinfo")
+	gwKey := models.BuildKey("gateway", "ingress", "istio-system")
+	seKey := models.BuildKey("serviceentry", "payments", "bookinfo")
+	svcKey := models.BuildKey("service", "reviews", "bookinfo")
+	scKey := models.BuildKey("sidecar", "productpage", "bookinfo")
+	apKey := models.BuildKey("authorizationpolicy", "allow-reviews", "bookinfo")
+
+	assert.ElementsMatch([]models.IstioDependencyEdge{
+		{Source: vsKey, Target: gwKey, Kind: models.DependencyGateway, Path: "spec/gateways[0]"},
+		{Source: vsKey, Target: svcKey, Kind: models.DependencyHost, Path: "spec/http[0]/route[0]/destination/host"},
+		{Source: vsKey, Target: drKey, Kind: models.DependencySubset, Path: "spec/http[0]/route[0]/destination/subset"},
+		{Source: vsKey, Target: seKey, Kind: models.DependencyHost, Path: "spec/tcp[0]/route[0]/destination/host"},
+		{Source: drKey, Target: svcKey, Kind: models.DependencyHost, Path: "spec/host"},
+		{Source: seKey, Target: models.BuildKey("workloadentry", "payments-vm", "bookinfo"), Kind: models.DependencyWorkloadEntry, Path: "spec/workloadSelector"},
+		{Source: scKey, Target: models.BuildKey("workload", "productpage-v1", "bookinfo"), Kind: models.DependencySelector, Path: "spec/workloadSelector"},
+		{Source: scKey, Target: svcKey, Kind: models.DependencyEgress, Path: "spec/egress[0]/hosts[0]"},
+		{Source: apKey, Target: models.BuildKey("workload", "reviews-v1", "bookinfo"), Kind: models.DependencySelector, Path: "spec/selector"},
+	}, graph.Edges)
+
+	// Objects without references are part of the graph too
+	assert.True(graph.HasNode(models.BuildKey("service", "productpage", "bookinfo")))
+
+	// Deleting the gateway only breaks the VirtualService
+	assert.Equal([]models.IstioValidationKey{vsKey, gwKey}, graph.Dependents(gwKey).Nodes)
+}
+
+func TestGetIstioDependencyGraphWithOtherNamespaces(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	gateway := data.CreateEmptyGateway("ingress", "istio-system", map[string]string{"istio": "ingressgateway"})
+	vs := data.AddGatewaysToVirtualService([]string{"istio-system/ingress"},
+		data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v1", -1),
+			data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})))
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("IsMaistraApi").Return(false)
+	k8s.On("GetNamespace", mock.AnythingOfType("string")).Return(&core_v1.Namespace{}, nil)
+	k8s.On("GetNamespaces", mock.AnythingOfType("string")).Return([]core_v1.Namespace{
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}},
+		{ObjectMeta: meta_v1.ObjectMeta{Name: "istio-system"}},
+	}, nil)
+	k8s.On("GetIstioObjects", "istio-system", "gateways", "").Return([]kubernetes.IstioObject{gateway}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", "virtualservices", "").Return([]kubernetes.IstioObject{vs}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), mock.AnythingOfType("string"), "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetServices", "istio-system", mock.AnythingOfType("map[string]string")).Return([]core_v1.Service{}, nil)
+	mockWorkLoadService(k8s)
+
+	vsService := IstioValidationsService{k8s: k8s, businessLayer: NewWithBackends(k8s, nil, nil)}
+	graph, err := vsService.GetIstioDependencyGraph("istio-system")
+
+	assert.NoError(err)
+	vsKey := models.BuildKey("virtualservice", "reviews", "bookinfo")
+	gwKey := models.BuildKey("gateway", "ingress", "istio-system")
+	// Only the references to the namespace are part of the graph
+	assert.Equal([]models.IstioDependencyEdge{
+		{Source: vsKey, Target: gwKey, Kind: models.DependencyGateway, Path: "spec/gateways[0]"},
+	}, graph.Edges)
+	assert.Equal([]models.IstioValidationKey{vsKey, gwKey}, graph.Dependents(gwKey).Nodes)
+}
@@ -0,0 +1,46 @@
+package business
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/status"
+)
+
+const istioReleaseCacheDuration = 5 * time.Minute
+
+// istioReleaseCache keeps the Istio release of the control plane, so validations don't query it on every request
+var istioReleaseCache = struct {
+	sync.RWMutex
+	release   string
+	fetchedAt time.Time
+}{}
+
+// validationsIstioRelease returns the Istio release the objects are validated against: the target release
+// configured to plan an upgrade or, by default, the release of the control plane. It is empty when unknown.
+func validationsIstioRelease() string {
+	if target := config.Get().KialiFeatureFlags.Validations.TargetVersion; target != "" {
+		return target
+	}
+
+	istioReleaseCache.RLock()
+	release, fetchedAt := istioReleaseCache.release, istioReleaseCache.fetchedAt
+	istioReleaseCache.RUnlock()
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) < istioReleaseCacheDuration {
+		return release
+	}
+
+	release, err := status.IstioRelease()
+	if err != nil {
+		log.Debugf("Istio release of the control plane can't be detected: %v", err)
+	}
+
+	istioReleaseCache.Lock()
+	istioReleaseCache.release = release
+	istioReleaseCache.fetchedAt = time.Now()
+	istioReleaseCache.Unlock()
+
+	return release
+}
@@ -1,21 +1,48 @@
 package business
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	apps_v1 "k8s.io/api/apps/v1"
 	core_v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business/checkers"
+	"github.com/kiali/kiali/business/checkers/authorization"
+	"github.com/kiali/kiali/business/checkers/common"
+	"github.com/kiali/kiali/business/checkers/gateways"
+	"github.com/kiali/kiali/business/checkers/proxies"
+	"github.com/kiali/kiali/business/checkers/requestauthentications"
+	workloadchecks "github.com/kiali/kiali/business/checkers/workloads"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
 )
 
+// gatewayCertificatesCacheDuration is how long the certificates read for the gateway validations are reused
+const gatewayCertificatesCacheDuration = 30 * time.Second
+
+type cachedCertificate struct {
+	certificate *gateways.SecretCertificate
+	readable    bool
+	fetchedAt   time.Time
+}
+
+// gatewayCertificatesCache keeps the certificate data of the Secrets read for the gateway validations, so Secrets
+// are not read on every validation. No other Secret data is kept.
+// Entries are indexed by a hash of the token and by namespace/name, as the access to the Secrets depends on the user.
+var gatewayCertificatesCache = struct {
+	sync.Mutex
+	entries map[string]map[string]cachedCertificate
+}{entries: map[string]map[string]cachedCertificate{}}
+
 type IstioValidationsService struct {
 	k8s           kubernetes.ClientInterface
 	businessLayer *Layer
@@ -34,7 +61,8 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 
 	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
 	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
-	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+	ns, err := in.businessLayer.Namespace.GetNamespace(namespace)
+	if err != nil {
 		return nil, err
 	}
 
@@ -58,13 +86,20 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	var mtlsDetails kubernetes.MTLSDetails
 	var rbacDetails kubernetes.RBACDetails
 	var deployments []apps_v1.Deployment
+	var legacyObjects map[string][]kubernetes.IstioObject
 
-	wg.Add(8) // We need to add these here to make sure we don't execute wg.Wait() before scheduler has started goroutines
+	wg.Add(9) // We need to add these here to make sure we don't execute wg.Wait() before scheduler has started goroutines
 
 	if service != "" {
 		// These resources are not used if no service is targeted
-		wg.Add(2)
+		wg.Add(1)
 		go in.fetchDeployments(&deployments, namespace, errChan, &wg)
+	}
+	// Perf: pods are only checked by the service checkers and for the workloads in the mesh. They are fetched upfront
+	// for injected namespaces, otherwise once the workloads tell whether some has a sidecar.
+	podsFetched := service != "" || workloadchecks.IsInjectionEnabled(*ns)
+	if podsFetched {
+		wg.Add(1)
 		go in.fetchPods(&pods, namespace, errChan, &wg)
 	}
 
@@ -77,6 +112,7 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	go in.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
 	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
 	go in.fetchServices(&services, namespace, errChan, &wg)
+	go in.fetchLegacyObjects(&legacyObjects, namespace, &wg)
 
 	wg.Wait()
 	close(errChan)
@@ -86,14 +122,31 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 		}
 	}
 
-	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces)
+	if !podsFetched && hasSidecar(workloads) {
+		if pods, err = in.getPods(namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	gatewayCertificates := in.fetchGatewayCertificates(istioDetails.Gateways, workloadsPerNamespace)
+	jwks := fetchJwks(istioDetails.RequestAuthentications)
+	serviceAccounts := map[string][]string{}
+	in.fetchServiceAccounts(rbacDetails.AuthorizationPolicies, namespaces, serviceAccounts)
+	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, gatewayCertificates, jwks, serviceAccounts)
 
+	objects := validatedObjects(istioDetails, gatewaysPerNamespace, mtlsDetails, rbacDetails)
+	for objectType, list := range legacyObjects {
+		objects[objectType] = list
+	}
+
+	objectCheckers = append(objectCheckers, in.getMeshReadinessChecker(namespace, namespaces, services, workloads, pods, istioDetails.VirtualServices))
+	objectCheckers = append(objectCheckers, checkers.VersionProfileChecker{Namespace: namespace, Release: validationsIstioRelease(), Objects: objects})
 	if service != "" {
 		objectCheckers = append(objectCheckers, in.getServiceCheckers(namespace, services, deployments, pods)...)
 	}
 
 	// Get group validations for same kind istio objects
-	validations := runObjectCheckers(objectCheckers)
+	validations := suppressChecks(runObjectCheckers(objectCheckers), objects, services)
 	if service != "" {
 		validations = validations.FilterBySingleType("service", service)
 	}
@@ -101,26 +154,54 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 	return validations, nil
 }
 
+// hasSidecar checks whether some workload has a sidecar
+func hasSidecar(workloads models.WorkloadList) bool {
+	for _, wk := range workloads.Workloads {
+		if wk.IstioSidecar {
+			return true
+		}
+	}
+	return false
+}
+
 func (in *IstioValidationsService) getServiceCheckers(namespace string, services []core_v1.Service, deployments []apps_v1.Deployment, pods []core_v1.Pod) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.ServiceChecker{Services: services, Deployments: deployments, Pods: pods},
 	}
 }
 
-func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace) []ObjectChecker {
+func (in *IstioValidationsService) getMeshReadinessChecker(namespace string, namespaces models.Namespaces, services []core_v1.Service, workloads models.WorkloadList, pods []core_v1.Pod, virtualServices []kubernetes.IstioObject) ObjectChecker {
+	ns := models.Namespace{Name: namespace}
+	for _, n := range namespaces {
+		if n.Name == namespace {
+			ns = n
+		}
+	}
+	return checkers.MeshReadinessChecker{Namespace: ns, Services: services, WorkloadList: workloads, Pods: pods, VirtualServices: virtualServices}
+}
+
+func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, gatewayCertificates map[string]*gateways.SecretCertificate, jwks map[string]requestauthentications.JwksStatus, serviceAccounts map[string][]string) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.NoServiceChecker{Namespace: namespace, Namespaces: namespaces, IstioDetails: &istioDetails, Services: services, WorkloadList: workloads, GatewaysPerNamespace: gatewaysPerNamespace, AuthorizationDetails: &rbacDetails},
 		checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, VirtualServices: istioDetails.VirtualServices},
 		checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries},
-		checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace},
-		checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads},
+		checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace, Certificates: gatewayCertificates},
+		checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads, Services: services},
+		portMtlsConflictChecker(mtlsDetails, services, workloads),
 		checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries},
-		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries, WorkloadList: workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices},
+		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries, WorkloadList: workloads, ServiceAccounts: serviceAccounts, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices},
 		checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces, WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries},
-		checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads},
+		checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, AuthorizationPolicies: rbacDetails.AuthorizationPolicies, MeshAuthorizationPolicies: rbacDetails.MeshAuthorizationPolicies, WorkloadList: workloads, Jwks: jwks},
 	}
 }
 
+// portMtlsConflictChecker compares the port-level mTLS of the PeerAuthentications to the DestinationRules of all the
+// namespaces. It reports both object types, so it runs once rather than from each of their checkers.
+func portMtlsConflictChecker(mtlsDetails kubernetes.MTLSDetails, services []core_v1.Service, workloads models.WorkloadList) ObjectChecker {
+	return common.PortMtlsConflictChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MeshPeerAuthentications: mtlsDetails.MeshPeerAuthentications,
+		DestinationRules: mtlsDetails.DestinationRules, Services: services, WorkloadList: workloads}
+}
+
 func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, objectType string, object string) (models.IstioValidations, error) {
 	var err error
 	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetIstioObjectValidations")
@@ -162,36 +243,44 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 
 	switch objectType {
 	case kubernetes.Gateways:
+		gatewayCertificates := in.fetchGatewayCertificates(istioDetails.Gateways, workloadsPerNamespace)
 		objectCheckers = []ObjectChecker{
-			checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace},
+			checkers.GatewayChecker{GatewaysPerNamespace: gatewaysPerNamespace, Namespace: namespace, WorkloadsPerNamespace: workloadsPerNamespace, Certificates: gatewayCertificates},
 		}
 	case kubernetes.VirtualServices:
 		virtualServiceChecker := checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, VirtualServices: istioDetails.VirtualServices, DestinationRules: istioDetails.DestinationRules}
 		objectCheckers = []ObjectChecker{noServiceChecker, virtualServiceChecker}
 	case kubernetes.DestinationRules:
 		destinationRulesChecker := checkers.DestinationRulesChecker{Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, MTLSDetails: mtlsDetails, ServiceEntries: istioDetails.ServiceEntries}
-		objectCheckers = []ObjectChecker{noServiceChecker, destinationRulesChecker}
+		objectCheckers = []ObjectChecker{noServiceChecker, destinationRulesChecker, portMtlsConflictChecker(mtlsDetails, services, workloads)}
 	case kubernetes.ServiceEntries:
 		serviceEntryChecker := checkers.ServiceEntryChecker{ServiceEntries: istioDetails.ServiceEntries}
 		objectCheckers = []ObjectChecker{serviceEntryChecker}
 	case kubernetes.Sidecars:
+		// Outbound traffic is only compared when a single Sidecar is validated, as it requires querying Prometheus
+		outboundHosts, e := in.businessLayer.IstioConfig.GetOutboundHosts(namespace, "")
+		if e != nil {
+			log.Warningf("Sidecar %s/%s can't be validated against the observed traffic: %v", namespace, object, e)
+		}
 		sidecarsChecker := checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces,
-			WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries}
+			WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries, OutboundHosts: outboundHosts}
 		objectCheckers = []ObjectChecker{sidecarsChecker}
 	case kubernetes.AuthorizationPolicies:
+		serviceAccounts := map[string][]string{}
+		in.fetchServiceAccounts(rbacDetails.AuthorizationPolicies, namespaces, serviceAccounts)
 		authPoliciesChecker := checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies,
 			Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries,
-			WorkloadList: workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices}
+			WorkloadList: workloads, ServiceAccounts: serviceAccounts, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices}
 		objectCheckers = []ObjectChecker{authPoliciesChecker}
 	case kubernetes.PeerAuthentications:
 		// Validations on PeerAuthentications
-		peerAuthnChecker := checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads}
-		objectCheckers = []ObjectChecker{peerAuthnChecker}
+		peerAuthnChecker := checkers.PeerAuthenticationChecker{PeerAuthentications: mtlsDetails.PeerAuthentications, MTLSDetails: mtlsDetails, WorkloadList: workloads, Services: services}
+		objectCheckers = []ObjectChecker{peerAuthnChecker, portMtlsConflictChecker(mtlsDetails, services, workloads)}
 	case kubernetes.WorkloadEntries:
 		// Validation on WorkloadEntries are not yet in place
 	case kubernetes.RequestAuthentications:
-		// Validation on RequestAuthentications are not yet in place
-		requestAuthnChecker := checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads}
+		requestAuthnChecker := checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, AuthorizationPolicies: rbacDetails.AuthorizationPolicies, MeshAuthorizationPolicies: rbacDetails.MeshAuthorizationPolicies,
+			WorkloadList: workloads, Jwks: fetchJwks(istioDetails.RequestAuthentications)}
 		objectCheckers = []ObjectChecker{requestAuthnChecker}
 	case kubernetes.EnvoyFilters:
 		// Validation on EnvoyFilters are not yet in place
@@ -210,7 +299,359 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 		return models.IstioValidations{}, err
 	}
 
-	return runObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), nil
+	objects := validatedObjects(istioDetails, gatewaysPerNamespace, mtlsDetails, rbacDetails)
+	objectCheckers = append(objectCheckers, checkers.VersionProfileChecker{Namespace: namespace, Release: validationsIstioRelease(), Objects: objects})
+	validations := suppressChecks(runObjectCheckers(objectCheckers), objects, services)
+	return validations.FilterByKey(models.ObjectTypeSingular[objectType], object), nil
+}
+
+// GetValidationsPreview validates the namespace as it is and as it would be if the proposed object was applied.
+// The proposed object replaces the existing one with the same name or it is added as a new object.
+func (in *IstioValidationsService) GetValidationsPreview(namespace, objectType string, proposed kubernetes.IstioObject) (models.IstioValidations, models.IstioValidations, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetValidationsPreview")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, nil, err
+	}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	var istioDetails kubernetes.IstioDetails
+	var services []core_v1.Service
+	var namespaces models.Namespaces
+	var workloads models.WorkloadList
+	var workloadsPerNamespace map[string]models.WorkloadList
+	var gatewaysPerNamespace [][]kubernetes.IstioObject
+	var mtlsDetails kubernetes.MTLSDetails
+	var rbacDetails kubernetes.RBACDetails
+
+	wg.Add(8)
+	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
+	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchWorkloads(&workloads, namespace, errChan, &wg)
+	go in.fetchAllWorkloads(&workloadsPerNamespace, errChan, &wg)
+	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &wg)
+	go in.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
+	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
+	go in.fetchServices(&services, namespace, errChan, &wg)
+
+	wg.Wait()
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return nil, nil, err
+		}
+	}
+
+	gatewayCertificates := in.fetchGatewayCertificates(istioDetails.Gateways, workloadsPerNamespace)
+	// The preview doesn't fetch any JWKS, so that proposed objects can't trigger requests to arbitrary URIs
+	jwks := cachedJwksOf(istioDetails.RequestAuthentications)
+	serviceAccounts := map[string][]string{}
+	in.fetchServiceAccounts(rbacDetails.AuthorizationPolicies, namespaces, serviceAccounts)
+	release := validationsIstioRelease()
+	currentObjects := validatedObjects(istioDetails, gatewaysPerNamespace, mtlsDetails, rbacDetails)
+	currentCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, gatewayCertificates, jwks, serviceAccounts)
+	currentCheckers = append(currentCheckers, checkers.VersionProfileChecker{Namespace: namespace, Release: release, Objects: currentObjects})
+	current := suppressChecks(runObjectCheckers(currentCheckers), currentObjects, services)
+
+	// Checkers receive copies of the object lists, so the original ones are not modified
+	switch objectType {
+	case kubernetes.Gateways:
+		istioDetails.Gateways = replaceIstioObject(istioDetails.Gateways, proposed)
+		gwss := make([][]kubernetes.IstioObject, 0, len(gatewaysPerNamespace)+1)
+		found := false
+		for _, gws := range gatewaysPerNamespace {
+			if len(gws) > 0 && gws[0].GetObjectMeta().Namespace == namespace {
+				gws = replaceIstioObject(gws, proposed)
+				found = true
+			}
+			gwss = append(gwss, gws)
+		}
+		if !found {
+			gwss = append(gwss, []kubernetes.IstioObject{proposed})
+		}
+		gatewaysPerNamespace = gwss
+		for key, certificate := range in.fetchGatewayCertificates([]kubernetes.IstioObject{proposed}, workloadsPerNamespace) {
+			gatewayCertificates[key] = certificate
+		}
+	case kubernetes.VirtualServices:
+		istioDetails.VirtualServices = replaceIstioObject(istioDetails.VirtualServices, proposed)
+	case kubernetes.DestinationRules:
+		istioDetails.DestinationRules = replaceIstioObject(istioDetails.DestinationRules, proposed)
+		mtlsDetails.DestinationRules = replaceIstioObject(mtlsDetails.DestinationRules, proposed)
+	case kubernetes.ServiceEntries:
+		istioDetails.ServiceEntries = replaceIstioObject(istioDetails.ServiceEntries, proposed)
+	case kubernetes.Sidecars:
+		istioDetails.Sidecars = replaceIstioObject(istioDetails.Sidecars, proposed)
+	case kubernetes.AuthorizationPolicies:
+		rbacDetails.AuthorizationPolicies = replaceIstioObject(rbacDetails.AuthorizationPolicies, proposed)
+		in.fetchServiceAccounts([]kubernetes.IstioObject{proposed}, namespaces, serviceAccounts)
+	case kubernetes.PeerAuthentications:
+		mtlsDetails.PeerAuthentications = replaceIstioObject(mtlsDetails.PeerAuthentications, proposed)
+		if namespace == config.Get().IstioNamespace {
+			mtlsDetails.MeshPeerAuthentications = replaceIstioObject(mtlsDetails.MeshPeerAuthentications, proposed)
+		}
+	case kubernetes.RequestAuthentications:
+		istioDetails.RequestAuthentications = replaceIstioObject(istioDetails.RequestAuthentications, proposed)
+		for uri, status := range cachedJwksOf([]kubernetes.IstioObject{proposed}) {
+			jwks[uri] = status
+		}
+	default:
+		err = fmt.Errorf("object type not found: %v", objectType)
+		return nil, nil, err
+	}
+
+	// Proposed objects are checked against the Istio release too, as they may use fields not supported yet
+	objects := validatedObjects(istioDetails, gatewaysPerNamespace, mtlsDetails, rbacDetails)
+	previewCheckers := in.getAllObjectCheckers(namespace, istioDetails, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, gatewayCertificates, jwks, serviceAccounts)
+	previewCheckers = append(previewCheckers, checkers.VersionProfileChecker{Namespace: namespace, Release: release, Objects: objects})
+	preview := suppressChecks(runObjectCheckers(previewCheckers), objects, services)
+
+	return current, preview, nil
+}
+
+// replaceIstioObject returns a copy of objects where the object with the same name and namespace
+// than replacement is replaced. The replacement is appended if there is no such object.
+func replaceIstioObject(objects []kubernetes.IstioObject, replacement kubernetes.IstioObject) []kubernetes.IstioObject {
+	replaced := make([]kubernetes.IstioObject, 0, len(objects)+1)
+	found := false
+	for _, o := range objects {
+		if o.GetObjectMeta().Name == replacement.GetObjectMeta().Name && o.GetObjectMeta().Namespace == replacement.GetObjectMeta().Namespace {
+			replaced = append(replaced, replacement)
+			found = true
+		} else {
+			replaced = append(replaced, o)
+		}
+	}
+	if !found {
+		replaced = append(replaced, replacement)
+	}
+	return replaced
+}
+
+// GetProxyConfigValidations compares the Istio configuration of the namespace with the configuration
+// actually applied in the proxy of a workload. If pod is "", the first pod of the workload with a sidecar is used.
+// Drift is reported both on the workload and on the Istio objects not found in the proxy.
+func (in *IstioValidationsService) GetProxyConfigValidations(namespace, workload, pod string) (models.IstioValidations, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetProxyConfigValidations")
+	defer promtimer.ObserveNow(&err)
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	wk, err := in.businessLayer.Workload.GetWorkload(namespace, workload, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyPod := ""
+	for _, p := range wk.Pods {
+		if len(p.IstioContainers) > 0 && (pod == "" || p.Name == pod) {
+			proxyPod = p.Name
+			break
+		}
+	}
+	if proxyPod == "" {
+		err = kubernetes.NewNotFound(workload, "", "pods")
+		return nil, err
+	}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	var istioDetails kubernetes.IstioDetails
+	var namespaces models.Namespaces
+	var rbacDetails kubernetes.RBACDetails
+	var configDump *kubernetes.ConfigDump
+
+	wg.Add(4)
+	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
+	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
+	go func() {
+		defer wg.Done()
+		cd, e := in.businessLayer.ProxyStatus.GetConfigDump(namespace, proxyPod)
+		if e != nil {
+			select {
+			case errChan <- e:
+			default:
+			}
+		} else {
+			configDump = cd
+		}
+	}()
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			err = e
+			return nil, err
+		}
+	}
+
+	wkItem := models.WorkloadListItem{}
+	wkItem.ParseWorkload(wk)
+
+	validations := proxies.ConfigDriftChecker{
+		Namespace:             namespace,
+		Namespaces:            namespaces.GetNames(),
+		Workload:              wkItem,
+		ConfigDump:            configDump,
+		VirtualServices:       istioDetails.VirtualServices,
+		DestinationRules:      istioDetails.DestinationRules,
+		AuthorizationPolicies: rbacDetails.AuthorizationPolicies,
+	}.Check()
+
+	return suppressChecks(validations, validatedObjects(istioDetails, nil, kubernetes.MTLSDetails{}, rbacDetails), nil), nil
+}
+
+// fetchGatewayCertificates reads the certificates of the Secrets referenced by the credentialName of the gateways.
+// Secrets not found are stored as nil, while Secrets that can't be read (i.e. forbidden) are left out.
+// Secrets are read once per user for gatewayCertificatesCacheDuration.
+func (in *IstioValidationsService) fetchGatewayCertificates(gws []kubernetes.IstioObject, workloadsPerNamespace map[string]models.WorkloadList) map[string]*gateways.SecretCertificate {
+	certificates := map[string]*gateways.SecretCertificate{}
+	fetched := map[string]bool{}
+
+	for _, gw := range gws {
+		for _, key := range gateways.CredentialSecrets(gw, workloadsPerNamespace) {
+			if fetched[key] {
+				continue
+			}
+			fetched[key] = true
+			entry, err := in.getGatewayCertificate(key)
+			if err != nil {
+				log.Debugf("Secret %s referenced by gateway %s/%s can't be read: %v", key, gw.GetObjectMeta().Namespace, gw.GetObjectMeta().Name, err)
+			}
+			if entry.readable {
+				certificates[key] = entry.certificate
+			}
+		}
+	}
+
+	return certificates
+}
+
+// getGatewayCertificate returns the certificate of the Secret of a namespace/name key from the cache of the user,
+// or reads the Secret. Forbidden Secrets are cached as not readable, while other errors are not cached.
+func (in *IstioValidationsService) getGatewayCertificate(key string) (cachedCertificate, error) {
+	user := fmt.Sprintf("%x", sha256.Sum256([]byte(in.k8s.GetToken())))
+	now := util.Clock.Now()
+
+	gatewayCertificatesCache.Lock()
+	entry, found := gatewayCertificatesCache.entries[user][key]
+	gatewayCertificatesCache.Unlock()
+	if found && now.Sub(entry.fetchedAt) < gatewayCertificatesCacheDuration {
+		return entry, nil
+	}
+
+	parts := strings.SplitN(key, "/", 2)
+	secret, err := in.k8s.GetSecret(parts[0], parts[1])
+	entry = cachedCertificate{fetchedAt: now}
+	if err == nil {
+		entry.certificate = gateways.NewSecretCertificate(secret)
+		entry.readable = true
+	} else if errors.IsNotFound(err) {
+		entry.readable = true
+		err = nil
+	} else if !errors.IsForbidden(err) {
+		return entry, err
+	}
+
+	gatewayCertificatesCache.Lock()
+	defer gatewayCertificatesCache.Unlock()
+	// Expired entries are dropped, so the users not validating anymore don't stay in memory
+	for u, entries := range gatewayCertificatesCache.entries {
+		for k, e := range entries {
+			if now.Sub(e.fetchedAt) >= gatewayCertificatesCacheDuration {
+				delete(entries, k)
+			}
+		}
+		if len(entries) == 0 {
+			delete(gatewayCertificatesCache.entries, u)
+		}
+	}
+	if gatewayCertificatesCache.entries[user] == nil {
+		gatewayCertificatesCache.entries[user] = map[string]cachedCertificate{}
+	}
+	gatewayCertificatesCache.entries[user][key] = entry
+	return entry, err
+}
+
+// fetchServiceAccounts reads the ServiceAccount names of the accessible namespaces referenced by the principals of the
+// policies, for the namespaces not in serviceAccounts yet. Namespaces whose ServiceAccounts can't be read are left out.
+func (in *IstioValidationsService) fetchServiceAccounts(authPolicies []kubernetes.IstioObject, namespaces models.Namespaces, serviceAccounts map[string][]string) {
+	accessible := map[string]bool{}
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+	}
+
+	for _, ns := range authorization.PrincipalsNamespaces(authPolicies) {
+		if _, found := serviceAccounts[ns]; found || !accessible[ns] {
+			continue
+		}
+		sas, err := in.k8s.GetServiceAccounts(ns)
+		if err != nil {
+			log.Debugf("ServiceAccounts of namespace %s can't be read: %v", ns, err)
+			continue
+		}
+		serviceAccounts[ns] = make([]string, 0, len(sas))
+		for _, sa := range sas {
+			serviceAccounts[ns] = append(serviceAccounts[ns], sa.Name)
+		}
+	}
+}
+
+// validatedObjects groups the Istio objects that can be validated by their object type
+func validatedObjects(istioDetails kubernetes.IstioDetails, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails) map[string][]kubernetes.IstioObject {
+	gateways := make([]kubernetes.IstioObject, 0, len(istioDetails.Gateways))
+	gateways = append(gateways, istioDetails.Gateways...)
+	for _, gws := range gatewaysPerNamespace {
+		gateways = append(gateways, gws...)
+	}
+
+	return map[string][]kubernetes.IstioObject{
+		"virtualservice":        istioDetails.VirtualServices,
+		"destinationrule":       append(append([]kubernetes.IstioObject{}, istioDetails.DestinationRules...), mtlsDetails.DestinationRules...),
+		"serviceentry":          istioDetails.ServiceEntries,
+		"gateway":               gateways,
+		"sidecar":               istioDetails.Sidecars,
+		"requestauthentication": istioDetails.RequestAuthentications,
+		"peerauthentication":    append(append([]kubernetes.IstioObject{}, mtlsDetails.PeerAuthentications...), mtlsDetails.MeshPeerAuthentications...),
+		"authorizationpolicy":   rbacDetails.AuthorizationPolicies,
+	}
+}
+
+// suppressChecks suppresses the checks ignored by the Kiali configuration or by the kiali.io/ignore-checks annotation
+// of the validated objects and services
+func suppressChecks(validations models.IstioValidations, objects map[string][]kubernetes.IstioObject, services []core_v1.Service) models.IstioValidations {
+	objectCodes := map[models.IstioValidationKey][]string{}
+	for objectType, list := range objects {
+		for _, o := range list {
+			meta := o.GetObjectMeta()
+			if annotation, found := meta.Annotations[models.IgnoreChecksAnnotation]; found {
+				objectCodes[models.BuildKey(objectType, meta.Name, meta.Namespace)] = models.ParseIgnoredChecks(annotation)
+			}
+		}
+	}
+	for _, svc := range services {
+		if annotation, found := svc.Annotations[models.IgnoreChecksAnnotation]; found {
+			objectCodes[models.BuildKey("service", svc.Name, svc.Namespace)] = models.ParseIgnoredChecks(annotation)
+		}
+	}
+
+	globalCodes := models.ParseIgnoredChecks(strings.Join(config.Get().KialiFeatureFlags.Validations.Ignore, ","))
+	return validations.SuppressChecks(globalCodes, objectCodes)
 }
 
 func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
@@ -339,15 +780,7 @@ func (in *IstioValidationsService) fetchDeployments(rValue *[]apps_v1.Deployment
 func (in *IstioValidationsService) fetchPods(rValue *[]core_v1.Pod, namespace string, errChan chan error, wg *sync.WaitGroup) {
 	defer wg.Done()
 	if len(errChan) == 0 {
-		var err error
-		var pods []core_v1.Pod
-		// Check if namespace is cached
-		// Namespace access is checked in the upper call
-		if IsNamespaceCached(namespace) {
-			pods, err = kialiCache.GetPods(namespace, "")
-		} else {
-			pods, err = in.k8s.GetPods(namespace, "")
-		}
+		pods, err := in.getPods(namespace)
 		if err != nil {
 			select {
 			case errChan <- err:
@@ -359,6 +792,15 @@ func (in *IstioValidationsService) fetchPods(rValue *[]core_v1.Pod, namespace st
 	}
 }
 
+func (in *IstioValidationsService) getPods(namespace string) ([]core_v1.Pod, error) {
+	// Check if namespace is cached
+	// Namespace access is checked in the upper call
+	if IsNamespaceCached(namespace) {
+		return kialiCache.GetPods(namespace, "")
+	}
+	return in.k8s.GetPods(namespace, "")
+}
+
 func (in *IstioValidationsService) fetchWorkloads(rValue *models.WorkloadList, namespace string, errChan chan error, wg *sync.WaitGroup) {
 	defer wg.Done()
 	if len(errChan) == 0 {
@@ -570,7 +1012,7 @@ func (in *IstioValidationsService) fetchAuthorizationDetails(rValue *kubernetes.
 		var err error
 		authDetails := &kubernetes.RBACDetails{}
 
-		innerErrChan := make(chan error, 1)
+		innerErrChan := make(chan error, 2)
 		var wg sync.WaitGroup
 		wg.Add(1)
 
@@ -587,6 +1029,26 @@ func (in *IstioValidationsService) fetchAuthorizationDetails(rValue *kubernetes.
 			}
 		}(innerErrChan)
 
+		// The policies of the root namespace apply to the workloads of every namespace
+		if rootNamespace := config.Get().IstioNamespace; namespace != rootNamespace {
+			wg.Add(1)
+			go func(errChan chan error) {
+				defer wg.Done()
+				var meshAuthPolicies []kubernetes.IstioObject
+				var err error
+				if IsResourceCached(rootNamespace, kubernetes.AuthorizationPolicies) {
+					meshAuthPolicies, err = kialiCache.GetIstioObjects(rootNamespace, kubernetes.AuthorizationPolicies, "")
+				} else {
+					meshAuthPolicies, err = in.k8s.GetIstioObjects(rootNamespace, kubernetes.AuthorizationPolicies, "")
+				}
+				if err == nil {
+					authDetails.MeshAuthorizationPolicies = meshAuthPolicies
+				} else if !checkForbidden("GetMeshAuthorizationPolicies", err, "probably Kiali doesn't have cluster permissions") {
+					errChan <- err
+				}
+			}(innerErrChan)
+		}
+
 		wg.Wait()
 		close(innerErrChan)
 
@@ -649,3 +1111,38 @@ func checkForbidden(caller string, err error, context string) bool {
 	}
 	return false
 }
+
+// fetchLegacyObjects reads the leftovers of the authentication and RBAC APIs removed in Istio 1.6, indexed by object type.
+// Cluster-scoped objects are read along the Istio namespace. Nothing is read when the legacy APIs are not served anymore.
+// Errors are only logged, as the leftovers are not required by the rest of the validations.
+func (in *IstioValidationsService) fetchLegacyObjects(rValue *map[string][]kubernetes.IstioObject, namespace string, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if !in.k8s.HasLegacyResources() {
+		return
+	}
+
+	resourceNamespaces := map[string]string{
+		kubernetes.Policies:            namespace,
+		kubernetes.ServiceRoles:        namespace,
+		kubernetes.ServiceRoleBindings: namespace,
+	}
+	if namespace == config.Get().IstioNamespace {
+		resourceNamespaces[kubernetes.MeshPolicies] = ""
+		resourceNamespaces[kubernetes.ClusterRbacConfigs] = ""
+		resourceNamespaces[kubernetes.RbacConfigs] = ""
+	}
+
+	legacyObjects := map[string][]kubernetes.IstioObject{}
+	for resourceType, ns := range resourceNamespaces {
+		objects, err := in.k8s.GetIstioObjects(ns, resourceType, "")
+		if err != nil {
+			log.Warningf("Legacy %s of namespace [%s] can't be read: %v", resourceType, namespace, err)
+			continue
+		}
+		if len(objects) > 0 {
+			legacyObjects[models.ObjectTypeSingular[resourceType]] = objects
+		}
+	}
+	*rValue = legacyObjects
+}
@@ -1,7 +1,9 @@
 package business
 
 import (
+	"sync"
 	"testing"
+	"time"
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
 	"github.com/stretchr/testify/assert"
@@ -10,13 +12,16 @@ import (
 	batch_v1 "k8s.io/api/batch/v1"
 	batch_v1beta1 "k8s.io/api/batch/v1beta1"
 	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/util"
 )
 
 func TestGetNamespaceValidations(t *testing.T) {
@@ -54,6 +59,125 @@ func TestGatewayValidation(t *testing.T) {
 	assert.NotEmpty(validations)
 }
 
+func TestFetchGatewayCertificates(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	gatewayCertificatesCache.entries = map[string]map[string]cachedCertificate{}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	util.Clock = util.ClockMock{Time: start}
+	defer func() { util.Clock = util.RealClock{} }()
+
+	secretsResource := schema.GroupResource{Resource: "secrets"}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetToken").Return("token")
+	k8s.On("GetSecret", "istio-system", "bookinfo-cert").Return(&core_v1.Secret{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo-cert"}}, nil)
+	k8s.On("GetSecret", "istio-system", "missing-cert").Return(&core_v1.Secret{}, errors.NewNotFound(secretsResource, "missing-cert"))
+	k8s.On("GetSecret", "istio-system", "forbidden-cert").Return(&core_v1.Secret{}, errors.NewForbidden(secretsResource, "forbidden-cert", nil))
+
+	gws := make([]kubernetes.IstioObject, 0, 3)
+	for _, credentialName := range []string{"bookinfo-cert", "missing-cert", "forbidden-cert"} {
+		server := data.CreateServer([]string{"bookinfo.example.com"}, 443, "https", "HTTPS")
+		server["tls"] = map[string]interface{}{"mode": "SIMPLE", "credentialName": credentialName}
+		gws = append(gws, data.AddServerToGateway(server, data.CreateEmptyGateway(credentialName, "bookinfo", map[string]string{"istio": "ingressgateway"})))
+	}
+	workloadsPerNamespace := map[string]models.WorkloadList{
+		"istio-system": data.CreateWorkloadList("istio-system",
+			data.CreateWorkloadListItem("istio-ingressgateway", map[string]string{"istio": "ingressgateway"})),
+	}
+
+	vs := IstioValidationsService{k8s: k8s}
+	certificates := vs.fetchGatewayCertificates(gws, workloadsPerNamespace)
+
+	assert.Len(certificates, 2)
+	assert.False(certificates["istio-system/bookinfo-cert"].Valid)
+	certificate, found := certificates["istio-system/missing-cert"]
+	assert.True(found)
+	assert.Nil(certificate)
+	_, found = certificates["istio-system/forbidden-cert"]
+	assert.False(found)
+	k8s.AssertNumberOfCalls(t, "GetSecret", 3)
+	// The raw token is not kept
+	_, found = gatewayCertificatesCache.entries["token"]
+	assert.False(found)
+
+	// Secrets are read once per user until the cache expires
+	assert.Equal(certificates, vs.fetchGatewayCertificates(gws, workloadsPerNamespace))
+	k8s.AssertNumberOfCalls(t, "GetSecret", 3)
+
+	other := new(kubetest.K8SClientMock)
+	other.On("GetToken").Return("other-token")
+	other.On("GetSecret", "istio-system", mock.AnythingOfType("string")).Return(&core_v1.Secret{}, errors.NewForbidden(secretsResource, "", nil))
+	otherVs := IstioValidationsService{k8s: other}
+	assert.Empty(otherVs.fetchGatewayCertificates(gws, workloadsPerNamespace))
+	other.AssertNumberOfCalls(t, "GetSecret", 3)
+
+	util.Clock = util.ClockMock{Time: start.Add(gatewayCertificatesCacheDuration)}
+	vs.fetchGatewayCertificates(gws, workloadsPerNamespace)
+	k8s.AssertNumberOfCalls(t, "GetSecret", 6)
+}
+
+func TestFetchServiceAccounts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetServiceAccounts", "test").Return([]core_v1.ServiceAccount{{ObjectMeta: meta_v1.ObjectMeta{Name: "unused"}}}, nil)
+	k8s.On("GetServiceAccounts", "test2").Return([]core_v1.ServiceAccount{}, errors.NewForbidden(schema.GroupResource{Resource: "serviceaccounts"}, "", nil))
+
+	authPolicy := data.CreateAuthorizationPolicyWithRules("auth-policy", "ALLOW", map[string]interface{}{"app": "details"}, []interface{}{
+		map[string]interface{}{
+			"from": []interface{}{
+				map[string]interface{}{
+					"source": map[string]interface{}{
+						"principals": []interface{}{"cluster.local/ns/test/sa/unused", "cluster.local/ns/test2/sa/default", "cluster.local/ns/other/sa/default"},
+					},
+				},
+			},
+		},
+	})
+	namespaces := models.Namespaces{{Name: "test"}, {Name: "test2"}}
+
+	vs := IstioValidationsService{k8s: k8s}
+	serviceAccounts := map[string][]string{}
+	vs.fetchServiceAccounts([]kubernetes.IstioObject{authPolicy}, namespaces, serviceAccounts)
+
+	// ServiceAccounts not used by any workload are found too, namespaces that can't be read or aren't accessible are left out
+	assert.Equal(map[string][]string{"test": {"unused"}}, serviceAccounts)
+	k8s.AssertNotCalled(t, "GetServiceAccounts", "other")
+
+	// Namespaces already read are not read again
+	vs.fetchServiceAccounts([]kubernetes.IstioObject{authPolicy}, namespaces, serviceAccounts)
+	k8s.AssertNumberOfCalls(t, "GetServiceAccounts", 3)
+}
+
+func TestFetchLegacyObjects(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	policy := data.CreateEmptyVirtualService("default", "bookinfo", []string{})
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("HasLegacyResources").Return(true).Once()
+	k8s.On("HasLegacyResources").Return(false)
+	k8s.On("GetIstioObjects", "bookinfo", "policies", "").Return([]kubernetes.IstioObject{policy}, nil)
+	k8s.On("GetIstioObjects", "bookinfo", mock.AnythingOfType("string"), "").Return([]kubernetes.IstioObject{}, nil)
+
+	vs := IstioValidationsService{k8s: k8s}
+	legacyObjects := map[string][]kubernetes.IstioObject{}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	vs.fetchLegacyObjects(&legacyObjects, "bookinfo", &wg)
+	assert.Equal(map[string][]kubernetes.IstioObject{"policy": {policy}}, legacyObjects)
+	k8s.AssertNumberOfCalls(t, "GetIstioObjects", 3)
+
+	// Nothing is read when the legacy APIs are not served
+	legacyObjects = map[string][]kubernetes.IstioObject{}
+	wg.Add(1)
+	vs.fetchLegacyObjects(&legacyObjects, "bookinfo", &wg)
+	assert.Empty(legacyObjects)
+	k8s.AssertNumberOfCalls(t, "GetIstioObjects", 3)
+}
+
 func mockWorkLoadService(k8s *kubetest.K8SClientMock) WorkloadService {
 	// Setup mocks
 	k8s.On("IsOpenShift").Return(true)
@@ -87,10 +211,14 @@ func mockMultiNamespaceGatewaysValidationService() IstioValidationsService {
 	k8s.On("GetMeshPolicies", mock.AnythingOfType("string")).Return(fakeMeshPolicies(), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "peerauthentications", "").Return(fakePolicies(), nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "requestauthentications", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("HasLegacyResources").Return(true)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "clusterrbacconfigs", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "authorizationpolicies", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "servicerolebindings", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "serviceroles", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "policies", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "meshpolicies", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "rbacconfigs", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "virtualservices", "").Return(fakeCombinedIstioDetails().VirtualServices, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "serviceentries", "").Return(fakeCombinedIstioDetails().ServiceEntries, nil)
 
@@ -106,9 +234,13 @@ func mockCombinedValidationService(istioObjects *kubernetes.IstioDetails, servic
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "virtualservices", "").Return(fakeCombinedIstioDetails().VirtualServices, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "destinationrules", "").Return(fakeCombinedIstioDetails().DestinationRules, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "authorizationpolicies", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("HasLegacyResources").Return(true)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "clusterrbacconfigs", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "servicerolebindings", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "serviceroles", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "policies", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "meshpolicies", "").Return([]kubernetes.IstioObject{}, nil)
+	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "rbacconfigs", "").Return([]kubernetes.IstioObject{}, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "serviceentries", "").Return(fakeCombinedIstioDetails().ServiceEntries, nil)
 	k8s.On("GetIstioObjects", mock.AnythingOfType("string"), "gateways", "").Return(fakeCombinedIstioDetails().Gateways, nil)
 	k8s.On("GetNamespace", mock.AnythingOfType("string")).Return(kubetest.FakeNamespace("test"), nil)
@@ -218,3 +350,35 @@ func getGateway(name string) []kubernetes.IstioObject {
 			"app": "real",
 		}))}
 }
+
+func TestSuppressChecks(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.Validations.Ignore = []string{"kia1107"}
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	vs := data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})
+	meta := vs.GetObjectMeta()
+	meta.Annotations = map[string]string{models.IgnoreChecksAnnotation: "KIA1101"}
+	vs.SetObjectMeta(meta)
+	svc := core_v1.Service{ObjectMeta: meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo", Annotations: map[string]string{models.IgnoreChecksAnnotation: "KIA0601"}}}
+
+	subsetNotFound := models.Build("virtualservices.subsetpresent.subsetnotfound", "spec/http[0]/route[0]/destination")
+	noHost := models.Build("virtualservices.nohost.hostnotfound", "spec/http[0]/route[0]/destination/host")
+	svcCheck := models.Build("port.name.mismatch", "spec/ports[0]")
+	vsKey := models.BuildKey("virtualservice", "reviews", "bookinfo")
+	svcKey := models.BuildKey("service", "reviews", "bookinfo")
+	validations := models.IstioValidations{
+		vsKey:  {Name: "reviews", ObjectType: "virtualservice", Checks: []*models.IstioCheck{&subsetNotFound, &noHost}},
+		svcKey: {Name: "reviews", ObjectType: "service", Valid: true, Checks: []*models.IstioCheck{&svcCheck}},
+	}
+
+	validations = suppressChecks(validations, validatedObjects(kubernetes.IstioDetails{VirtualServices: []kubernetes.IstioObject{vs}}, nil, kubernetes.MTLSDetails{}, kubernetes.RBACDetails{}), []core_v1.Service{svc})
+
+	assert.True(validations[vsKey].Valid)
+	assert.Empty(validations[vsKey].Checks)
+	assert.Len(validations[vsKey].Suppressed, 2)
+	assert.Empty(validations[svcKey].Checks)
+	assert.Equal([]*models.IstioCheck{&svcCheck}, validations[svcKey].Suppressed)
+}
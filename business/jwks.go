@@ -0,0 +1,146 @@
+package business
+
+import (
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/business/checkers/requestauthentications"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+type cachedJwks struct {
+	status    requestauthentications.JwksStatus
+	fetchedAt time.Time
+}
+
+// jwksCache keeps the JWKS fetched by jwksUri, so validations don't hit the issuers on every request
+var jwksCache = struct {
+	sync.RWMutex
+	entries map[string]cachedJwks
+}{entries: map[string]cachedJwks{}}
+
+// fetchJwks fetches and parses the JWKS of the jwksUri of the RequestAuthentications, in parallel.
+// URIs are not fetched when disabled by configuration or when their host is not allowed, so they are left out of the result.
+// No host is allowed when the allowed hosts are not configured.
+func fetchJwks(requestAuthns []kubernetes.IstioObject) map[string]requestauthentications.JwksStatus {
+	jwks := map[string]requestauthentications.JwksStatus{}
+
+	conf := config.Get().KialiFeatureFlags.Validations.Jwks
+	if !conf.Enabled {
+		return jwks
+	}
+
+	uris := allowedJwksUris(requestAuthns, conf.AllowedHosts)
+	statuses := make([]requestauthentications.JwksStatus, len(uris))
+	wg := sync.WaitGroup{}
+	wg.Add(len(uris))
+	for i, uri := range uris {
+		go func(i int, uri string) {
+			defer wg.Done()
+			statuses[i] = getJwks(uri, time.Duration(conf.Timeout)*time.Second, time.Duration(conf.CacheDuration)*time.Second, conf.MaxEntries)
+		}(i, uri)
+	}
+	wg.Wait()
+
+	for i, uri := range uris {
+		jwks[uri] = statuses[i]
+	}
+	return jwks
+}
+
+// cachedJwksOf returns the JWKS of the jwksUri of the RequestAuthentications that are in cache, without fetching any
+func cachedJwksOf(requestAuthns []kubernetes.IstioObject) map[string]requestauthentications.JwksStatus {
+	jwks := map[string]requestauthentications.JwksStatus{}
+
+	conf := config.Get().KialiFeatureFlags.Validations.Jwks
+	if !conf.Enabled {
+		return jwks
+	}
+
+	cacheDuration := time.Duration(conf.CacheDuration) * time.Second
+	jwksCache.RLock()
+	defer jwksCache.RUnlock()
+	for _, uri := range allowedJwksUris(requestAuthns, conf.AllowedHosts) {
+		if entry, found := jwksCache.entries[uri]; found && time.Since(entry.fetchedAt) < cacheDuration {
+			jwks[uri] = entry.status
+		}
+	}
+	return jwks
+}
+
+// allowedJwksUris returns the distinct jwksUri of the RequestAuthentications whose host is allowed
+func allowedJwksUris(requestAuthns []kubernetes.IstioObject, allowedHosts []string) []string {
+	uris := []string{}
+	found := map[string]bool{}
+	for _, ra := range requestAuthns {
+		for _, uri := range requestauthentications.JwksUris(ra) {
+			if found[uri] {
+				continue
+			}
+			found[uri] = true
+			if isJwksHostAllowed(uri, allowedHosts) {
+				uris = append(uris, uri)
+			} else {
+				log.Debugf("JWKS not fetched from %s: host not allowed", uri)
+			}
+		}
+	}
+	return uris
+}
+
+func isJwksHostAllowed(uri string, allowedHosts []string) bool {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	for _, host := range allowedHosts {
+		if host == parsed.Host || host == parsed.Hostname() {
+			return true
+		}
+	}
+	return false
+}
+
+func getJwks(uri string, timeout, cacheDuration time.Duration, maxEntries int) requestauthentications.JwksStatus {
+	jwksCache.RLock()
+	entry, found := jwksCache.entries[uri]
+	jwksCache.RUnlock()
+	if found && time.Since(entry.fetchedAt) < cacheDuration {
+		return entry.status
+	}
+
+	status := requestauthentications.JwksStatus{Reachable: true}
+	body, code, err := httputil.HttpGet(uri, nil, timeout)
+	if err == nil && (code < 200 || code >= 300) {
+		err = fmt.Errorf("HTTP status %d", code)
+	}
+	if err != nil {
+		log.Debugf("JWKS can't be fetched from %s: %v", uri, err)
+		status = requestauthentications.JwksStatus{Reachable: false, Err: err}
+	} else if err = requestauthentications.ParseJwks(body); err != nil {
+		log.Debugf("JWKS fetched from %s is not valid: %v", uri, err)
+		status.Err = err
+	}
+
+	jwksCache.Lock()
+	if _, found := jwksCache.entries[uri]; !found && maxEntries > 0 && len(jwksCache.entries) >= maxEntries {
+		// Expired entries are dropped first, and the whole cache if it is still full
+		for key, cached := range jwksCache.entries {
+			if time.Since(cached.fetchedAt) >= cacheDuration {
+				delete(jwksCache.entries, key)
+			}
+		}
+		if len(jwksCache.entries) >= maxEntries {
+			jwksCache.entries = map[string]cachedJwks{}
+		}
+	}
+	jwksCache.entries[uri] = cachedJwks{status: status, fetchedAt: time.Now()}
+	jwksCache.Unlock()
+
+	return status
+}
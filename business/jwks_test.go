@@ -0,0 +1,114 @@
+package business
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestFetchJwks(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.Validations.Jwks.Enabled = true
+	config.Set(conf)
+	jwksCache.entries = map[string]cachedJwks{}
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/valid":
+			_, _ = w.Write([]byte(`{"keys":[{"kty":"RSA","kid":"key-1","n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbf","e":"AQAB"}]}`))
+		case "/empty":
+			_, _ = w.Write([]byte(`{"keys":[]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ras := []kubernetes.IstioObject{
+		data.CreateRequestAuthentication("jwt-1", "bookinfo", nil, []interface{}{
+			map[string]interface{}{"issuer": "issuer-1", "jwksUri": server.URL + "/valid"},
+			map[string]interface{}{"issuer": "issuer-2", "jwksUri": server.URL + "/empty"},
+		}),
+		data.CreateRequestAuthentication("jwt-2", "bookinfo", nil, []interface{}{
+			map[string]interface{}{"issuer": "issuer-1", "jwksUri": server.URL + "/valid"},
+			map[string]interface{}{"issuer": "issuer-3", "jwksUri": server.URL + "/missing"},
+		}),
+	}
+
+	// Nothing is fetched without allowed hosts
+	assert.Empty(fetchJwks(ras))
+	assert.Equal(int32(0), atomic.LoadInt32(&requests))
+
+	conf.KialiFeatureFlags.Validations.Jwks.AllowedHosts = []string{strings.TrimPrefix(server.URL, "http://")}
+	config.Set(conf)
+	jwks := fetchJwks(ras)
+	assert.Len(jwks, 3)
+	assert.Equal(int32(3), atomic.LoadInt32(&requests))
+
+	assert.True(jwks[server.URL+"/valid"].Reachable)
+	assert.NoError(jwks[server.URL+"/valid"].Err)
+	assert.True(jwks[server.URL+"/empty"].Reachable)
+	assert.EqualError(jwks[server.URL+"/empty"].Err, "no keys found")
+	assert.False(jwks[server.URL+"/missing"].Reachable)
+	assert.EqualError(jwks[server.URL+"/missing"].Err, "HTTP status 404")
+
+	// Fetched JWKS are cached
+	jwks = fetchJwks(ras)
+	assert.Len(jwks, 3)
+	assert.Equal(int32(3), atomic.LoadInt32(&requests))
+
+	assert.Len(cachedJwksOf(ras), 3)
+
+	// Only allowed hosts are fetched
+	conf.KialiFeatureFlags.Validations.Jwks.AllowedHosts = []string{"issuer.example.com"}
+	config.Set(conf)
+	assert.Empty(fetchJwks(ras))
+	conf.KialiFeatureFlags.Validations.Jwks.AllowedHosts = []string{strings.TrimPrefix(server.URL, "http://")}
+	config.Set(conf)
+	assert.Len(fetchJwks(ras), 3)
+
+	// Nothing is fetched when disabled
+	conf.KialiFeatureFlags.Validations.Jwks.Enabled = false
+	config.Set(conf)
+	assert.Empty(fetchJwks(ras))
+	assert.Empty(cachedJwksOf(ras))
+}
+
+func TestJwksDisabledByDefault(t *testing.T) {
+	config.Set(config.NewConfig())
+
+	ras := []kubernetes.IstioObject{
+		data.CreateRequestAuthentication("jwt-1", "bookinfo", nil, []interface{}{
+			map[string]interface{}{"issuer": "issuer-1", "jwksUri": "http://169.254.169.254/latest"},
+		}),
+	}
+	assert.Empty(t, fetchJwks(ras))
+}
+
+func TestJwksCacheIsBounded(t *testing.T) {
+	assert := assert.New(t)
+	jwksCache.entries = map[string]cachedJwks{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"keys":[]}`))
+	}))
+	defer server.Close()
+
+	for _, path := range []string{"/a", "/b", "/c"} {
+		getJwks(server.URL+path, time.Second, time.Minute, 2)
+	}
+	assert.LessOrEqual(len(jwksCache.entries), 2)
+	assert.Contains(jwksCache.entries, server.URL+"/c")
+}
@@ -27,6 +27,7 @@ type Layer struct {
 	Iter8          Iter8Service
 	IstioStatus    IstioStatusService
 	ProxyStatus    ProxyStatus
+	SLO            SLOService
 }
 
 // Global clientfactory and prometheus clients.
@@ -113,7 +114,7 @@ func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInter
 	temporaryLayer := &Layer{}
 	temporaryLayer.Health = HealthService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Svc = SvcService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
-	temporaryLayer.IstioConfig = IstioConfigService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.IstioConfig = IstioConfigService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
 	temporaryLayer.Workload = WorkloadService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
 	temporaryLayer.Validations = IstioValidationsService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.App = AppService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
@@ -125,11 +126,14 @@ func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInter
 	temporaryLayer.Iter8 = Iter8Service{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.IstioStatus = IstioStatusService{k8s: k8s}
 	temporaryLayer.ProxyStatus = ProxyStatus{k8s: k8s}
+	temporaryLayer.SLO = SLOService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 
 	return temporaryLayer
 }
 
 func Stop() {
+	StopValidationsHistory()
+	StopHealthNotifications()
 	if kialiCache != nil {
 		kialiCache.Stop()
 	}
@@ -201,6 +201,9 @@ func createStatsMetricsLabelsBuilder(q *models.MetricsStatsQuery) *MetricsLabels
 	lb.SelfReporter()
 	if q.Target.Kind == "app" {
 		lb.App(q.Target.Name, q.Target.Namespace)
+		if q.Target.Version != "" {
+			lb.Version(q.Target.Version)
+		}
 	} else if q.Target.Kind == "workload" {
 		lb.Workload(q.Target.Name, q.Target.Namespace)
 	} else if q.Target.Kind == "service" {
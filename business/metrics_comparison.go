@@ -0,0 +1,303 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// Number of samples of the window the targets are compared on
+	comparisonSamples = 30
+	// Below, the normal approximation of the Mann-Whitney U test is not reliable and a simple threshold is used
+	minComparisonSamples = 8
+	// Error ratio and latency (ms) under which a degradation is relative to the minimum, not to the baseline
+	minComparisonErrorRatio = 0.01
+	minComparisonLatency    = 1.0
+)
+
+// comparisonSeries holds the samples of the window of a target
+type comparisonSeries struct {
+	rates     map[model.Time]float64
+	errors    map[model.Time]float64
+	latencies map[string]map[model.Time]float64
+	err       error
+}
+
+// CompareMetrics compares, side by side, the request rate, the error ratio and the response times of a candidate to the ones of
+// a baseline over the window of the query. The verdict of each metric comes from a one-sided Mann-Whitney U test on the samples
+// of the window, combined with the tolerance of the query, or from the tolerance only when there are too few samples.
+func (in *MetricsService) CompareMetrics(q models.MetricsComparisonQuery) (*models.MetricsComparison, error) {
+	statsQueries := q.StatsQueries()
+	stats, err := in.GetStats(statsQueries)
+	if err != nil {
+		return nil, err
+	}
+
+	window, err := model.ParseDuration(q.Interval)
+	if err != nil {
+		return nil, err
+	}
+	step := time.Duration(window) / comparisonSamples
+	if step < 15*time.Second {
+		step = 15 * time.Second
+	}
+	rateInterval := step
+	if rateInterval < time.Minute {
+		rateInterval = time.Minute
+	}
+	rq := prometheus.RangeQuery{
+		Range:        prom_v1.Range{Start: q.QueryTime.Add(-time.Duration(window)), End: q.QueryTime, Step: step},
+		RateInterval: fmt.Sprintf("%ds", int(rateInterval.Seconds())),
+		RateFunc:     "rate",
+		Quantiles:    q.Quantiles,
+		Avg:          true,
+	}
+
+	series := make([]comparisonSeries, len(statsQueries))
+	var wg sync.WaitGroup
+	for i := range statsQueries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			series[i] = in.fetchComparisonSeries(&statsQueries[i], &rq)
+		}(i)
+	}
+	wg.Wait()
+	for _, s := range series {
+		if s.err != nil {
+			return nil, s.err
+		}
+	}
+	baseline, candidate := series[0], series[1]
+
+	comparison := models.MetricsComparison{
+		Baseline:  q.Baseline,
+		Candidate: q.Candidate,
+		Interval:  q.Interval,
+	}
+
+	rates := models.MetricComparison{
+		Name:      models.ComparisonRequestRate,
+		Baseline:  meanValue(baseline.rates),
+		Candidate: meanValue(candidate.rates),
+		PValue:    mannWhitneyGreater(sampleValues(baseline.rates), sampleValues(candidate.rates)),
+		Verdict:   models.ComparisonPass,
+	}
+	if rates.Baseline == nil || rates.Candidate == nil {
+		rates.Verdict = models.ComparisonNoData
+	}
+	comparison.Metrics = append(comparison.Metrics, rates)
+
+	baselineErrors := errorRatios(baseline)
+	candidateErrors := errorRatios(candidate)
+	comparison.Metrics = append(comparison.Metrics, compareMetric(q, models.MetricComparison{
+		Name:      models.ComparisonErrorRatio,
+		Baseline:  totalErrorRatio(baseline),
+		Candidate: totalErrorRatio(candidate),
+	}, baselineErrors, candidateErrors, minComparisonErrorRatio))
+
+	baselineStats := stats[statsQueries[0].GenKey()].ResponseTimes
+	candidateStats := stats[statsQueries[1].GenKey()].ResponseTimes
+	for _, stat := range append([]string{"avg"}, q.Quantiles...) {
+		comparison.Metrics = append(comparison.Metrics, compareMetric(q, models.MetricComparison{
+			Name:      models.ComparisonLatency,
+			Stat:      stat,
+			Baseline:  statValue(baselineStats, stat),
+			Candidate: statValue(candidateStats, stat),
+		}, sampleValues(baseline.latencies[stat]), sampleValues(candidate.latencies[stat]), minComparisonLatency))
+	}
+
+	comparison.Verdict = models.ComparisonPass
+	for _, metric := range comparison.Metrics {
+		if metric.Verdict == models.ComparisonFail {
+			comparison.Verdict = models.ComparisonFail
+			break
+		}
+		if metric.Verdict == models.ComparisonNoData {
+			comparison.Verdict = models.ComparisonNoData
+		}
+	}
+	return &comparison, nil
+}
+
+func (in *MetricsService) fetchComparisonSeries(q *models.MetricsStatsQuery, rq *prometheus.RangeQuery) comparisonSeries {
+	lb := createStatsMetricsLabelsBuilder(q)
+	series := comparisonSeries{latencies: map[string]map[model.Time]float64{}}
+
+	rates := in.prom.FetchRateRange("istio_requests_total", []string{lb.Build()}, "", rq)
+	if rates.Err != nil {
+		series.err = rates.Err
+		return series
+	}
+	series.rates = matrixValues(rates.Matrix)
+
+	errors := in.prom.FetchRateRange("istio_requests_total", lb.BuildForErrors(), "", rq)
+	if errors.Err != nil {
+		series.err = errors.Err
+		return series
+	}
+	series.errors = matrixValues(errors.Matrix)
+
+	for stat, latencies := range in.prom.FetchHistogramRange("istio_request_duration_milliseconds", lb.Build(), "", rq) {
+		if latencies.Err != nil {
+			series.err = latencies.Err
+			return series
+		}
+		series.latencies[stat] = matrixValues(latencies.Matrix)
+	}
+	return series
+}
+
+// compareMetric sets the p-value and the verdict of a metric whose increase is a degradation. The degradation is relative to the
+// baseline value, or to the minimum when the baseline is lower.
+func compareMetric(q models.MetricsComparisonQuery, metric models.MetricComparison, baseline, candidate []float64, minimum float64) models.MetricComparison {
+	if metric.Baseline == nil || metric.Candidate == nil {
+		metric.Verdict = models.ComparisonNoData
+		return metric
+	}
+	metric.PValue = mannWhitneyGreater(baseline, candidate)
+	degradation := (*metric.Candidate - *metric.Baseline) / math.Max(*metric.Baseline, minimum)
+	significant := metric.PValue == nil || *metric.PValue < q.Alpha
+	if significant && degradation > q.Tolerance {
+		metric.Verdict = models.ComparisonFail
+	} else {
+		metric.Verdict = models.ComparisonPass
+	}
+	return metric
+}
+
+// matrixValues sums the series of the matrix by timestamp, leaving out the NaN samples
+func matrixValues(matrix model.Matrix) map[model.Time]float64 {
+	values := map[model.Time]float64{}
+	for _, stream := range matrix {
+		for _, pair := range stream.Values {
+			if !math.IsNaN(float64(pair.Value)) {
+				values[pair.Timestamp] += float64(pair.Value)
+			}
+		}
+	}
+	return values
+}
+
+// errorRatios returns the error ratio of each sample with requests
+func errorRatios(series comparisonSeries) []float64 {
+	ratios := []float64{}
+	for _, ts := range sortedTimestamps(series.rates) {
+		if rate := series.rates[ts]; rate > 0 {
+			ratios = append(ratios, series.errors[ts]/rate)
+		}
+	}
+	return ratios
+}
+
+// totalErrorRatio returns the error ratio of the whole window, nil without requests
+func totalErrorRatio(series comparisonSeries) *float64 {
+	rates, errors := 0.0, 0.0
+	for ts, rate := range series.rates {
+		rates += rate
+		errors += series.errors[ts]
+	}
+	if rates == 0 {
+		return nil
+	}
+	ratio := errors / rates
+	return &ratio
+}
+
+func meanValue(values map[model.Time]float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	return &mean
+}
+
+func statValue(stats []models.Stat, name string) *float64 {
+	for _, stat := range stats {
+		if stat.Name == name {
+			value := stat.Value
+			return &value
+		}
+	}
+	return nil
+}
+
+func sampleValues(values map[model.Time]float64) []float64 {
+	samples := make([]float64, 0, len(values))
+	for _, ts := range sortedTimestamps(values) {
+		samples = append(samples, values[ts])
+	}
+	return samples
+}
+
+func sortedTimestamps(values map[model.Time]float64) []model.Time {
+	timestamps := make([]model.Time, 0, len(values))
+	for ts := range values {
+		timestamps = append(timestamps, ts)
+	}
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+	return timestamps
+}
+
+// mannWhitneyGreater returns the p-value of the one-sided Mann-Whitney U test of y being greater than x, using the normal
+// approximation with tie and continuity corrections. It returns nil when a sample is too small for the approximation.
+func mannWhitneyGreater(x, y []float64) *float64 {
+	nx, ny := float64(len(x)), float64(len(y))
+	if len(x) < minComparisonSamples || len(y) < minComparisonSamples {
+		return nil
+	}
+
+	type rankedValue struct {
+		value float64
+		fromY bool
+	}
+	all := make([]rankedValue, 0, len(x)+len(y))
+	for _, v := range x {
+		all = append(all, rankedValue{value: v})
+	}
+	for _, v := range y {
+		all = append(all, rankedValue{value: v, fromY: true})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// Tied values share the average of their ranks
+	rankSumY, ties := 0.0, 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromY {
+				rankSumY += rank
+			}
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	n := nx + ny
+	u := rankSumY - ny*(ny+1)/2
+	sigma := math.Sqrt(nx * ny / 12 * ((n + 1) - ties/(n*(n-1))))
+	p := 1.0
+	if sigma > 0 {
+		z := (u - nx*ny/2 - 0.5) / sigma
+		p = math.Min(1, 0.5*math.Erfc(z/math.Sqrt2))
+	}
+	return &p
+}
@@ -0,0 +1,120 @@
+package business
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+// comparisonMatrix returns a series of 30 samples alternating around the value
+func comparisonMatrix(value float64) model.Matrix {
+	values := []model.SamplePair{}
+	for i := 0; i < 30; i++ {
+		noise := 0.05 * value
+		if i%2 == 0 {
+			noise = -noise
+		}
+		values = append(values, model.SamplePair{Timestamp: model.Time(int64(i) * 60000), Value: model.SampleValue(value + noise*float64(i%3))})
+	}
+	return model.Matrix{&model.SampleStream{Metric: model.Metric{}, Values: values}}
+}
+
+func TestCompareMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	prom := new(prometheustest.PromClientMock)
+	for _, version := range []struct {
+		workload     string
+		rate, errors float64
+		avg, p99     float64
+	}{
+		{workload: "reviews-v1", rate: 10, errors: 0.1, avg: 50, p99: 100},
+		{workload: "reviews-v2", rate: 1, errors: 0.01, avg: 80, p99: 102},
+	} {
+		labels := `{reporter="destination",destination_workload_namespace="bookinfo",destination_workload="` + version.workload + `"}`
+		prom.On("FetchHistogramValues", "istio_request_duration_milliseconds", labels, "", "30m", true, []string{"0.99"}, queryTime).
+			Return(map[string]model.Vector{
+				"avg":  {&model.Sample{Value: model.SampleValue(version.avg)}},
+				"0.99": {&model.Sample{Value: model.SampleValue(version.p99)}},
+			}, nil)
+		prom.On("FetchRateRange", "istio_requests_total", []string{labels}, "", mock.AnythingOfType("*prometheus.RangeQuery")).
+			Return(prometheus.Metric{Matrix: comparisonMatrix(version.rate)})
+		workload := version.workload
+		prom.On("FetchRateRange", "istio_requests_total", mock.MatchedBy(func(labels []string) bool {
+			return len(labels) == 2 && strings.Contains(labels[0], workload) && strings.Contains(labels[0], "response_code")
+		}), "", mock.AnythingOfType("*prometheus.RangeQuery")).
+			Return(prometheus.Metric{Matrix: comparisonMatrix(version.errors)})
+		prom.On("FetchHistogramRange", "istio_request_duration_milliseconds", labels, "", mock.AnythingOfType("*prometheus.RangeQuery")).
+			Return(prometheus.Histogram{
+				"avg":  prometheus.Metric{Matrix: comparisonMatrix(version.avg)},
+				"0.99": prometheus.Metric{Matrix: comparisonMatrix(version.p99)},
+			})
+	}
+
+	q := models.MetricsComparisonQuery{
+		Baseline:    models.Target{Namespace: "bookinfo", Name: "reviews-v1", Kind: "workload"},
+		Candidate:   models.Target{Namespace: "bookinfo", Name: "reviews-v2", Kind: "workload"},
+		QueryTime:   queryTime,
+		RawInterval: "30m",
+		Interval:    "30m",
+		Direction:   "inbound",
+		Quantiles:   []string{"0.99"},
+	}
+	q.FillDefaults()
+	comparison, err := NewMetricsService(prom).CompareMetrics(q)
+
+	assert.NoError(err)
+	assert.Equal(models.ComparisonFail, comparison.Verdict)
+	assert.Len(comparison.Metrics, 4)
+
+	// A canary receives less traffic without failing
+	rates := comparison.Metrics[0]
+	assert.Equal(models.ComparisonRequestRate, rates.Name)
+	assert.Equal(models.ComparisonPass, rates.Verdict)
+	assert.InDelta(10, *rates.Baseline, 0.5)
+	assert.InDelta(1, *rates.Candidate, 0.05)
+
+	errors := comparison.Metrics[1]
+	assert.Equal(models.ComparisonErrorRatio, errors.Name)
+	assert.InDelta(0.01, *errors.Candidate, 1e-9)
+	assert.Equal(models.ComparisonPass, errors.Verdict)
+
+	// Significantly slower on average
+	avg := comparison.Metrics[2]
+	assert.Equal(models.ComparisonLatency, avg.Name)
+	assert.Equal("avg", avg.Stat)
+	assert.Equal(80.0, *avg.Candidate)
+	assert.True(*avg.PValue < 0.05)
+	assert.Equal(models.ComparisonFail, avg.Verdict)
+
+	// Slightly slower, within the tolerance
+	p99 := comparison.Metrics[3]
+	assert.Equal("0.99", p99.Stat)
+	assert.Equal(models.ComparisonPass, p99.Verdict)
+}
+
+func TestMannWhitneyGreater(t *testing.T) {
+	assert := assert.New(t)
+
+	low := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	high := []float64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	assert.True(*mannWhitneyGreater(low, high) < 0.001)
+	assert.True(*mannWhitneyGreater(high, low) > 0.999)
+	assert.True(*mannWhitneyGreater(low, low) > 0.5)
+
+	// All values tied
+	same := []float64{1, 1, 1, 1, 1, 1, 1, 1}
+	assert.Equal(1.0, *mannWhitneyGreater(same, same))
+
+	// Too few samples
+	assert.Nil(mannWhitneyGreater(low[:3], high))
+}
@@ -79,6 +79,10 @@ func (lb *MetricsLabelsBuilder) App(name, namespace string) *MetricsLabelsBuilde
 	return lb.addSided("canonical_service", name, lb.side)
 }
 
+func (lb *MetricsLabelsBuilder) Version(name string) *MetricsLabelsBuilder {
+	return lb.addSided("canonical_revision", name, lb.side)
+}
+
 func (lb *MetricsLabelsBuilder) PeerService(name, namespace string) *MetricsLabelsBuilder {
 	if lb.peerSide == destination {
 		lb.Add("destination_service_name", name)
@@ -51,6 +51,32 @@ func (in *ProxyStatus) getProxyStatusUsingKialiSA() ([]*kubernetes.ProxyStatus,
 	return k8s.GetProxyStatus()
 }
 
+// GetConfigDump returns the Envoy configuration of the proxy running in the given pod.
+// It falls back to the Kiali ServiceAccount as users usually don't have access to the istiod pods.
+func (in *ProxyStatus) GetConfigDump(namespace, pod string) (*kubernetes.ConfigDump, error) {
+	configDump, err := in.k8s.GetConfigDump(namespace, pod)
+	if err == nil {
+		return configDump, nil
+	}
+
+	clientFactory, err := kubernetes.GetClientFactory()
+	if err != nil {
+		return nil, err
+	}
+
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		return nil, err
+	}
+
+	k8s, err := clientFactory.GetClient(kialiToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return k8s.GetConfigDump(namespace, pod)
+}
+
 func castProxyStatus(ps kubernetes.ProxyStatus) *models.ProxyStatus {
 	return &models.ProxyStatus{
 		CDS: xdsStatus(ps.ClusterSent, ps.ClusterAcked),
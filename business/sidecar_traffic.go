@@ -0,0 +1,147 @@
+package business
+
+import (
+	"sort"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// Interval used to observe the outbound traffic of the workloads when it is not set by the caller
+const defaultOutboundTrafficInterval = "1h"
+
+// GetOutboundHosts returns the hosts called by each workload of the namespace, as reported by Prometheus.
+// Only requests with a known destination namespace are considered, as other destinations can't be declared in a Sidecar.
+func (in *IstioConfigService) GetOutboundHosts(namespace, rateInterval string) (map[string][]models.OutboundHost, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GetOutboundHosts")
+	defer promtimer.ObserveNow(&err)
+
+	outboundHosts := map[string][]models.OutboundHost{}
+	if in.prom == nil {
+		return outboundHosts, nil
+	}
+	if rateInterval == "" {
+		rateInterval = defaultOutboundTrafficInterval
+	}
+
+	vector, err := in.prom.GetAllRequestRates(namespace, rateInterval, util.Clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]map[models.OutboundHost]bool{}
+	for _, sample := range vector {
+		if string(sample.Metric["source_workload_namespace"]) != namespace {
+			continue
+		}
+		workload := string(sample.Metric["source_workload"])
+		host := models.OutboundHost{
+			Host:      string(sample.Metric["destination_service"]),
+			Namespace: string(sample.Metric["destination_service_namespace"]),
+		}
+		if workload == "" || workload == "unknown" || host.Host == "" || host.Namespace == "" || host.Namespace == "unknown" {
+			continue
+		}
+		if seen[workload] == nil {
+			seen[workload] = map[models.OutboundHost]bool{}
+		}
+		if !seen[workload][host] {
+			seen[workload][host] = true
+			outboundHosts[workload] = append(outboundHosts[workload], host)
+		}
+	}
+
+	return outboundHosts, nil
+}
+
+// GenerateSidecars proposes, for each workload of the namespace with a proxy, a Sidecar restricting its egress
+// configuration to the control plane namespace and the hosts observed in its outbound traffic.
+// If workload is set, only the Sidecar for that workload is proposed.
+func (in *IstioConfigService) GenerateSidecars(namespace, workload, rateInterval string) ([]kubernetes.IstioObject, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioConfigService", "GenerateSidecars")
+	defer promtimer.ObserveNow(&err)
+
+	sidecars := make([]kubernetes.IstioObject, 0)
+
+	workloads, err := in.businessLayer.Workload.GetWorkloadList(namespace)
+	if err != nil {
+		return sidecars, err
+	}
+	outboundHosts, err := in.GetOutboundHosts(namespace, rateInterval)
+	if err != nil {
+		return sidecars, err
+	}
+
+	found := false
+	for _, wk := range workloads.Workloads {
+		if workload != "" && wk.Name != workload {
+			continue
+		}
+		found = true
+		if !wk.IstioSidecar || len(wk.Labels) == 0 {
+			continue
+		}
+		sidecars = append(sidecars, buildSidecar(namespace, wk, outboundHosts[wk.Name]))
+	}
+
+	if workload != "" && !found {
+		err = kubernetes.NewNotFound(workload, "", "workloads")
+		return sidecars, err
+	}
+
+	return sidecars, nil
+}
+
+func buildSidecar(namespace string, wk models.WorkloadListItem, outboundHosts []models.OutboundHost) kubernetes.IstioObject {
+	hosts := []string{config.Get().IstioNamespace + "/*"}
+	seen := map[string]bool{hosts[0]: true}
+	for _, oh := range outboundHosts {
+		ns := oh.Namespace
+		if ns == namespace {
+			ns = "."
+		}
+		host := ns + "/" + oh.Host
+		if !seen[host] && !seen[oh.Namespace+"/*"] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts[1:])
+
+	egressHosts := make([]interface{}, 0, len(hosts))
+	for _, h := range hosts {
+		egressHosts = append(egressHosts, h)
+	}
+	selectorLabels := make(map[string]interface{}, len(wk.Labels))
+	for k, v := range wk.Labels {
+		selectorLabels[k] = v
+	}
+
+	return &kubernetes.GenericIstioObject{
+		TypeMeta: meta_v1.TypeMeta{
+			Kind:       kubernetes.SidecarType,
+			APIVersion: kubernetes.ApiNetworkingVersion,
+		},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      wk.Name,
+			Namespace: namespace,
+		},
+		Spec: map[string]interface{}{
+			"workloadSelector": map[string]interface{}{
+				"labels": selectorLabels,
+			},
+			"egress": []interface{}{
+				map[string]interface{}{
+					"hosts": egressHosts,
+				},
+			},
+		},
+	}
+}
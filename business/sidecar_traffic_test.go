@@ -0,0 +1,87 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+	"github.com/kiali/kiali/tests/data"
+	"github.com/kiali/kiali/util"
+)
+
+func TestGetOutboundHosts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	util.Clock = util.RealClock{}
+
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetAllRequestRates", "bookinfo", "1h", mock.AnythingOfType("time.Time")).Return(model.Vector{
+		outboundSample("bookinfo", "productpage-v1", "reviews.bookinfo.svc.cluster.local", "bookinfo"),
+		outboundSample("bookinfo", "productpage-v1", "reviews.bookinfo.svc.cluster.local", "bookinfo"),
+		outboundSample("bookinfo", "productpage-v1", "details.bookinfo.svc.cluster.local", "bookinfo"),
+		outboundSample("bookinfo", "reviews-v2", "ratings.bookinfo.svc.cluster.local", "bookinfo"),
+		// Unknown destinations and requests coming from other namespaces are discarded
+		outboundSample("bookinfo", "reviews-v2", "PassthroughCluster", "unknown"),
+		outboundSample("istio-system", "istio-ingressgateway", "productpage.bookinfo.svc.cluster.local", "bookinfo"),
+	}, nil)
+
+	service := IstioConfigService{prom: prom}
+	hosts, err := service.GetOutboundHosts("bookinfo", "")
+	assert.NoError(err)
+	assert.Len(hosts, 2)
+	assert.Equal([]models.OutboundHost{
+		{Host: "reviews.bookinfo.svc.cluster.local", Namespace: "bookinfo"},
+		{Host: "details.bookinfo.svc.cluster.local", Namespace: "bookinfo"},
+	}, hosts["productpage-v1"])
+	assert.Equal([]models.OutboundHost{
+		{Host: "ratings.bookinfo.svc.cluster.local", Namespace: "bookinfo"},
+	}, hosts["reviews-v2"])
+}
+
+func TestBuildSidecar(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	wk := data.CreateWorkloadListItem("productpage-v1", map[string]string{"app": "productpage", "version": "v1"})
+	sidecar := buildSidecar("bookinfo", wk, []models.OutboundHost{
+		{Host: "reviews.bookinfo.svc.cluster.local", Namespace: "bookinfo"},
+		{Host: "istiod.istio-system.svc.cluster.local", Namespace: "istio-system"},
+		{Host: "hotels.travel.svc.cluster.local", Namespace: "travel"},
+		{Host: "details.bookinfo.svc.cluster.local", Namespace: "bookinfo"},
+	})
+
+	assert.Equal(kubernetes.SidecarType, sidecar.GetTypeMeta().Kind)
+	assert.Equal("productpage-v1", sidecar.GetObjectMeta().Name)
+	assert.Equal("bookinfo", sidecar.GetObjectMeta().Namespace)
+	assert.Equal(map[string]interface{}{
+		"labels": map[string]interface{}{"app": "productpage", "version": "v1"},
+	}, sidecar.GetSpec()["workloadSelector"])
+	assert.Equal([]interface{}{
+		map[string]interface{}{
+			"hosts": []interface{}{
+				"istio-system/*",
+				"./details.bookinfo.svc.cluster.local",
+				"./reviews.bookinfo.svc.cluster.local",
+				"travel/hotels.travel.svc.cluster.local",
+			},
+		},
+	}, sidecar.GetSpec()["egress"])
+}
+
+func outboundSample(sourceNamespace, sourceWorkload, destinationService, destinationNamespace string) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"source_workload_namespace":     model.LabelValue(sourceNamespace),
+			"source_workload":               model.LabelValue(sourceWorkload),
+			"destination_service":           model.LabelValue(destinationService),
+			"destination_service_namespace": model.LabelValue(destinationNamespace),
+		},
+		Value: 1,
+	}
+}
@@ -0,0 +1,317 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/common/model"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+// SLOAnnotation holds, as a JSON list, the service level objectives declared by a Service.
+// They are added to the ones of the configuration, replacing the ones with the same id, when SLOAnnotations is enabled.
+const SLOAnnotation = "kiali.io/slo"
+
+const defaultSLOWindow = "30d"
+
+// defaultLatencyBuckets are the default bucket boundaries of istio_request_duration_milliseconds. Latency objectives
+// with another threshold have no data, unless the buckets are customized.
+var defaultLatencyBuckets = []float64{0.5, 1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000, 300000, 600000, 1800000, 3600000}
+
+// sloBurnRateWindows are the multiwindow burn rate alerts: a pair alerts when its long and short windows
+// both consume the share of the error budget faster than the threshold
+var sloBurnRateWindows = []struct {
+	long        string
+	short       string
+	budgetShare float64
+}{
+	{long: "1h", short: "5m", budgetShare: 0.02},
+	{long: "6h", short: "30m", budgetShare: 0.05},
+	{long: "1d", short: "2h", budgetShare: 0.1},
+	{long: "3d", short: "6h", budgetShare: 0.1},
+}
+
+// SLOService evaluates the service level objectives of apps, services and workloads
+type SLOService struct {
+	prom          prometheus.ClientInterface
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// GetServiceSLOs evaluates the objectives of a service, from the configuration and its kiali.io/slo annotation
+func (in *SLOService) GetServiceSLOs(namespace, service string, queryTime time.Time) ([]models.SLOStatus, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SLOService", "GetServiceSLOs")
+	defer promtimer.ObserveNow(&err)
+
+	// Perf: the service is only read for its annotations, and there is no namespace lookup nor query without objectives
+	var annotations map[string]string
+	sloAnnotations := config.Get().HealthConfig.SLOAnnotations
+	if sloAnnotations {
+		if annotations, err = in.getServiceAnnotations(namespace, service); err != nil {
+			return nil, err
+		}
+	}
+	objectives := sloObjectives(namespace, "service", service, annotations)
+	if len(objectives) == 0 {
+		return []models.SLOStatus{}, nil
+	}
+	if !sloAnnotations {
+		if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	return in.evaluate(objectives, sloLabels(namespace, "service", service), queryTime)
+}
+
+// GetAppSLOs evaluates the objectives of an app from the configuration
+func (in *SLOService) GetAppSLOs(namespace, app string, queryTime time.Time) ([]models.SLOStatus, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SLOService", "GetAppSLOs")
+	defer promtimer.ObserveNow(&err)
+
+	// Perf: no namespace lookup nor query when there is no objective
+	objectives := sloObjectives(namespace, "app", app, nil)
+	if len(objectives) == 0 {
+		return []models.SLOStatus{}, nil
+	}
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	return in.evaluate(objectives, sloLabels(namespace, "app", app), queryTime)
+}
+
+// GetWorkloadSLOs evaluates the objectives of a workload from the configuration
+func (in *SLOService) GetWorkloadSLOs(namespace, workload string, queryTime time.Time) ([]models.SLOStatus, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "SLOService", "GetWorkloadSLOs")
+	defer promtimer.ObserveNow(&err)
+
+	// Perf: no namespace lookup nor query when there is no objective
+	objectives := sloObjectives(namespace, "workload", workload, nil)
+	if len(objectives) == 0 {
+		return []models.SLOStatus{}, nil
+	}
+	if _, err = in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	return in.evaluate(objectives, sloLabels(namespace, "workload", workload), queryTime)
+}
+
+// getNamespaceSLOs evaluates the objectives of the named apps, services or workloads of a namespace whose access is
+// already checked, with the annotations of the services when enabled. Objects without objectives are left out.
+// Errors are logged, the health being still relevant without SLOs.
+func (in *SLOService) getNamespaceSLOs(namespace, kind string, names []string, annotations map[string]map[string]string, queryTime time.Time) map[string][]models.SLOStatus {
+	allSLOs := map[string][]models.SLOStatus{}
+	if !config.Get().HealthConfig.SLOAnnotations {
+		annotations = nil
+	}
+	for _, name := range names {
+		objectives := sloObjectives(namespace, kind, name, annotations[name])
+		if len(objectives) == 0 {
+			continue
+		}
+		slos, err := in.evaluate(objectives, sloLabels(namespace, kind, name), queryTime)
+		if err != nil {
+			log.Errorf("Error evaluating SLOs of %s %s/%s: %s", kind, namespace, name, err)
+			continue
+		}
+		allSLOs[name] = slos
+	}
+	return allSLOs
+}
+
+// sloLabels selects the requests received by an app, a service or a workload
+func sloLabels(namespace, kind, name string) string {
+	switch kind {
+	case "app":
+		return fmt.Sprintf(`reporter="destination",destination_app="%s",destination_workload_namespace="%s"`, name, namespace)
+	case "service":
+		return fmt.Sprintf(`reporter="destination",destination_service_name="%s",destination_service_namespace="%s"`, name, namespace)
+	default:
+		return fmt.Sprintf(`reporter="destination",destination_workload="%s",destination_workload_namespace="%s"`, name, namespace)
+	}
+}
+
+// getServiceAnnotations returns the annotations of a service, from the cache when the namespace is cached
+func (in *SLOService) getServiceAnnotations(namespace, service string) (map[string]string, error) {
+	if IsNamespaceCached(namespace) {
+		// Check if user has access to the namespace (RBAC), as the cache doesn't
+		if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+			return nil, err
+		}
+		services, err := kialiCache.GetServices(namespace, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, svc := range services {
+			if svc.Name == service {
+				return svc.Annotations, nil
+			}
+		}
+		return nil, errors.NewNotFound(core_v1.Resource("services"), service)
+	}
+	svc, err := in.k8s.GetService(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	return svc.Annotations, nil
+}
+
+func (in *SLOService) evaluate(objectives []config.SLO, labels string, queryTime time.Time) ([]models.SLOStatus, error) {
+	statuses := make([]models.SLOStatus, 0, len(objectives))
+	for _, slo := range objectives {
+		window := slo.Window
+		if window == "" {
+			window = defaultSLOWindow
+		}
+		if err := slo.Validate(); err != nil {
+			statuses = append(statuses, models.SLOStatus{
+				Id:               slo.Id,
+				Type:             slo.Type,
+				Objective:        slo.Objective,
+				LatencyThreshold: slo.LatencyThreshold,
+				Window:           window,
+				BurnRates:        []models.BurnRate{},
+				Status:           models.SLOInvalid,
+				Reason:           err.Error(),
+			})
+			continue
+		}
+		windowDuration, _ := model.ParseDuration(window)
+
+		windows := []string{window}
+		queried := map[string]bool{window: true}
+		for _, br := range sloBurnRateWindows {
+			if longDuration, _ := model.ParseDuration(br.long); longDuration < windowDuration {
+				for _, w := range []string{br.long, br.short} {
+					if !queried[w] {
+						queried[w] = true
+						windows = append(windows, w)
+					}
+				}
+			}
+		}
+
+		latencyThreshold := 0.0
+		if slo.Type == "latency" {
+			latencyThreshold = slo.LatencyThreshold
+		}
+		ratios, err := in.prom.GetSLOErrorRatios(labels, latencyThreshold, windows, queryTime)
+		if err != nil {
+			return nil, err
+		}
+		status := evaluateSLO(slo, window, time.Duration(windowDuration), ratios)
+		if status.Status == models.SLONoData && slo.Type == "latency" && !isDefaultLatencyBucket(slo.LatencyThreshold) {
+			status.Reason = fmt.Sprintf("latency threshold %v is not a default bucket boundary of istio_request_duration_milliseconds", slo.LatencyThreshold)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func isDefaultLatencyBucket(threshold float64) bool {
+	for _, bucket := range defaultLatencyBuckets {
+		if bucket == threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluateSLO computes the status of an objective from the error ratios of its window and of the burn rate windows
+func evaluateSLO(slo config.SLO, window string, windowDuration time.Duration, ratios map[string]float64) models.SLOStatus {
+	status := models.SLOStatus{
+		Id:               slo.Id,
+		Type:             slo.Type,
+		Objective:        slo.Objective,
+		LatencyThreshold: slo.LatencyThreshold,
+		Window:           window,
+		BurnRates:        []models.BurnRate{},
+		Status:           models.SLONoData,
+	}
+
+	budget := 1 - slo.Objective/100
+	errorRatio, found := ratios[window]
+	if !found {
+		return status
+	}
+
+	sli := 1 - errorRatio
+	remaining := 1 - errorRatio/budget
+	status.SLI = &sli
+	status.ErrorBudgetRemaining = &remaining
+	status.Status = models.SLOHealthy
+
+	for _, br := range sloBurnRateWindows {
+		longDuration, _ := model.ParseDuration(br.long)
+		if time.Duration(longDuration) >= windowDuration {
+			continue
+		}
+		burnRate := models.BurnRate{
+			LongWindow:  br.long,
+			ShortWindow: br.short,
+			Long:        ratios[br.long] / budget,
+			Short:       ratios[br.short] / budget,
+			Threshold:   br.budgetShare * float64(windowDuration) / float64(longDuration),
+		}
+		burnRate.Alerting = burnRate.Long > burnRate.Threshold && burnRate.Short > burnRate.Threshold
+		if burnRate.Alerting {
+			status.Status = models.SLOBurning
+		}
+		status.BurnRates = append(status.BurnRates, burnRate)
+	}
+
+	if remaining <= 0 {
+		status.Status = models.SLOBreached
+	}
+	return status
+}
+
+// sloObjectives returns the objectives of the configuration matching the object, followed by the ones of its annotations
+func sloObjectives(namespace, kind, name string, annotations map[string]string) []config.SLO {
+	objectives := []config.SLO{}
+	index := map[string]int{}
+	add := func(slo config.SLO) {
+		if i, found := index[slo.Id]; found {
+			objectives[i] = slo
+		} else {
+			index[slo.Id] = len(objectives)
+			objectives = append(objectives, slo)
+		}
+	}
+
+	for _, slo := range config.Get().HealthConfig.SLO {
+		if matchesHealthConfig(slo.Namespace, namespace) && matchesHealthConfig(slo.Kind, kind) && matchesHealthConfig(slo.Name, name) {
+			add(slo)
+		}
+	}
+
+	if annotation, found := annotations[SLOAnnotation]; found {
+		declared := []config.SLO{}
+		if err := json.Unmarshal([]byte(annotation), &declared); err != nil {
+			log.Warningf("Invalid %s annotation of %s %s/%s: %v", SLOAnnotation, kind, namespace, name, err)
+		}
+		for _, slo := range declared {
+			// Invalid objectives are still added, to be reported rather than evaluated
+			if err := slo.Validate(); err != nil {
+				log.Warningf("Invalid SLO [%s] in %s annotation of %s %s/%s: %v", slo.Id, SLOAnnotation, kind, namespace, name, err)
+			}
+			add(slo)
+		}
+	}
+
+	return objectives
+}
@@ -0,0 +1,175 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestGetServiceSLOs(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.HealthConfig.SLO = []config.SLO{
+		{Id: "availability", Namespace: "bookinfo", Kind: "service", Name: "reviews", Type: "availability", Objective: 99.9},
+		{Id: "latency", Namespace: "bookinfo", Kind: "service", Name: "reviews", Type: "latency", Objective: 99, LatencyThreshold: 500},
+		// Not matching the service
+		{Id: "other", Namespace: "bookinfo", Kind: "app", Name: "reviews", Type: "availability", Objective: 99},
+	}
+	conf.HealthConfig.SLOAnnotations = true
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "reviews",
+			Namespace: "bookinfo",
+			Annotations: map[string]string{
+				SLOAnnotation: `[{"id":"latency","type":"latency","objective":95,"latencyThreshold":250,"window":"1d"}]`,
+			},
+		},
+	}, nil)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo"`
+	prom.On("GetSLOErrorRatios", labels, 0.0, []string{"30d", "1h", "5m", "6h", "30m", "1d", "2h", "3d"}, queryTime).
+		Return(map[string]float64{"30d": 0.0005, "1h": 0.02, "5m": 0.03, "6h": 0.001, "30m": 0.001}, nil)
+	// The annotation replaces the configured latency objective, and its window is too short for the longest burn rates
+	prom.On("GetSLOErrorRatios", labels, 250.0, []string{"1d", "1h", "5m", "6h", "30m"}, queryTime).
+		Return(map[string]float64{}, nil)
+
+	slo := SLOService{k8s: k8s, prom: prom}
+	slos, err := slo.GetServiceSLOs("bookinfo", "reviews", queryTime)
+
+	assert.NoError(err)
+	assert.Len(slos, 2)
+
+	availability := slos[0]
+	assert.Equal("availability", availability.Id)
+	assert.Equal("30d", availability.Window)
+	assert.Equal(models.SLOBurning, availability.Status)
+	assert.InDelta(0.9995, *availability.SLI, 1e-9)
+	assert.InDelta(0.5, *availability.ErrorBudgetRemaining, 1e-9)
+	assert.Len(availability.BurnRates, 4)
+	assert.Equal("1h", availability.BurnRates[0].LongWindow)
+	assert.InDelta(14.4, availability.BurnRates[0].Threshold, 1e-9)
+	assert.InDelta(20, availability.BurnRates[0].Long, 1e-9)
+	assert.InDelta(30, availability.BurnRates[0].Short, 1e-9)
+	assert.True(availability.BurnRates[0].Alerting)
+	assert.False(availability.BurnRates[1].Alerting)
+
+	latency := slos[1]
+	assert.Equal("latency", latency.Id)
+	assert.Equal(95.0, latency.Objective)
+	assert.Equal(models.SLONoData, latency.Status)
+	assert.Nil(latency.SLI)
+	assert.Nil(latency.ErrorBudgetRemaining)
+	assert.Empty(latency.BurnRates)
+}
+
+func TestGetAppSLOsWithoutObjectives(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	prom := new(prometheustest.PromClientMock)
+	layer := NewWithBackends(k8s, prom, nil)
+
+	slos, err := layer.SLO.GetAppSLOs("bookinfo", "reviews", time.Now())
+
+	assert.NoError(err)
+	assert.Empty(slos)
+	k8s.AssertNotCalled(t, "GetProject", "bookinfo")
+	prom.AssertNotCalled(t, "GetSLOErrorRatios")
+}
+
+func TestGetServiceSLOsWithoutObjectives(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.HealthConfig.SLO = []config.SLO{{Id: "other", Kind: "app", Type: "availability", Objective: 99}}
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(true)
+	prom := new(prometheustest.PromClientMock)
+	layer := NewWithBackends(k8s, prom, nil)
+
+	slos, err := layer.SLO.GetServiceSLOs("bookinfo", "reviews", time.Now())
+
+	assert.NoError(err)
+	assert.Empty(slos)
+	// The service isn't read, as its annotations are ignored
+	k8s.AssertNotCalled(t, "GetService", "bookinfo", "reviews")
+	prom.AssertNotCalled(t, "GetSLOErrorRatios")
+}
+
+func TestGetServiceSLOsInvalidObjectives(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.HealthConfig.SLO = []config.SLO{
+		// Not a default bucket boundary
+		{Id: "latency", Kind: "service", Type: "latency", Objective: 99, LatencyThreshold: 300, Window: "1h"},
+	}
+	conf.HealthConfig.SLOAnnotations = true
+	config.Set(conf)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	k8s.On("GetService", "bookinfo", "reviews").Return(&core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "reviews",
+			Namespace: "bookinfo",
+			Annotations: map[string]string{
+				SLOAnnotation: `[{"id":"no-threshold","type":"latency","objective":99},{"id":"miscased","type":"Availability","objective":99},` +
+					`{"id":"full","type":"availability","objective":100},{"id":"window","type":"availability","objective":99,"window":"1 week"}]`,
+			},
+		},
+	}, nil)
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	labels := `reporter="destination",destination_service_name="reviews",destination_service_namespace="bookinfo"`
+	prom.On("GetSLOErrorRatios", labels, 300.0, []string{"1h"}, queryTime).Return(map[string]float64{}, nil)
+
+	slo := SLOService{k8s: k8s, prom: prom}
+	slos, err := slo.GetServiceSLOs("bookinfo", "reviews", queryTime)
+
+	assert.NoError(err)
+	assert.Len(slos, 5)
+	assert.Equal(models.SLONoData, slos[0].Status)
+	assert.Contains(slos[0].Reason, "not a default bucket boundary")
+	for _, invalid := range slos[1:] {
+		assert.Equal(models.SLOInvalid, invalid.Status, invalid.Id)
+		assert.NotEmpty(invalid.Reason, invalid.Id)
+	}
+	// Invalid objectives are not queried
+	prom.AssertNumberOfCalls(t, "GetSLOErrorRatios", 1)
+}
+
+func TestEvaluateSLO(t *testing.T) {
+	assert := assert.New(t)
+
+	slo := config.SLO{Id: "availability", Type: "availability", Objective: 99}
+	window := 7 * 24 * time.Hour
+
+	status := evaluateSLO(slo, "7d", window, map[string]float64{"7d": 0.001, "1h": 0.001, "5m": 0.5})
+	assert.Equal(models.SLOHealthy, status.Status)
+	assert.InDelta(0.9, *status.ErrorBudgetRemaining, 1e-9)
+	assert.Len(status.BurnRates, 4)
+	// Only the short window burns
+	assert.False(status.BurnRates[0].Alerting)
+
+	status = evaluateSLO(slo, "7d", window, map[string]float64{"7d": 0.02})
+	assert.Equal(models.SLOBreached, status.Status)
+	assert.InDelta(-1, *status.ErrorBudgetRemaining, 1e-9)
+}
@@ -0,0 +1,301 @@
+package business
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+const validationsHistoryConfigMapKey = "history.json"
+
+// ValidationsHistoryStore keeps the validations history of all the namespaces validated by the background validator
+type ValidationsHistoryStore struct {
+	maxEntries int
+	mutex      sync.RWMutex
+	namespaces map[string]*models.ValidationsHistory
+}
+
+// validationsHistoryStorage persists the validations history between Kiali restarts
+type validationsHistoryStorage interface {
+	load() ([]byte, error)
+	save(data []byte) error
+}
+
+type fileHistoryStorage struct {
+	path string
+}
+
+type configMapHistoryStorage struct {
+	k8s       kubernetes.ClientInterface
+	namespace string
+	name      string
+}
+
+// Global validations history, only set when the background validator is enabled.
+// It is guarded by validationsHistoryMutex, as it is read by the handlers while the validator is started or stopped.
+var validationsHistory *ValidationsHistoryStore
+var validationsHistoryStop chan bool
+var validationsHistoryMutex sync.RWMutex
+
+func NewValidationsHistoryStore(maxEntries int) *ValidationsHistoryStore {
+	return &ValidationsHistoryStore{
+		maxEntries: maxEntries,
+		namespaces: map[string]*models.ValidationsHistory{},
+	}
+}
+
+// Add records the validations of a namespace and updates the validation checks metrics
+func (in *ValidationsHistoryStore) Add(namespace string, validations models.IstioValidations, timestamp time.Time, objectVersion func(models.IstioValidationKey) (string, int64)) {
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+
+	history, found := in.namespaces[namespace]
+	if !found {
+		history = &models.ValidationsHistory{
+			Namespace:   namespace,
+			Trend:       []models.ValidationsTrendPoint{},
+			Regressions: []models.ValidationRegression{},
+		}
+		in.namespaces[namespace] = history
+	}
+	history.Add(validations, timestamp, in.maxEntries, objectVersion)
+
+	last := history.Trend[len(history.Trend)-1]
+	internalmetrics.SetValidationChecks(namespace, string(models.ErrorSeverity), last.Errors)
+	internalmetrics.SetValidationChecks(namespace, string(models.WarningSeverity), last.Warnings)
+}
+
+// RemoveStaleMetrics stops exporting the validation checks metrics of the namespaces that are not validated anymore,
+// as they were deleted or became inaccessible. Their history is kept.
+func (in *ValidationsHistoryStore) RemoveStaleMetrics(validated []string) {
+	in.mutex.RLock()
+	defer in.mutex.RUnlock()
+
+	current := make(map[string]bool, len(validated))
+	for _, ns := range validated {
+		current[ns] = true
+	}
+	for ns := range in.namespaces {
+		if !current[ns] {
+			internalmetrics.DeleteValidationChecks(ns, string(models.ErrorSeverity))
+			internalmetrics.DeleteValidationChecks(ns, string(models.WarningSeverity))
+		}
+	}
+}
+
+// Get returns a copy of the validations history of a namespace
+func (in *ValidationsHistoryStore) Get(namespace string) models.ValidationsHistory {
+	in.mutex.RLock()
+	defer in.mutex.RUnlock()
+
+	history := models.ValidationsHistory{
+		Namespace:   namespace,
+		Trend:       []models.ValidationsTrendPoint{},
+		Regressions: []models.ValidationRegression{},
+	}
+	if h, found := in.namespaces[namespace]; found {
+		history.Trend = append(history.Trend, h.Trend...)
+		history.Regressions = append(history.Regressions, h.Regressions...)
+	}
+	return history
+}
+
+func (in *ValidationsHistoryStore) marshal() ([]byte, error) {
+	in.mutex.RLock()
+	defer in.mutex.RUnlock()
+	return json.Marshal(in.namespaces)
+}
+
+func (in *ValidationsHistoryStore) unmarshal(data []byte) error {
+	namespaces := map[string]*models.ValidationsHistory{}
+	if err := json.Unmarshal(data, &namespaces); err != nil {
+		return err
+	}
+	if namespaces == nil {
+		namespaces = map[string]*models.ValidationsHistory{}
+	}
+	in.mutex.Lock()
+	defer in.mutex.Unlock()
+	in.namespaces = namespaces
+	return nil
+}
+
+func (s fileHistoryStorage) load() ([]byte, error) {
+	return ioutil.ReadFile(s.path)
+}
+
+func (s fileHistoryStorage) save(data []byte) error {
+	return ioutil.WriteFile(s.path, data, 0644)
+}
+
+func (s configMapHistoryStorage) load() ([]byte, error) {
+	cm, err := s.k8s.GetConfigMap(s.namespace, s.name)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(cm.Data[validationsHistoryConfigMapKey]), nil
+}
+
+func (s configMapHistoryStorage) save(data []byte) error {
+	cm, err := s.k8s.GetConfigMap(s.namespace, s.name)
+	if errors.IsNotFound(err) {
+		_, err = s.k8s.CreateConfigMap(s.namespace, &core_v1.ConfigMap{
+			ObjectMeta: meta_v1.ObjectMeta{Name: s.name, Namespace: s.namespace},
+			Data:       map[string]string{validationsHistoryConfigMapKey: string(data)},
+		})
+		return err
+	} else if err != nil {
+		return err
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[validationsHistoryConfigMapKey] = string(data)
+	_, err = s.k8s.UpdateConfigMap(s.namespace, cm)
+	return err
+}
+
+// StartValidationsHistory starts the background validator when it is enabled.
+// It validates all the namespaces accessible by the Kiali ServiceAccount on each interval.
+func StartValidationsHistory() {
+	conf := config.Get().KialiFeatureFlags.Validations.History
+	validationsHistoryMutex.Lock()
+	defer validationsHistoryMutex.Unlock()
+	if !conf.Enabled || validationsHistory != nil {
+		return
+	}
+
+	validationsHistory = NewValidationsHistoryStore(conf.MaxEntries)
+	validationsHistoryStop = make(chan bool)
+
+	go func(store *ValidationsHistoryStore, stop chan bool) {
+		var storage validationsHistoryStorage
+		if conf.PersistenceFile != "" {
+			storage = fileHistoryStorage{path: conf.PersistenceFile}
+		}
+
+		interval := time.Duration(conf.Interval) * time.Second
+		if interval <= 0 {
+			interval = 5 * time.Minute
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		loaded := false
+		for {
+			layer, err := getKialiSALayer()
+			if err != nil {
+				log.Errorf("Validations history: error initializing the business layer: %s", err)
+			} else {
+				if storage == nil && conf.PersistenceConfigMap != "" {
+					storage = configMapHistoryStorage{k8s: layer.k8s, namespace: config.Get().Deployment.Namespace, name: conf.PersistenceConfigMap}
+				}
+				if !loaded && storage != nil {
+					if data, err := storage.load(); err == nil && len(data) > 0 {
+						if err = store.unmarshal(data); err != nil {
+							log.Warningf("Validations history: cannot restore the persisted history: %s", err)
+						}
+					}
+					loaded = true
+				}
+				layer.Validations.recordValidationsHistory(store)
+				if storage != nil {
+					if data, err := store.marshal(); err == nil {
+						if err = storage.save(data); err != nil {
+							log.Warningf("Validations history: cannot persist the history: %s", err)
+						}
+					}
+				}
+			}
+
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}(validationsHistory, validationsHistoryStop)
+}
+
+// StopValidationsHistory stops the background validator
+func StopValidationsHistory() {
+	validationsHistoryMutex.Lock()
+	defer validationsHistoryMutex.Unlock()
+	if validationsHistoryStop != nil {
+		close(validationsHistoryStop)
+		validationsHistoryStop = nil
+	}
+	validationsHistory = nil
+}
+
+func getKialiSALayer() (*Layer, error) {
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		return nil, err
+	}
+	return Get(kialiToken)
+}
+
+// recordValidationsHistory validates all the accessible namespaces and adds the results to the history
+func (in *IstioValidationsService) recordValidationsHistory(history *ValidationsHistoryStore) {
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		log.Errorf("Validations history: error fetching namespaces: %s", err)
+		return
+	}
+
+	nsNames := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		nsNames = append(nsNames, ns.Name)
+		validations, err := in.GetValidations(ns.Name, "")
+		if err != nil {
+			log.Errorf("Validations history: error validating namespace [%s]: %s", ns.Name, err)
+			continue
+		}
+		history.Add(ns.Name, validations, util.Clock.Now(), in.getObjectVersion)
+	}
+	history.RemoveStaleMetrics(nsNames)
+}
+
+// getObjectVersion returns the resourceVersion and generation of a validated Istio object
+func (in *IstioValidationsService) getObjectVersion(key models.IstioValidationKey) (string, int64) {
+	for resourceType, objectType := range models.ObjectTypeSingular {
+		if objectType != key.ObjectType {
+			continue
+		}
+		if obj, err := in.k8s.GetIstioObject(key.Namespace, resourceType, key.Name); err == nil {
+			meta := obj.GetObjectMeta()
+			return meta.ResourceVersion, meta.Generation
+		}
+	}
+	return "", 0
+}
+
+// GetValidationsHistory returns the validations recorded over time by the background validator for a namespace
+func (in *IstioValidationsService) GetValidationsHistory(namespace string) (models.ValidationsHistory, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return models.ValidationsHistory{}, err
+	}
+
+	validationsHistoryMutex.RLock()
+	history := validationsHistory
+	validationsHistoryMutex.RUnlock()
+	if history == nil {
+		return models.ValidationsHistory{}, errors.NewServiceUnavailable("the validations history is not enabled")
+	}
+	return history.Get(namespace), nil
+}
@@ -0,0 +1,129 @@
+package business
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+func TestValidationsHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	errorCheck := models.Build("virtualservices.nohost.hostnotfound", "spec/http[0]/route[0]/destination/host")
+	vsKey := models.IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}
+	timestamp := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := NewValidationsHistoryStore(10)
+	store.Add("bookinfo", models.IstioValidations{
+		vsKey: &models.IstioValidation{Name: "reviews", ObjectType: "virtualservice", Valid: false, Checks: []*models.IstioCheck{&errorCheck}},
+	}, timestamp, nil)
+
+	history := store.Get("bookinfo")
+	assert.Equal("bookinfo", history.Namespace)
+	assert.Len(history.Trend, 1)
+	assert.Equal(1, history.Trend[0].Errors)
+	assert.Len(history.Regressions, 1)
+
+	empty := store.Get("other")
+	assert.Empty(empty.Trend)
+	assert.Empty(empty.Regressions)
+
+	// Persist and restore the history
+	dir, err := ioutil.TempDir("", "validations-history")
+	assert.NoError(err)
+	defer os.RemoveAll(dir)
+
+	storage := fileHistoryStorage{path: filepath.Join(dir, "history.json")}
+	data, err := store.marshal()
+	assert.NoError(err)
+	assert.NoError(storage.save(data))
+
+	restored := NewValidationsHistoryStore(10)
+	data, err = storage.load()
+	assert.NoError(err)
+	assert.NoError(restored.unmarshal(data))
+
+	history = restored.Get("bookinfo")
+	assert.Len(history.Trend, 1)
+	assert.True(timestamp.Equal(history.Trend[0].Timestamp))
+	assert.Len(history.Regressions, 1)
+	assert.Equal(vsKey, history.Regressions[0].IstioValidationKey)
+}
+
+func TestValidationsHistoryRemoveStaleMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	internalmetrics.Metrics.ValidationChecks.Reset()
+	defer internalmetrics.Metrics.ValidationChecks.Reset()
+
+	store := NewValidationsHistoryStore(10)
+	store.Add("bookinfo", models.IstioValidations{}, time.Now(), nil)
+	store.Add("travel", models.IstioValidations{}, time.Now(), nil)
+	assert.Equal(4, validationChecksSeries())
+
+	// travel was deleted
+	store.RemoveStaleMetrics([]string{"bookinfo"})
+	assert.Equal(2, validationChecksSeries())
+	assert.Len(store.Get("travel").Trend, 1)
+
+	// the namespace metrics are exported again when it is validated again
+	store.Add("travel", models.IstioValidations{}, time.Now(), nil)
+	store.RemoveStaleMetrics([]string{"bookinfo", "travel"})
+	assert.Equal(4, validationChecksSeries())
+}
+
+func validationChecksSeries() int {
+	ch := make(chan prometheus.Metric, 10)
+	internalmetrics.Metrics.ValidationChecks.Collect(ch)
+	close(ch)
+	return len(ch)
+}
+
+func TestGetValidationsHistoryWhileStarting(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.Validations.History.Enabled = true
+	config.Set(conf)
+	defer config.Set(config.NewConfig())
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetNamespace", "bookinfo").Return(&core_v1.Namespace{ObjectMeta: meta_v1.ObjectMeta{Name: "bookinfo"}}, nil)
+	layer := NewWithBackends(k8s, nil, nil)
+
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			StartValidationsHistory()
+			StopValidationsHistory()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 10; i++ {
+			if _, err := layer.Validations.GetValidationsHistory("bookinfo"); err != nil {
+				assert.True(errors.IsServiceUnavailable(err))
+			}
+		}
+	}()
+	wg.Wait()
+
+	_, err := layer.Validations.GetValidationsHistory("bookinfo")
+	assert.True(errors.IsServiceUnavailable(err))
+}
@@ -0,0 +1,112 @@
+package business
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+	"github.com/kiali/kiali/util"
+)
+
+// GetMeshValidationsReport validates all the namespaces accessible by the user and summarizes the results.
+// Namespaces are validated concurrently by a bounded pool of workers; a namespace that can't be validated
+// is reported with its error instead of failing the whole report.
+// The report lists up to topObjects offending objects, or the configured default when topObjects is not positive.
+func (in *IstioValidationsService) GetMeshValidationsReport(topObjects int) (models.MeshValidationsReport, error) {
+	var err error
+	promtimer := internalmetrics.GetGoFunctionMetric("business", "IstioValidationsService", "GetMeshValidationsReport")
+	defer promtimer.ObserveNow(&err)
+
+	conf := config.Get().KialiFeatureFlags.Validations.Report
+	if topObjects <= 0 {
+		topObjects = conf.TopObjects
+	}
+
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return models.MeshValidationsReport{}, err
+	}
+
+	nsReports := make([]models.NamespaceValidationsReport, len(namespaces))
+	nsValidations := make([]models.IstioValidations, len(namespaces))
+
+	workers := conf.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	if workers > len(namespaces) {
+		workers = len(namespaces)
+	}
+
+	jobs := make(chan int, len(namespaces))
+	for i := range namespaces {
+		jobs <- i
+	}
+	close(jobs)
+
+	wg := sync.WaitGroup{}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				nsReports[i].Namespace = namespaces[i].Name
+				validations, err := in.GetValidations(namespaces[i].Name, "")
+				if err != nil {
+					log.Errorf("Validations report: error validating namespace [%s]: %s", namespaces[i].Name, err)
+					nsReports[i].Error = err.Error()
+					continue
+				}
+				nsValidations[i] = validations
+			}
+		}()
+	}
+	wg.Wait()
+
+	validations := make(map[string]models.IstioValidations, len(namespaces))
+	nsLabels := make(map[string]map[string]string, len(namespaces))
+	for i, ns := range namespaces {
+		validations[ns.Name] = nsValidations[i]
+		nsLabels[ns.Name] = ns.Labels
+	}
+
+	report := models.NewMeshValidationsReport(nsReports, validations, topObjects, util.Clock.Now())
+	for i := range report.TopObjects {
+		report.TopObjects[i].Owner = in.getObjectOwner(report.TopObjects[i].IstioValidationKey, nsLabels[report.TopObjects[i].Namespace], conf.OwnerKeys)
+	}
+
+	return report, nil
+}
+
+// getObjectOwner looks for the owner keys in the annotations and labels of the object, and then in the labels of its namespace.
+// Resource types and owner keys are checked in a fixed order, so the same owner is reported on every run.
+func (in *IstioValidationsService) getObjectOwner(key models.IstioValidationKey, namespaceLabels map[string]string, ownerKeys []string) string {
+	resourceTypes := make([]string, 0, 1)
+	for resourceType, objectType := range models.ObjectTypeSingular {
+		if objectType == key.ObjectType {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+	sort.Strings(resourceTypes)
+
+	sources := make([]map[string]string, 0, 3)
+	for _, resourceType := range resourceTypes {
+		if obj, err := in.k8s.GetIstioObject(key.Namespace, resourceType, key.Name); err == nil {
+			sources = append(sources, obj.GetObjectMeta().Annotations, obj.GetObjectMeta().Labels)
+			break
+		}
+	}
+	sources = append(sources, namespaceLabels)
+
+	for _, source := range sources {
+		for _, ownerKey := range ownerKeys {
+			if owner, found := source[ownerKey]; found && owner != "" {
+				return owner
+			}
+		}
+	}
+	return ""
+}
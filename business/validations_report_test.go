@@ -0,0 +1,55 @@
+package business
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestGetObjectOwner(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	annotated := data.CreateEmptyVirtualService("annotated", "bookinfo", []string{"reviews"})
+	meta := annotated.GetObjectMeta()
+	meta.Annotations = map[string]string{"team": "annotation-team"}
+	meta.Labels = map[string]string{"owner": "label-owner"}
+	annotated.SetObjectMeta(meta)
+	labeled := data.CreateEmptyVirtualService("labeled", "bookinfo", []string{"reviews"})
+	meta = labeled.GetObjectMeta()
+	meta.Labels = map[string]string{"team": "label-team"}
+	labeled.SetObjectMeta(meta)
+
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "bookinfo", "virtualservices", "annotated").Return(annotated, nil)
+	k8s.On("GetIstioObject", "bookinfo", "virtualservices", "labeled").Return(labeled, nil)
+	k8s.On("GetIstioObject", "bookinfo", "virtualservices", "missing").Return((*kubernetes.GenericIstioObject)(nil), errors.New("not found"))
+	vs := IstioValidationsService{k8s: k8s}
+
+	ownerKeys := config.Get().KialiFeatureFlags.Validations.Report.OwnerKeys
+	nsLabels := map[string]string{"owner": "namespace-owner"}
+
+	// Annotations are checked before labels, and labels before the namespace labels
+	assert.Equal("annotation-team", vs.getObjectOwner(models.BuildKey("virtualservice", "annotated", "bookinfo"), nsLabels, ownerKeys))
+	assert.Equal("label-owner", vs.getObjectOwner(models.BuildKey("virtualservice", "annotated", "bookinfo"), nsLabels, []string{"owner"}))
+	assert.Equal("label-team", vs.getObjectOwner(models.BuildKey("virtualservice", "labeled", "bookinfo"), nsLabels, ownerKeys))
+	assert.Equal("namespace-owner", vs.getObjectOwner(models.BuildKey("virtualservice", "labeled", "bookinfo"), nsLabels, []string{"owner"}))
+	assert.Equal("namespace-owner", vs.getObjectOwner(models.BuildKey("virtualservice", "missing", "bookinfo"), nsLabels, ownerKeys))
+	assert.Equal("", vs.getObjectOwner(models.BuildKey("virtualservice", "missing", "bookinfo"), nil, ownerKeys))
+
+	// Owner keys are checked in their configured order within a source
+	meta = labeled.GetObjectMeta()
+	meta.Labels = map[string]string{"owner": "label-owner", "team": "label-team"}
+	labeled.SetObjectMeta(meta)
+	for i := 0; i < 10; i++ {
+		assert.Equal("label-owner", vs.getObjectOwner(models.BuildKey("virtualservice", "labeled", "bookinfo"), nsLabels, ownerKeys))
+		assert.Equal("label-team", vs.getObjectOwner(models.BuildKey("virtualservice", "labeled", "bookinfo"), nsLabels, []string{"team", "owner"}))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/prometheus/common/model"
 	"gopkg.in/yaml.v2"
 
 	"github.com/kiali/kiali/config/security"
@@ -104,8 +105,16 @@ type PrometheusConfig struct {
 	// Enable cache for Prometheus queries
 	CacheEnabled bool `yaml:"cache_enabled,omitempty"`
 	// Global cache expiration expressed in seconds
-	CacheExpiration int    `yaml:"cache_expiration:omitempty"`
-	URL             string `yaml:"url,omitempty"`
+	CacheExpiration int `yaml:"cache_expiration:omitempty"`
+	// Enable cache of all query results, instant and range queries. Fresh results are reused for CacheDuration,
+	// range queries are split in sub-ranges and settled sub-ranges are reused until the global cache expiration.
+	// Disabled by default: range queries are aligned on their step, which shifts their datapoints.
+	QueryCacheEnabled bool `yaml:"query_cache_enabled,omitempty"`
+	// Maximum number of cached query results, above which the query cache is cleared
+	QueryCacheMaxEntries int `yaml:"query_cache_max_entries,omitempty"`
+	// Duration of the sub-ranges range queries are split in, expressed in seconds
+	QuerySplitInterval int    `yaml:"query_split_interval,omitempty"`
+	URL                string `yaml:"url,omitempty"`
 }
 
 // CustomDashboardsConfig describes configuration specific to Custom Dashboards
@@ -290,7 +299,60 @@ type DeploymentConfig struct {
 type IstioComponentNamespaces map[string]string
 
 type KialiFeatureFlags struct {
-	IstioInjectionAction bool `yaml:"istio_injection_action,omitempty" json:"istioInjectionAction"`
+	IstioInjectionAction bool              `yaml:"istio_injection_action,omitempty" json:"istioInjectionAction"`
+	Validations          ValidationsConfig `yaml:"validations,omitempty" json:"validations"`
+}
+
+// ValidationsConfig describes the configuration of the Istio config validations
+type ValidationsConfig struct {
+	// Days before the expiration of a gateway certificate when it starts being reported
+	CertificateExpirationWarning int                      `yaml:"certificate_expiration_warning,omitempty" json:"certificateExpirationWarning"`
+	History                      ValidationsHistoryConfig `yaml:"history,omitempty" json:"history"`
+	// Codes of the checks suppressed on all the objects, i.e. KIA1201
+	Ignore []string                `yaml:"ignore,omitempty" json:"ignore"`
+	Jwks   ValidationsJwksConfig   `yaml:"jwks,omitempty" json:"jwks"`
+	Report ValidationsReportConfig `yaml:"report,omitempty" json:"report"`
+	// Istio release (i.e. 1.6) the objects are validated against, to plan an upgrade.
+	// When empty, the release of the detected control plane is used.
+	TargetVersion string `yaml:"target_version,omitempty" json:"targetVersion"`
+}
+
+// ValidationsJwksConfig describes how the JWKS referenced by the jwksUri of RequestAuthentications are fetched
+type ValidationsJwksConfig struct {
+	// Hosts (i.e. issuer.example.com or issuer.example.com:8443) the jwksUri can be fetched from.
+	// When empty, no jwksUri is fetched.
+	AllowedHosts []string `yaml:"allowed_hosts,omitempty" json:"allowedHosts"`
+	// When disabled, the jwksUri are not fetched and only inline JWKS are validated
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled"`
+	// Seconds a fetched JWKS is reused before fetching it again
+	CacheDuration int `yaml:"cache_duration,omitempty" json:"cacheDuration"`
+	// Maximum number of JWKS kept in cache
+	MaxEntries int `yaml:"max_entries,omitempty" json:"maxEntries"`
+	// Seconds to wait for the jwksUri to respond
+	Timeout int `yaml:"timeout,omitempty" json:"timeout"`
+}
+
+// ValidationsReportConfig describes the mesh-wide validations report
+type ValidationsReportConfig struct {
+	// Annotations or labels, checked in order, holding the owner of an object or namespace
+	OwnerKeys []string `yaml:"owner_keys,omitempty" json:"ownerKeys"`
+	// Maximum number of offending objects listed
+	TopObjects int `yaml:"top_objects,omitempty" json:"topObjects"`
+	// Number of namespaces validated concurrently
+	Workers int `yaml:"workers,omitempty" json:"workers"`
+}
+
+// ValidationsHistoryConfig describes the background validator that keeps track of the validations over time
+type ValidationsHistoryConfig struct {
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled"`
+	// Interval between two validation runs expressed in seconds
+	Interval int `yaml:"interval,omitempty" json:"interval"`
+	// Number of trend points and regressions kept per namespace
+	MaxEntries int `yaml:"max_entries,omitempty" json:"maxEntries"`
+	// Optional ConfigMap, in the Kiali deployment namespace, where the history is persisted
+	PersistenceConfigMap string `yaml:"persistence_config_map,omitempty" json:"-"`
+	// Optional file where the history is persisted
+	PersistenceFile string `yaml:"persistence_file,omitempty" json:"-"`
 }
 
 // ToleranceConfig
@@ -300,6 +362,11 @@ type Tolerance struct {
 	Failure   float32 `yaml:"failure,omitempty" json:"failure"`
 	Protocol  string  `yaml:"protocol,omitempty" json:"protocol"`
 	Direction string  `yaml:"direction,omitempty" json:"direction"`
+	// Latency tolerance: when Quantile is set (i.e. 0.99), the latency of the requests at that quantile
+	// is compared to LatencyDegraded and LatencyFailure, in milliseconds
+	Quantile        float64 `yaml:"quantile,omitempty" json:"quantile,omitempty"`
+	LatencyDegraded float64 `yaml:"latency_degraded,omitempty" json:"latencyDegraded,omitempty"`
+	LatencyFailure  float64 `yaml:"latency_failure,omitempty" json:"latencyFailure,omitempty"`
 }
 
 // RateConfig
@@ -310,9 +377,100 @@ type Rate struct {
 	Tolerance []Tolerance `yaml:"tolerance,omitempty" json:"tolerance"`
 }
 
+// SLO is a service level objective of the apps, services or workloads matched by the Namespace, Kind and Name
+// regular expressions. Services can declare their own objectives with the kiali.io/slo annotation, when enabled.
+type SLO struct {
+	// Identifies the objective among the ones of an object, i.e. availability
+	Id        string `yaml:"id" json:"id"`
+	Namespace string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	Kind      string `yaml:"kind,omitempty" json:"kind,omitempty"`
+	Name      string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Type of the good requests: "availability" for the ones not failing, "latency" for the ones faster than LatencyThreshold
+	Type string `yaml:"type" json:"type"`
+	// Percentage of good requests, i.e. 99.9
+	Objective float64 `yaml:"objective" json:"objective"`
+	// Milliseconds of the latency objectives. It must be a bucket boundary of istio_request_duration_milliseconds,
+	// otherwise the objective has no data
+	LatencyThreshold float64 `yaml:"latency_threshold,omitempty" json:"latencyThreshold,omitempty"`
+	// Rolling window the objective is evaluated on, 30d by default
+	Window string `yaml:"window,omitempty" json:"window,omitempty"`
+}
+
+// Validate checks that the objective can be evaluated: a known type, a latency threshold for the latency objectives,
+// an objective strictly between 0 and 100 and a valid window.
+func (slo SLO) Validate() error {
+	switch slo.Type {
+	case "availability":
+	case "latency":
+		if slo.LatencyThreshold <= 0 {
+			return fmt.Errorf("latency objectives require a positive latency threshold")
+		}
+	default:
+		return fmt.Errorf("unknown type [%s], expecting availability or latency", slo.Type)
+	}
+	if slo.Objective <= 0 || slo.Objective >= 100 {
+		return fmt.Errorf("objective %v is not strictly between 0 and 100", slo.Objective)
+	}
+	if slo.Window != "" {
+		if window, err := model.ParseDuration(slo.Window); err != nil || window <= 0 {
+			return fmt.Errorf("invalid window [%s]", slo.Window)
+		}
+	}
+	return nil
+}
+
+// HealthNotificationsConfig describes the background health evaluator notifying the health changes of the apps,
+// services and workloads of the namespaces accessible by Kiali
+type HealthNotificationsConfig struct {
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled"`
+	// Interval between two health evaluations expressed in seconds
+	Interval int `yaml:"interval,omitempty" json:"interval"`
+	// Rate interval of the evaluated health, i.e. 5m
+	RateInterval string `yaml:"rate_interval,omitempty" json:"rateInterval"`
+	// Seconds a new health status must last before being notified, so flapping objects are not notified
+	Debounce int `yaml:"debounce,omitempty" json:"debounce"`
+	// Number of events kept in the in-memory feed
+	MaxEvents int                      `yaml:"max_events,omitempty" json:"maxEvents"`
+	Sinks     []HealthNotificationSink `yaml:"sinks,omitempty" json:"-"`
+}
+
+// HealthNotificationSink receives the health change events of the namespaces matching its Namespaces regular expression
+type HealthNotificationSink struct {
+	Name string `yaml:"name,omitempty"`
+	// "webhook" posts the events as JSON, "slack" posts them with the Slack incoming webhook format
+	Type       string `yaml:"type,omitempty"`
+	URL        string `yaml:"url,omitempty"`
+	Auth       Auth   `yaml:"auth,omitempty"`
+	Namespaces string `yaml:"namespaces,omitempty"`
+	// Seconds to wait for the sink to respond
+	Timeout int `yaml:"timeout,omitempty"`
+}
+
+// HealthAnomaliesConfig describes the comparison of the requests received by the apps, services and workloads
+// with their baselines, the same requests at the same time one period ago
+type HealthAnomaliesConfig struct {
+	Enabled bool `yaml:"enabled,omitempty" json:"enabled"`
+	// Offsets of the baselines, i.e. 1d for the same time yesterday and 1w for the same time last week
+	Baselines []string `yaml:"baselines,omitempty" json:"baselines"`
+	// Quantile of the compared latency, i.e. 0.95
+	Quantile float64 `yaml:"quantile,omitempty" json:"quantile"`
+	// Minimum relative deviation of an anomaly, i.e. 0.5 for a signal 50% higher or lower than expected
+	Threshold float64 `yaml:"threshold,omitempty" json:"threshold"`
+	// Request rate (rps), error ratio and latency (ms) under which a signal is too low to be compared
+	MinRate       float64 `yaml:"min_rate,omitempty" json:"minRate"`
+	MinErrorRatio float64 `yaml:"min_error_ratio,omitempty" json:"minErrorRatio"`
+	MinLatency    float64 `yaml:"min_latency,omitempty" json:"minLatency"`
+}
+
 // HealthConfig
 type HealthConfig struct {
-	Rate []Rate `yaml:"rate,omitempty" json:"rate"`
+	Anomalies     HealthAnomaliesConfig     `yaml:"anomalies,omitempty" json:"anomalies"`
+	Notifications HealthNotificationsConfig `yaml:"notifications,omitempty" json:"notifications"`
+	Rate          []Rate                    `yaml:"rate,omitempty" json:"rate"`
+	SLO           []SLO                     `yaml:"slo,omitempty" json:"slo,omitempty"`
+	// Reads the objectives declared by the kiali.io/slo annotation of the services, which requires reading the
+	// services on their health requests
+	SLOAnnotations bool `yaml:"slo_annotations,omitempty" json:"sloAnnotations"`
 }
 
 // Config defines full YAML configuration.
@@ -425,8 +583,11 @@ func NewConfig() (c *Config) {
 				// 1/2 Prom Scrape Interval
 				CacheDuration: 7,
 				// Prom Cache expires and it forces to repopulate cache
-				CacheExpiration: 300,
-				URL:             "http://prometheus.istio-system:9090",
+				CacheExpiration:      300,
+				QueryCacheEnabled:    false,
+				QueryCacheMaxEntries: 10000,
+				QuerySplitInterval:   900,
+				URL:                  "http://prometheus.istio-system:9090",
 			},
 			Tracing: TracingConfig{
 				Auth: Auth{
@@ -440,6 +601,24 @@ func NewConfig() (c *Config) {
 				WhiteListIstioSystem: []string{"jaeger-query", "istio-ingressgateway"},
 			},
 		},
+		HealthConfig: HealthConfig{
+			Anomalies: HealthAnomaliesConfig{
+				Enabled:       false,
+				Baselines:     []string{"1d", "1w"},
+				Quantile:      0.95,
+				Threshold:     0.5,
+				MinRate:       0.1,
+				MinErrorRatio: 0.01,
+				MinLatency:    10,
+			},
+			Notifications: HealthNotificationsConfig{
+				Enabled:      false,
+				Interval:     60,
+				RateInterval: "5m",
+				Debounce:     120,
+				MaxEvents:    500,
+			},
+		},
 		IstioLabels: IstioLabels{
 			AppLabelName:       "app",
 			InjectionLabelName: "istio-injection",
@@ -447,6 +626,27 @@ func NewConfig() (c *Config) {
 		},
 		KialiFeatureFlags: KialiFeatureFlags{
 			IstioInjectionAction: true,
+			Validations: ValidationsConfig{
+				CertificateExpirationWarning: 30,
+				Ignore:                       []string{},
+				Jwks: ValidationsJwksConfig{
+					AllowedHosts:  []string{},
+					Enabled:       false,
+					CacheDuration: 5 * 60,
+					MaxEntries:    100,
+					Timeout:       5,
+				},
+				History: ValidationsHistoryConfig{
+					Enabled:    false,
+					Interval:   5 * 60,
+					MaxEntries: 288,
+				},
+				Report: ValidationsReportConfig{
+					OwnerKeys:  []string{"owner", "team"},
+					TopObjects: 20,
+					Workers:    5,
+				},
+			},
 		},
 		KubernetesConfig: KubernetesConfig{
 			Burst:                       200,
@@ -479,6 +679,38 @@ func NewConfig() (c *Config) {
 	return
 }
 
+// DefaultTolerances returns the request tolerances of the health default configuration,
+// the same ones the UI applies when no rate configuration matches
+func DefaultTolerances() []Tolerance {
+	return []Tolerance{
+		{
+			Code:      "^5\\d\\d$",
+			Protocol:  "http",
+			Direction: ".*",
+			Failure:   10,
+		},
+		{
+			Code:      "^4\\d\\d$",
+			Protocol:  "http",
+			Direction: ".*",
+			Degraded:  10,
+			Failure:   20,
+		},
+		{
+			Code:      "^[1-9]$|^1[0-6]$",
+			Protocol:  "grpc",
+			Direction: ".*",
+			Failure:   10,
+		},
+		{
+			Code:      "^-$", // no response is indicated with a "-" code
+			Protocol:  "http|grpc",
+			Direction: ".*",
+			Failure:   10,
+		},
+	}
+}
+
 // AddHealthDefault Configuration
 func (conf *Config) AddHealthDefault() {
 	// Health default configuration
@@ -488,33 +720,7 @@ func (conf *Config) AddHealthDefault() {
 				Namespace: ".*",
 				Kind:      ".*",
 				Name:      ".*",
-				Tolerance: []Tolerance{
-					{
-						Code:      "^5\\d\\d$",
-						Protocol:  "http",
-						Direction: ".*",
-						Failure:   10,
-					},
-					{
-						Code:      "^4\\d\\d$",
-						Protocol:  "http",
-						Direction: ".*",
-						Degraded:  10,
-						Failure:   20,
-					},
-					{
-						Code:      "^[1-9]$|^1[0-6]$",
-						Protocol:  "grpc",
-						Direction: ".*",
-						Failure:   10,
-					},
-					{
-						Code:      "^-$", // no response is indicated with a "-" code
-						Protocol:  "http|grpc",
-						Direction: ".*",
-						Failure:   10,
-					},
-				},
+				Tolerance: DefaultTolerances(),
 			},
 		},
 	}
@@ -549,6 +755,12 @@ func (conf Config) String() (str string) {
 	obf.Identity.Obfuscate()
 	obf.LoginToken.Obfuscate()
 	obf.Auth.OpenId.ClientSecret = "xxx"
+	obf.HealthConfig.Notifications.Sinks = make([]HealthNotificationSink, len(conf.HealthConfig.Notifications.Sinks))
+	for i, sink := range conf.HealthConfig.Notifications.Sinks {
+		sink.Auth.Obfuscate()
+		sink.URL = "xxx"
+		obf.HealthConfig.Notifications.Sinks[i] = sink
+	}
 	str, err := Marshal(&obf)
 	if err != nil {
 		str = fmt.Sprintf("Failed to marshal config to string. err=%v", err)
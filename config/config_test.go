@@ -213,6 +213,20 @@ func TestError(t *testing.T) {
 	}
 }
 
+func TestSLOValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(SLO{Id: "availability", Type: "availability", Objective: 99.9}.Validate())
+	assert.NoError(SLO{Id: "latency", Type: "latency", Objective: 99, LatencyThreshold: 250, Window: "7d"}.Validate())
+
+	assert.Error(SLO{Id: "latency", Type: "latency", Objective: 99}.Validate())
+	assert.Error(SLO{Id: "latency", Type: "Latency", Objective: 99, LatencyThreshold: 250}.Validate())
+	assert.Error(SLO{Id: "availability", Objective: 99}.Validate())
+	assert.Error(SLO{Id: "availability", Type: "availability", Objective: 100}.Validate())
+	assert.Error(SLO{Id: "availability", Type: "availability", Objective: 0}.Validate())
+	assert.Error(SLO{Id: "availability", Type: "availability", Objective: 99, Window: "30 days"}.Validate())
+}
+
 func TestRaces(t *testing.T) {
 
 	wg := sync.WaitGroup{}
@@ -7,6 +7,7 @@ import (
 	"github.com/kiali/kiali/graph/config/cytoscape"
 	"github.com/kiali/kiali/handlers"
 	"github.com/kiali/kiali/jaeger"
+	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/status"
 )
@@ -34,7 +35,7 @@ type AggregateValueParam struct {
 	Name string `json:"aggregateValue"`
 }
 
-// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces errorTraces
+// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces errorTraces appSLOs
 type AppParam struct {
 	// The app name (label value).
 	//
@@ -61,7 +62,7 @@ type ContainerParam struct {
 	Name string `json:"container"`
 }
 
-// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments
+// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations namespaceValidationsHistory workloadConfigDrift istioConfigCreatePreview istioConfigUpdatePreview istioConfigFix istioConfigDependencies sidecarsGenerate getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments serviceSLOs appSLOs workloadSLOs
 type NamespaceParam struct {
 	// The namespace name.
 	//
@@ -79,7 +80,7 @@ type NameParam struct {
 	Name string `json:"name"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigUpdatePreview istioConfigFix
 type ObjectNameParam struct {
 	// The Istio object name.
 	//
@@ -88,7 +89,7 @@ type ObjectNameParam struct {
 	Name string `json:"object"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype istioConfigCreatePreview istioConfigUpdatePreview istioConfigFix
 type ObjectTypeParam struct {
 	// The Istio object type.
 	//
@@ -98,6 +99,89 @@ type ObjectTypeParam struct {
 	Name string `json:"object_type"`
 }
 
+// swagger:parameters workloadConfigDrift
+type ProxyPodParam struct {
+	// The pod whose proxy configuration is compared. Defaults to the first pod of the workload with a sidecar.
+	//
+	// in: query
+	// required: false
+	Name string `json:"pod"`
+}
+
+// swagger:parameters istioConfigFix
+type IstioCheckFixRequestParam struct {
+	// The check of the object whose fix is applied.
+	//
+	// in: body
+	// required: true
+	Body models.IstioCheckFixRequest
+}
+
+// swagger:parameters sidecarsGenerate
+type SidecarsGenerateWorkloadParam struct {
+	// Generate only the Sidecar of this workload.
+	//
+	// in: query
+	// required: false
+	Name string `json:"workload"`
+}
+
+// swagger:parameters sidecarsGenerate
+type SidecarsGenerateRateIntervalParam struct {
+	// Interval used to observe the outbound traffic of the workloads.
+	//
+	// in: query
+	// required: false
+	// default: 1h
+	Name string `json:"rateInterval"`
+}
+
+// swagger:parameters istioConfigDependencies
+type IstioDependenciesObjectTypeParam struct {
+	// Type of the object whose dependents are returned, i.e. gateway or service.
+	//
+	// in: query
+	// required: false
+	Name string `json:"objectType"`
+}
+
+// swagger:parameters istioConfigDependencies
+type IstioDependenciesObjectParam struct {
+	// Name of the object whose dependents are returned.
+	//
+	// in: query
+	// required: false
+	Name string `json:"object"`
+}
+
+// swagger:parameters istioConfigDependencies
+type IstioDependenciesObjectNamespaceParam struct {
+	// Namespace of the object whose dependents are returned. Defaults to the namespace of the graph.
+	//
+	// in: query
+	// required: false
+	Name string `json:"objectNamespace"`
+}
+
+// swagger:parameters meshValidations
+type MeshValidationsFormatParam struct {
+	// Format of the report: json, csv or html. The csv report holds the namespaces, checks and top objects sections, told apart by its section column.
+	//
+	// in: query
+	// required: false
+	// default: json
+	Name string `json:"format"`
+}
+
+// swagger:parameters meshValidations
+type MeshValidationsTopParam struct {
+	// Maximum number of offending objects listed. Defaults to the Kiali configuration.
+	//
+	// in: query
+	// required: false
+	Name int `json:"top"`
+}
+
 // swagger:parameters podDetails podLogs
 type PodParam struct {
 	// The pod name.
@@ -107,7 +191,7 @@ type PodParam struct {
 	Name string `json:"pod"`
 }
 
-// swagger:parameters serviceDetails serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces
+// swagger:parameters serviceDetails serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces serviceSLOs
 type ServiceParam struct {
 	// The service name.
 	//
@@ -153,7 +237,7 @@ type DashboardParam struct {
 	Name string `json:"dashboard"`
 }
 
-// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces
+// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadConfigDrift workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces workloadSLOs
 type WorkloadParam struct {
 	// The workload name.
 	//
@@ -169,11 +253,11 @@ type WorkloadParam struct {
 
 // swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
 type AppendersParam struct {
-	// Comma-separated list of Appenders to run. Available appenders: [deadNode, istio, aggregateNode, responseTime, securityPolicy, serviceEntry, sidecarsCheck, unusedNode].
+	// Comma-separated list of Appenders to run. Available appenders: [anomaly, deadNode, istio, aggregateNode, responseTime, securityPolicy, serviceEntry, sidecarsCheck, unusedNode].
 	//
 	// in: query
 	// required: false
-	// default: run all appenders
+	// default: run all appenders but anomaly
 	Name string `json:"appenders"`
 }
 
@@ -310,6 +394,17 @@ type LabelsFiltersParam struct {
 	Name string `json:"labelsFilters"`
 }
 
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics namespaceMetrics customDashboard
+type MetricsFormatParam struct {
+	// Format of the metrics: json, csv or openmetrics. Defaults to the format negotiated with the Accept header
+	// (text/csv, application/openmetrics-text), else json. Custom dashboards only export the metrics of their charts in csv or openmetrics.
+	//
+	// in: query
+	// required: false
+	// default: json
+	Name string `json:"format"`
+}
+
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics customDashboard appDashboard serviceDashboard workloadDashboard
 type QuantilesParam struct {
 	// List of quantiles to fetch. Fetch no quantiles when empty. Ex: [0.5, 0.95, 0.99].
@@ -370,6 +465,15 @@ type StepParam struct {
 	Name int `json:"step"`
 }
 
+// swagger:parameters customDashboard
+type VariableParam struct {
+	// In custom dashboards, the value(s) of a templated variable, as "var-<name>=<value>". Repeat the parameter or comma-separate the values for multi-valued variables. Defaults to the variable default value, or its first option.
+	//
+	// in: query
+	// required: false
+	Name []string `json:"var-name"`
+}
+
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics
 type VersionParam struct {
 	// Filters metrics by the specified version.
@@ -536,6 +640,20 @@ type workloadHealthResponse struct {
 	Body models.WorkloadHealth
 }
 
+// sloResponse is the evaluation of the service level objectives of an app, service or workload
+// swagger:response sloResponse
+type sloResponse struct {
+	// in:body
+	Body []models.SLOStatus
+}
+
+// namespaceHealthEventsResponse is the list of the health changes detected in a namespace
+// swagger:response namespaceHealthEventsResponse
+type namespaceHealthEventsResponse struct {
+	// in:body
+	Body models.HealthEvents
+}
+
 // namespaceAppHealthResponse is a map of app name x health
 // swagger:response namespaceAppHealthResponse
 type namespaceAppHealthResponse struct {
@@ -606,6 +724,20 @@ type IstioConfigDetailsResponse struct {
 	Body models.IstioConfigDetails
 }
 
+// Validations and traffic changes that an Istio object change would cause
+// swagger:response istioConfigPreviewResponse
+type IstioConfigPreviewResponse struct {
+	// in:body
+	Body models.IstioConfigPreview
+}
+
+// Sidecars proposed from the observed traffic of the workloads
+// swagger:response sidecarsGenerateResponse
+type SidecarsGenerateResponse struct {
+	// in:body
+	Body []kubernetes.GenericIstioObject
+}
+
 // Detailed information of an specific app
 // swagger:response appDetails
 type AppDetailsResponse struct {
@@ -662,6 +794,34 @@ type NamespaceValidationSummaryResponse struct {
 	Body models.IstioValidationSummary
 }
 
+// Return the references between the Istio objects of a namespace and the objects they depend on
+// swagger:response istioDependencyGraphResponse
+type IstioDependencyGraphResponse struct {
+	// in:body
+	Body models.IstioDependencyGraph
+}
+
+// Return the validations summary of all the namespaces of the mesh
+// swagger:response meshValidationsResponse
+type MeshValidationsResponse struct {
+	// in:body
+	Body models.MeshValidationsReport
+}
+
+// Return the validations history of a specific Namespace
+// swagger:response namespaceValidationsHistoryResponse
+type NamespaceValidationsHistoryResponse struct {
+	// in:body
+	Body models.ValidationsHistory
+}
+
+// Return a list of validations grouped by object type
+// swagger:response typedIstioValidationsResponse
+type TypedIstioValidationsResponse struct {
+	// in:body
+	Body TypedIstioValidations
+}
+
 //////////////////
 // SWAGGER MODELS
 //////////////////
@@ -722,3 +882,49 @@ type MetricsStatsResponse struct {
 	// in: body
 	Body models.MetricsStats
 }
+
+// Posted parameters for a metrics comparison
+// swagger:parameters metricsComparison
+type MetricsComparisonQueryBody struct {
+	// in: body
+	Body models.MetricsComparisonQuery
+}
+
+// swagger:parameters grafanaDashboardConvert
+type GrafanaDashboardBody struct {
+	// The Grafana dashboard JSON, as exported from Grafana or returned by its API
+	// in: body
+	Body map[string]interface{}
+}
+
+// swagger:parameters grafanaDashboardConvert
+type GrafanaDashboardNameParam struct {
+	// Name of the MonitoringDashboard resource. Defaults to the Grafana dashboard title, as a Kubernetes name.
+	//
+	// in: query
+	// required: false
+	Name string `json:"name"`
+}
+
+// swagger:parameters grafanaDashboardConvert
+type GrafanaDashboardRuntimeParam struct {
+	// Runtime of the MonitoringDashboard resource. Defaults to the Grafana dashboard title.
+	//
+	// in: query
+	// required: false
+	Name string `json:"runtime"`
+}
+
+// Result of the conversion of a Grafana dashboard
+// swagger:response grafanaDashboardConversionResponse
+type GrafanaDashboardConversionResponse struct {
+	// in: body
+	Body models.GrafanaDashboardConversion
+}
+
+// Response of the metrics comparison
+// swagger:response metricsComparisonResponse
+type MetricsComparisonResponse struct {
+	// in: body
+	Body models.MetricsComparison
+}
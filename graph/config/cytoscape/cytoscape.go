@@ -20,6 +20,7 @@ import (
 	"sort"
 
 	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
 )
 
 // ResponseFlags is a map of maps. Each response code is broken down by responseFlags:percentageOfTraffic, e.g.:
@@ -68,6 +69,7 @@ type NodeData struct {
 	Aggregate       string              `json:"aggregate,omitempty"`       // set like "<aggregate>=<aggregateVal>"
 	DestServices    []graph.ServiceName `json:"destServices,omitempty"`    // requested services for [dest] node
 	Traffic         []ProtocolTraffic   `json:"traffic,omitempty"`         // traffic rates for all detected protocols
+	Anomalies       []models.Anomaly    `json:"anomalies,omitempty"`       // anomalous request signals, compared to their baselines
 	HasCB           bool                `json:"hasCB,omitempty"`           // true (has circuit breaker) | false
 	HasMissingSC    bool                `json:"hasMissingSC,omitempty"`    // true (has missing sidecar) | false
 	HasVS           bool                `json:"hasVS,omitempty"`           // true (has route rule) | false
@@ -234,6 +236,11 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			nd.HasMissingSC = val.(bool)
 		}
 
+		// node may have requests deviating from their baselines
+		if val, ok := n.Metadata[graph.Anomalies]; ok {
+			nd.Anomalies = val.([]models.Anomaly)
+		}
+
 		// check if node is misconfigured
 		if val, ok := n.Metadata[graph.IsMisconfigured]; ok {
 			nd.IsMisconfigured = val.(string)
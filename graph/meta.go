@@ -15,6 +15,7 @@ func NewMetadata() Metadata {
 const (
 	Aggregate       MetadataKey = "aggregate" // the prom attribute used for aggregation
 	AggregateValue  MetadataKey = "aggregateValue"
+	Anomalies       MetadataKey = "anomalies" // anomalous request signals, compared to their baselines
 	DestPrincipal   MetadataKey = "destPrincipal"
 	DestServices    MetadataKey = "destServices"
 	HasCB           MetadataKey = "hasCB"
@@ -0,0 +1,100 @@
+package appender
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// AnomalyAppenderName uniquely identifies the appender: anomaly
+	AnomalyAppenderName = "anomaly"
+)
+
+// AnomalyAppender is responsible for flagging the nodes whose received requests deviate from their baselines:
+// the same requests at the same time of the baseline periods configured in health_config.anomalies. The anomalous
+// request rates, error ratios and latencies are set on the nodes. It is not part of the default appenders,
+// since it queries Prometheus for each baseline.
+// Name: anomaly
+type AnomalyAppender struct {
+	Namespaces graph.NamespaceInfoMap
+	QueryTime  int64 // unix time in seconds
+}
+
+// Name implements Appender
+func (a AnomalyAppender) Name() string {
+	return AnomalyAppenderName
+}
+
+// AppendGraph implements Appender
+func (a AnomalyAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, business.NewMetricsService(globalInfo.PromClient))
+}
+
+func (a AnomalyAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, metrics *business.MetricsService) {
+	duration := a.Namespaces[namespace].Duration
+	log.Tracef("Generating anomalies; namespace = %v", namespace)
+
+	// Anomalies by kind then object name, only queried for the kinds of the nodes of the namespace
+	anomalies := map[string]map[string][]models.Anomaly{}
+	getAnomalies := func(kind, name string) []models.Anomaly {
+		if _, ok := anomalies[kind]; !ok {
+			kindAnomalies, err := metrics.GetAnomalies(models.AnomaliesQuery{
+				Namespace:    namespace,
+				Kind:         kind,
+				RateInterval: fmt.Sprintf("%ds", int(duration.Seconds())),
+				QueryTime:    time.Unix(a.QueryTime, 0),
+			})
+			graph.CheckError(err)
+			anomalies[kind] = kindAnomalies
+		}
+		return anomalies[kind][name]
+	}
+
+	for _, n := range trafficMap {
+		// Only the requested namespaces are compared to their baselines
+		if n.Namespace != namespace {
+			continue
+		}
+
+		var nodeAnomalies []models.Anomaly
+		switch n.NodeType {
+		case graph.NodeTypeService:
+			nodeAnomalies = getAnomalies("service", n.Service)
+		case graph.NodeTypeWorkload:
+			nodeAnomalies = getAnomalies("workload", n.Workload)
+		case graph.NodeTypeApp:
+			// Versioned app nodes are backed by a workload
+			if graph.IsOK(n.Workload) {
+				nodeAnomalies = getAnomalies("workload", n.Workload)
+			} else if graph.IsOK(n.App) {
+				nodeAnomalies = getAnomalies("app", n.App)
+			}
+		}
+
+		anomalous := []models.Anomaly{}
+		for _, anomaly := range nodeAnomalies {
+			if anomaly.Anomalous {
+				anomalous = append(anomalous, anomaly)
+			}
+		}
+		if len(anomalous) > 0 {
+			n.Metadata[graph.Anomalies] = anomalous
+		}
+	}
+}
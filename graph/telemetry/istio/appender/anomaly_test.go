@@ -0,0 +1,71 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func TestAnomaly(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.HealthConfig.Anomalies.Baselines = []string{"1d"}
+	config.Set(conf)
+
+	trafficMap := graph.NewTrafficMap()
+	reviews := graph.NewNode("bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	trafficMap[reviews.ID] = &reviews
+	ratings := graph.NewNode("bookinfo", "ratings", "bookinfo", "ratings-v1", "ratings", "v1", graph.GraphTypeVersionedApp)
+	trafficMap[ratings.ID] = &ratings
+	service := graph.NewNode("bookinfo", "reviews", "", "", "", "", graph.GraphTypeVersionedApp)
+	trafficMap[service.ID] = &service
+	outside := graph.NewNode("other", "details", "other", "details-v1", "details", "v1", graph.GraphTypeVersionedApp)
+	trafficMap[outside.ID] = &outside
+
+	sample := func(label, name string, value float64) *model.Sample {
+		return &model.Sample{Metric: model.Metric{model.LabelName(label): model.LabelValue(name)}, Value: model.SampleValue(value)}
+	}
+	wlLabels := `reporter="destination",destination_workload_namespace="bookinfo"`
+	svcLabels := `reporter="destination",destination_service_namespace="bookinfo"`
+	prom := new(prometheustest.PromClientMock)
+	prom.On("GetRequestSignals", wlLabels, "destination_workload", 0.95, "60s", "", mock.AnythingOfType("time.Time")).Return(prometheus.RequestSignals{
+		Rates: model.Vector{sample("destination_workload", "reviews-v1", 1), sample("destination_workload", "ratings-v1", 10)},
+	}, nil)
+	prom.On("GetRequestSignals", wlLabels, "destination_workload", 0.95, "60s", "1d", mock.AnythingOfType("time.Time")).Return(prometheus.RequestSignals{
+		Rates: model.Vector{sample("destination_workload", "reviews-v1", 10), sample("destination_workload", "ratings-v1", 11)},
+	}, nil)
+	prom.On("GetRequestSignals", svcLabels, "destination_service_name", 0.95, "60s", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(prometheus.RequestSignals{
+		Rates: model.Vector{sample("destination_service_name", "reviews", 5)},
+	}, nil)
+
+	duration, _ := time.ParseDuration("60s")
+	a := AnomalyAppender{
+		Namespaces: graph.NamespaceInfoMap{"bookinfo": graph.NamespaceInfo{Name: "bookinfo", Duration: duration}},
+		QueryTime:  time.Now().Unix(),
+	}
+	a.appendGraph(trafficMap, "bookinfo", business.NewMetricsService(prom))
+
+	anomalies, ok := reviews.Metadata[graph.Anomalies].([]models.Anomaly)
+	assert.True(ok)
+	assert.Len(anomalies, 1)
+	assert.Equal(models.AnomalyRequestRate, anomalies[0].Signal)
+	assert.Equal("1d", anomalies[0].Baseline)
+	assert.InDelta(-0.9, anomalies[0].Score, 1e-9)
+
+	// Stable traffic and nodes of other namespaces are not flagged
+	assert.Nil(ratings.Metadata[graph.Anomalies])
+	assert.Nil(service.Metadata[graph.Anomalies])
+	assert.Nil(outside.Metadata[graph.Anomalies])
+	prom.AssertNumberOfCalls(t, "GetRequestSignals", 4)
+}
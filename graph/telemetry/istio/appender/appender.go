@@ -24,6 +24,8 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 			switch appenderName {
 			case AggregateNodeAppenderName:
 				requestedAppenders[AggregateNodeAppenderName] = true
+			case AnomalyAppenderName:
+				requestedAppenders[AnomalyAppenderName] = true
 			case DeadNodeAppenderName:
 				requestedAppenders[DeadNodeAppenderName] = true
 			case IstioAppenderName:
@@ -84,6 +86,14 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 		}
 		appenders = append(appenders, a)
 	}
+	// Only when requested, the baselines are costly to query
+	if _, ok := requestedAppenders[AnomalyAppenderName]; ok {
+		a := AnomalyAppender{
+			Namespaces: o.Namespaces,
+			QueryTime:  o.QueryTime,
+		}
+		appenders = append(appenders, a)
+	}
 	if _, ok := requestedAppenders[SecurityPolicyAppenderName]; ok || o.Appenders.All {
 		a := SecurityPolicyAppender{
 			GraphType:          o.GraphType,
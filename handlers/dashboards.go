@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/gorilla/mux"
+	"github.com/prometheus/common/model"
 	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business"
@@ -24,6 +26,10 @@ func CustomDashboard(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusServiceUnavailable, "Custom dashboards are disabled in config")
 		return
 	}
+	if _, err := metricsFormat(r); err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Check namespace
 	layer, err := getBusiness(r)
@@ -52,12 +58,16 @@ func CustomDashboard(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, dashboard)
+	respondWithDashboard(w, r, dashboard)
 }
 
 func extractDashboardQueryParams(queryParams url.Values, q *models.DashboardQuery, namespaceInfo *models.Namespace) error {
 	q.FillDefaults()
-	q.LabelsFilters = extractLabelsFilters(queryParams.Get("labelsFilters"))
+	labelsFilters, err := extractLabelsFilters(queryParams.Get("labelsFilters"))
+	if err != nil {
+		return err
+	}
+	q.LabelsFilters = labelsFilters
 	additionalLabels := strings.Split(queryParams.Get("additionalLabels"), ",")
 	for _, additionalLabel := range additionalLabels {
 		kvPair := strings.Split(additionalLabel, ":")
@@ -74,19 +84,44 @@ func extractDashboardQueryParams(queryParams url.Values, q *models.DashboardQuer
 	if op == "sum" || op == "min" || op == "max" || op == "avg" || op == "stddev" || op == "stdvar" {
 		q.RawDataAggregator = op
 	}
+	q.Variables = extractDashboardVariables(queryParams)
 	return extractBaseMetricsQueryParams(queryParams, &q.RangeQuery, namespaceInfo)
 }
 
-func extractLabelsFilters(rawString string) map[string]string {
+// extractDashboardVariables reads the values of the dashboard variables, passed as "var-<name>" query params
+func extractDashboardVariables(queryParams url.Values) map[string][]string {
+	variables := make(map[string][]string)
+	for key, values := range queryParams {
+		if name := strings.TrimPrefix(key, "var-"); name != key && name != "" {
+			for _, value := range values {
+				// Multiple values can be passed either as repeated params or comma-separated
+				for _, v := range strings.Split(value, ",") {
+					if v = strings.TrimSpace(v); v != "" {
+						variables[name] = append(variables[name], v)
+					}
+				}
+			}
+		}
+	}
+	return variables
+}
+
+// extractLabelsFilters reads the "label:value" comma-separated filters. Label names are written as is in the
+// queries, so anything that isn't a valid Prometheus label name is rejected.
+func extractLabelsFilters(rawString string) (map[string]string, error) {
 	labelsFilters := make(map[string]string)
 	rawFilters := strings.Split(rawString, ",")
 	for _, rawFilter := range rawFilters {
 		kvPair := strings.Split(rawFilter, ":")
 		if len(kvPair) == 2 {
-			labelsFilters[strings.TrimSpace(kvPair[0])] = strings.TrimSpace(kvPair[1])
+			label := strings.TrimSpace(kvPair[0])
+			if !model.LabelName(label).IsValid() {
+				return nil, fmt.Errorf("invalid label name in labelsFilters: %q", label)
+			}
+			labelsFilters[label] = strings.TrimSpace(kvPair[1])
 		}
 	}
-	return labelsFilters
+	return labelsFilters, nil
 }
 
 // AppDashboard is the API handler to fetch Istio dashboard, related to a single app
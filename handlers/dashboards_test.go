@@ -53,3 +53,14 @@ func TestExtractDashboardQueryParams(t *testing.T) {
 		DisplayName: "YY",
 	}, params.AdditionalLabels[1])
 }
+
+func TestExtractDashboardQueryParamsRejectsInvalidLabels(t *testing.T) {
+	queryParams := url.Values{
+		"labelsFilters": []string{`app:foo,a="b"} or istio_requests_total{c!:x`},
+	}
+
+	params := models.DashboardQuery{Namespace: "test"}
+	err := extractDashboardQueryParams(queryParams, &params, buildNamespace("ns", time.Time{}))
+
+	assert.EqualError(t, err, `invalid label name in labelsFilters: "a=\"b\"} or istio_requests_total{c!"`)
+}
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
 	"github.com/kiali/kiali/log"
@@ -37,6 +39,26 @@ func GetGrafanaInfo(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, code, info)
 }
 
+// ConvertGrafanaDashboard is the API handler to convert a Grafana dashboard JSON, passed as body, into a MonitoringDashboard resource
+func ConvertGrafanaDashboard(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Cannot read Grafana dashboard: "+err.Error())
+		return
+	}
+	queryParams := r.URL.Query()
+	opts := models.GrafanaConversionOptions{
+		Name:    queryParams.Get("name"),
+		Runtime: queryParams.Get("runtime"),
+	}
+	conversion, err := business.ConvertGrafanaDashboard(body, opts)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, conversion)
+}
+
 // getGrafanaInfo returns the Grafana URL and other info, the HTTP status code (int) and eventually an error
 func getGrafanaInfo(requestToken string, dashboardSupplier dashboardSupplier) (*models.GrafanaInfo, int, error) {
 	grafanaConfig := config.Get().ExternalServices.Grafana
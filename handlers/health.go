@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -122,6 +123,25 @@ func ServiceHealth(w http.ResponseWriter, r *http.Request) {
 	handleHealthResponse(w, health, err)
 }
 
+// NamespaceHealthEvents is the API handler to get the health changes detected in the given namespace
+func NamespaceHealthEvents(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	p := healthEventsParams{}
+	if ok, err := p.extract(r); !ok {
+		// Bad request
+		RespondWithError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	events, err := business.Health.GetHealthEvents(p.Namespace, p.Since)
+	handleHealthResponse(w, events, err)
+}
+
 func handleHealthResponse(w http.ResponseWriter, health interface{}, err error) {
 	if err != nil {
 		handleErrorResponse(w, err)
@@ -236,6 +256,33 @@ func (p *workloadHealthParams) extract(r *http.Request) {
 	p.WorkloadType = query.Get("type")
 }
 
+// healthEventsParams holds the path and query parameters for NamespaceHealthEvents
+//
+// swagger:parameters namespaceHealthEvents
+type healthEventsParams struct {
+	// The namespace scope
+	//
+	// in: path
+	Namespace string `json:"namespace"`
+	// Only the events with a greater id are returned
+	//
+	// in: query
+	// default: 0
+	Since int64 `json:"since"`
+}
+
+func (p *healthEventsParams) extract(r *http.Request) (bool, string) {
+	p.Namespace = mux.Vars(r)["namespace"]
+	if since := r.URL.Query().Get("since"); since != "" {
+		id, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			return false, "Bad request, query parameter 'since' must be an event id"
+		}
+		p.Since = id
+	}
+	return true, ""
+}
+
 func adjustRateInterval(business *business.Layer, namespace, rateInterval string, queryTime time.Time) (string, error) {
 	namespaceInfo, err := business.Namespace.GetNamespace(namespace)
 	if err != nil {
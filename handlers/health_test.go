@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	core_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kiali/kiali/business"
@@ -38,6 +39,7 @@ func TestNamespaceAppHealth(t *testing.T) {
 
 	// Test 17s on rate interval to check that rate interval is adjusted correctly.
 	prom.On("GetAllRequestRates", "ns", "17s", util.Clock.Now()).Return(model.Vector{}, nil)
+	prom.MockContainerRestarts("ns", model.Vector{})
 
 	resp, err := http.Get(url)
 	if err != nil {
@@ -90,6 +92,7 @@ func TestAppHealth(t *testing.T) {
 
 	// Test 17s on rate interval to check that rate interval is adjusted correctly.
 	prom.On("GetAppRequestRates", mock.AnythingOfType("string"), mock.AnythingOfType("string"), "17s", util.Clock.Now()).Return(model.Vector{}, model.Vector{}, nil)
+	prom.MockContainerRestarts("ns", model.Vector{})
 
 	resp, err := http.Get(url)
 	if err != nil {
@@ -130,10 +133,11 @@ func setupAppHealthEndpoint(t *testing.T) (*httptest.Server, *kubetest.K8SClient
 func TestServiceHealth(t *testing.T) {
 	conf := config.NewConfig()
 	config.Set(conf)
-	ts, _, prom := setupServiceHealthEndpoint(t)
+	ts, k8s, prom := setupServiceHealthEndpoint(t)
 	defer ts.Close()
 
 	url := ts.URL + "/api/namespaces/ns/services/svc/health"
+	k8s.On("GetService", "ns", "svc").Return(&core_v1.Service{}, nil)
 
 	// Test 17s on rate interval to check that rate interval is adjusted correctly.
 	prom.On("GetServiceRequestRates", mock.AnythingOfType("string"), mock.AnythingOfType("string"), "17s", util.Clock.Now()).Return(model.Vector{}, nil)
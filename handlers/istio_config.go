@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"strings"
 	"sync"
 
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
@@ -241,6 +243,108 @@ func IstioConfigCreate(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, createdConfigDetails)
 }
 
+// IstioConfigPreview returns the validations and traffic changes that creating (when no object is specified)
+// or patching an Istio object would cause, without applying it.
+func IstioConfigPreview(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	if !checkObjectType(objectType) {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Preview request could not be read: "+err.Error())
+		return
+	}
+
+	preview, err := business.IstioConfig.PreviewIstioConfigDetail(namespace, objectType, object, body)
+	if err != nil {
+		if errors.IsBadRequest(err) {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, preview)
+}
+
+// IstioConfigFix applies the fix proposed for a check of an Istio object
+func IstioConfigFix(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	if !checkObjectType(objectType) {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	var fixRequest models.IstioCheckFixRequest
+	if err := json.NewDecoder(r.Body).Decode(&fixRequest); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Fix request could not be read: "+err.Error())
+		return
+	}
+
+	fix, details, err := business.IstioConfig.ApplyValidationFix(namespace, objectType, object, fixRequest.Code, fixRequest.Path)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	if fix.Patch != "" {
+		audit(r, "FIX on Namespace: "+namespace+" Type: "+objectType+" Name: "+object+" Check: "+fixRequest.Code+" "+fixRequest.Path+
+			" UPDATE on Namespace: "+fix.Target.Namespace+" Type: "+fix.Target.ObjectType+" Name: "+fix.Target.Name+" Patch: "+fix.Patch)
+	} else {
+		audit(r, "FIX on Namespace: "+namespace+" Type: "+objectType+" Name: "+object+" Check: "+fixRequest.Code+" "+fixRequest.Path+
+			" CREATE on Namespace: "+fix.Target.Namespace+" Type: "+fix.Target.ObjectType+" Object: "+fix.Object)
+	}
+	RespondWithJSON(w, http.StatusOK, details)
+}
+
+// IstioConfigSidecarsGenerate proposes minimal Sidecars for the workloads of a namespace based on their observed traffic
+func IstioConfigSidecarsGenerate(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+	namespace := params["namespace"]
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	sidecars, err := business.IstioConfig.GenerateSidecars(namespace, query.Get("workload"), query.Get("rateInterval"))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, sidecars)
+}
+
 func checkObjectType(objectType string) bool {
 	return business.GetIstioAPI(objectType) != ""
 }
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
+)
+
+// IstioConfigDependencies is the API handler to fetch the references between the Istio objects of a namespace
+// and the objects they depend on. When an object is given, only the objects depending on it are returned.
+func IstioConfigDependencies(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+	namespace := params["namespace"]
+
+	objectType := query.Get("objectType")
+	object := query.Get("object")
+	if (objectType == "") != (object == "") {
+		RespondWithError(w, http.StatusBadRequest, "objectType and object must be set together")
+		return
+	}
+	if singular, found := models.ObjectTypeSingular[objectType]; found {
+		objectType = singular
+	}
+	objectNamespace := query.Get("objectNamespace")
+	if objectNamespace == "" {
+		objectNamespace = namespace
+	}
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	graph, err := business.Validations.GetIstioDependencyGraph(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	if object != "" {
+		key := models.BuildKey(objectType, object, objectNamespace)
+		if !graph.HasNode(key) {
+			RespondWithError(w, http.StatusNotFound, "Object not found: "+objectNamespace+"/"+objectType+"/"+object)
+			return
+		}
+		graph = graph.Dependents(key)
+	}
+
+	RespondWithJSON(w, http.StatusOK, graph)
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	pmod "github.com/prometheus/common/model"
 
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/log"
@@ -48,7 +49,7 @@ func getAppMetrics(w http.ResponseWriter, r *http.Request, promSupplier promClie
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 // WorkloadMetrics is the API handler to fetch metrics to be displayed, related to a single workload
@@ -80,7 +81,7 @@ func getWorkloadMetrics(w http.ResponseWriter, r *http.Request, promSupplier pro
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 // ServiceMetrics is the API handler to fetch metrics to be displayed, related to a single service
@@ -112,7 +113,7 @@ func getServiceMetrics(w http.ResponseWriter, r *http.Request, promSupplier prom
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 // AggregateMetrics is the API handler to fetch metrics to be displayed, related to a single aggregate
@@ -153,7 +154,7 @@ func getAggregateMetrics(w http.ResponseWriter, r *http.Request, promSupplier pr
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 // NamespaceMetrics is the API handler to fetch metrics to be displayed, related to all
@@ -185,11 +186,14 @@ func getNamespaceMetrics(w http.ResponseWriter, r *http.Request, promSupplier pr
 		RespondWithError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
-	RespondWithJSON(w, http.StatusOK, metrics)
+	respondWithMetrics(w, r, metrics)
 }
 
 func extractIstioMetricsQueryParams(r *http.Request, q *models.IstioMetricsQuery, namespaceInfo *models.Namespace) error {
 	q.FillDefaults()
+	if _, err := metricsFormat(r); err != nil {
+		return err
+	}
 	queryParams := r.URL.Query()
 	if filters, ok := queryParams["filters[]"]; ok && len(filters) > 0 {
 		q.Filters = filters
@@ -334,6 +338,52 @@ func MetricsStats(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, result)
 }
 
+// MetricsComparison is the API handler to compare the metrics of two targets, such as a canary and its stable version
+func MetricsComparison(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var q models.MetricsComparisonQuery
+	err = json.Unmarshal(body, &q)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q.FillDefaults()
+	if errs := q.Validate(); errs != nil {
+		RespondWithError(w, http.StatusBadRequest, errs.Error())
+		return
+	}
+
+	// Check the namespaces and adjust the window to their creation
+	metricsService, queries, errs := prepareStatsQueries(w, r, q.StatsQueries(), defaultPromClientSupplier)
+	if metricsService == nil {
+		// any returned value nil means error & response already written
+		return
+	}
+	if errs != nil {
+		handleErrorResponse(w, errs)
+		return
+	}
+	// Both targets are compared on the shortest adjusted window
+	var shortest pmod.Duration
+	for _, sq := range queries {
+		if d, err := pmod.ParseDuration(sq.Interval); err == nil && (shortest == 0 || d < shortest) {
+			shortest = d
+			q.Interval = sq.Interval
+		}
+	}
+
+	comparison, err := metricsService.CompareMetrics(q)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, comparison)
+}
+
 func prepareStatsQueries(w http.ResponseWriter, r *http.Request, rawQ []models.MetricsStatsQuery, promSupplier promClientSupplier) (*business.MetricsService, []models.MetricsStatsQuery, *util.Errors) {
 	// Get unique namespaces list
 	var namespaces []string
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	pmod "github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// Metrics export formats
+const (
+	metricsFormatJSON        = "json"
+	metricsFormatCSV         = "csv"
+	metricsFormatOpenMetrics = "openmetrics"
+
+	openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+var (
+	invalidOpenMetricsNameChars  = regexp.MustCompile(`[^a-zA-Z0-9_:]`)
+	invalidOpenMetricsLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	openMetricsLabelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// metricsFormat returns the format the metrics are rendered with: the format query param when set,
+// else the one negotiated with the Accept header, json by default
+func metricsFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case metricsFormatJSON, metricsFormatCSV, metricsFormatOpenMetrics:
+		return format, nil
+	case "":
+	default:
+		return "", errors.New("bad request, query parameter 'format' must be either 'json', 'csv' or 'openmetrics'")
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "application/openmetrics-text"):
+		return metricsFormatOpenMetrics, nil
+	case strings.Contains(accept, "text/csv"):
+		return metricsFormatCSV, nil
+	}
+	return metricsFormatJSON, nil
+}
+
+// respondWithMetrics renders the metrics with the requested format
+func respondWithMetrics(w http.ResponseWriter, r *http.Request, metrics models.MetricsMap) {
+	format, _ := metricsFormat(r)
+	if format == metricsFormatJSON {
+		RespondWithJSON(w, http.StatusOK, metrics)
+		return
+	}
+
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	series := []models.Metric{}
+	for _, name := range names {
+		series = append(series, metrics[name]...)
+	}
+	respondWithMetricsSeries(w, format, series)
+}
+
+// respondWithDashboard renders the dashboard with the requested format. Other formats than JSON only
+// render the metrics of the charts.
+func respondWithDashboard(w http.ResponseWriter, r *http.Request, dashboard *models.MonitoringDashboard) {
+	format, _ := metricsFormat(r)
+	if format == metricsFormatJSON {
+		RespondWithJSON(w, http.StatusOK, dashboard)
+		return
+	}
+
+	series := []models.Metric{}
+	for _, chart := range dashboard.Charts {
+		series = append(series, chart.Metrics...)
+	}
+	respondWithMetricsSeries(w, format, series)
+}
+
+func respondWithMetricsSeries(w http.ResponseWriter, format string, series []models.Metric) {
+	var err error
+	if format == metricsFormatCSV {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=\"metrics.csv\"")
+		w.WriteHeader(http.StatusOK)
+		err = writeMetricsCSV(w, series)
+	} else {
+		w.Header().Set("Content-Type", openMetricsContentType)
+		w.WriteHeader(http.StatusOK)
+		err = writeOpenMetrics(w, series)
+	}
+	if err != nil {
+		log.Errorf("Error rendering the metrics as %s: %s", format, err)
+	}
+}
+
+// writeMetricsCSV writes one row per datapoint, with a column per label found on the series.
+// Timestamps are in seconds.
+func writeMetricsCSV(w io.Writer, series []models.Metric) error {
+	labelsSet := map[string]bool{}
+	for _, s := range series {
+		for label := range s.Labels {
+			labelsSet[label] = true
+		}
+	}
+	labels := make([]string, 0, len(labelsSet))
+	for label := range labelsSet {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write(append(append([]string{"name", "stat"}, labels...), "timestamp", "value"))
+	for _, s := range series {
+		row := make([]string, 0, len(labels)+4)
+		row = append(row, s.Name, s.Stat)
+		for _, label := range labels {
+			row = append(row, s.Labels[label])
+		}
+		for _, dp := range s.Datapoints {
+			_ = writer.Write(append(row, pmod.Time(dp.Timestamp).String(), strconv.FormatFloat(dp.Value, 'f', -1, 64)))
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeOpenMetrics writes the series as gauges of the OpenMetrics text format, the stat being a label.
+// Datapoints are timestamped samples, as expected by the Prometheus backfilling.
+func writeOpenMetrics(w io.Writer, series []models.Metric) error {
+	// Samples of a metric family must not be interleaved with other families
+	families := map[string][]models.Metric{}
+	names := []string{}
+	for _, s := range series {
+		name := openMetricsName(invalidOpenMetricsNameChars, s.Name)
+		if _, ok := families[name]; !ok {
+			names = append(names, name)
+		}
+		families[name] = append(families[name], s)
+	}
+
+	buf := bufio.NewWriter(w)
+	for _, name := range names {
+		fmt.Fprintf(buf, "# TYPE %s gauge\n", name)
+		for _, s := range families[name] {
+			labels := make([]string, 0, len(s.Labels)+1)
+			for label, value := range s.Labels {
+				labels = append(labels, fmt.Sprintf(`%s="%s"`, openMetricsName(invalidOpenMetricsLabelChars, label), openMetricsLabelValueEscaper.Replace(value)))
+			}
+			if s.Stat != "" {
+				labels = append(labels, fmt.Sprintf(`stat="%s"`, openMetricsLabelValueEscaper.Replace(s.Stat)))
+			}
+			sort.Strings(labels)
+			for _, dp := range s.Datapoints {
+				fmt.Fprintf(buf, "%s{%s} %s %s\n", name, strings.Join(labels, ","), strconv.FormatFloat(dp.Value, 'g', -1, 64), pmod.Time(dp.Timestamp).String())
+			}
+		}
+	}
+	fmt.Fprint(buf, "# EOF\n")
+	return buf.Flush()
+}
+
+// openMetricsName replaces the characters not allowed in a metric or label name
+func openMetricsName(invalidChars *regexp.Regexp, name string) string {
+	name = invalidChars.ReplaceAllString(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
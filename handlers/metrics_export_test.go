@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestMetricsFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	r := httptest.NewRequest("GET", "/api/namespaces/ns/services/svc/metrics", nil)
+	format, err := metricsFormat(r)
+	assert.NoError(err)
+	assert.Equal("json", format)
+
+	r.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	format, _ = metricsFormat(r)
+	assert.Equal("openmetrics", format)
+
+	// The query param wins over the Accept header
+	r = httptest.NewRequest("GET", "/api/namespaces/ns/services/svc/metrics?format=csv", nil)
+	r.Header.Set("Accept", "application/openmetrics-text")
+	format, _ = metricsFormat(r)
+	assert.Equal("csv", format)
+
+	r = httptest.NewRequest("GET", "/api/namespaces/ns/services/svc/metrics?format=xml", nil)
+	_, err = metricsFormat(r)
+	assert.Error(err)
+}
+
+func exportedSeries() []models.Metric {
+	return []models.Metric{
+		{
+			Name:       "request_count",
+			Labels:     map[string]string{"response_code": "200"},
+			Datapoints: []models.Datapoint{{Timestamp: 1600000000000, Value: 1.5}, {Timestamp: 1600000015000, Value: 2}},
+		},
+		{
+			Name:       "request_duration_millis",
+			Stat:       "0.99",
+			Labels:     map[string]string{"destination_workload": `reviews-"v1"`},
+			Datapoints: []models.Datapoint{{Timestamp: 1600000000500, Value: 120}},
+		},
+	}
+}
+
+func TestWriteMetricsCSV(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := bytes.Buffer{}
+	assert.NoError(writeMetricsCSV(&buf, exportedSeries()))
+	assert.Equal(`name,stat,destination_workload,response_code,timestamp,value
+request_count,,,200,1600000000,1.5
+request_count,,,200,1600000015,2
+request_duration_millis,0.99,"reviews-""v1""",,1600000000.5,120
+`, buf.String())
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	assert := assert.New(t)
+
+	buf := bytes.Buffer{}
+	assert.NoError(writeOpenMetrics(&buf, exportedSeries()))
+	assert.Equal(`# TYPE request_count gauge
+request_count{response_code="200"} 1.5 1600000000
+request_count{response_code="200"} 2 1600000015
+# TYPE request_duration_millis gauge
+request_duration_millis{destination_workload="reviews-\"v1\"",stat="0.99"} 120 1600000000.5
+# EOF
+`, buf.String())
+}
@@ -55,6 +55,27 @@ func NamespaceValidationSummary(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, validationSummary)
 }
 
+// NamespaceValidationsHistory returns the validations recorded over time for all objects in the given namespace
+func NamespaceValidationsHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace := vars["namespace"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	history, err := business.Validations.GetValidationsHistory(namespace)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, history)
+}
+
 // NamespaceUpdate is the API to perform a patch on a Namespace configuration
 func NamespaceUpdate(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/util"
+)
+
+// ServiceSLOs is the API handler to evaluate the service level objectives of a single service
+func ServiceSLOs(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	p := sloParams{}
+	p.extract(r)
+	slos, err := business.SLO.GetServiceSLOs(p.Namespace, mux.Vars(r)["service"], p.QueryTime)
+	handleHealthResponse(w, slos, err)
+}
+
+// AppSLOs is the API handler to evaluate the service level objectives of a single app
+func AppSLOs(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	p := sloParams{}
+	p.extract(r)
+	slos, err := business.SLO.GetAppSLOs(p.Namespace, mux.Vars(r)["app"], p.QueryTime)
+	handleHealthResponse(w, slos, err)
+}
+
+// WorkloadSLOs is the API handler to evaluate the service level objectives of a single workload
+func WorkloadSLOs(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	p := sloParams{}
+	p.extract(r)
+	slos, err := business.SLO.GetWorkloadSLOs(p.Namespace, mux.Vars(r)["workload"], p.QueryTime)
+	handleHealthResponse(w, slos, err)
+}
+
+// sloParams holds the parameters shared by the SLO endpoints
+type sloParams struct {
+	Namespace string
+	// The time to use for the prometheus query
+	QueryTime time.Time
+}
+
+func (p *sloParams) extract(r *http.Request) {
+	p.Namespace = mux.Vars(r)["namespace"]
+	p.QueryTime = util.Clock.Now()
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+var validationsReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Mesh validations report</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.error { color: #c9190b; }
+.warning { color: #795600; }
+</style>
+</head>
+<body>
+<h1>Mesh validations report</h1>
+<p>Generated at {{.Timestamp.Format "2006-01-02 15:04:05 MST"}}</p>
+<h2>Namespaces</h2>
+<table>
+<tr><th>Namespace</th><th>Objects</th><th>Errors</th><th>Warnings</th><th>Validation error</th></tr>
+{{range .Namespaces}}<tr><td>{{.Namespace}}</td><td>{{.ObjectCount}}</td><td>{{.Errors}}</td><td>{{.Warnings}}</td><td>{{.Error}}</td></tr>
+{{end}}</table>
+<h2>Checks</h2>
+<table>
+<tr><th>Code</th><th>Severity</th><th>Message</th><th>Count</th><th>Objects</th></tr>
+{{range .Checks}}<tr><td>{{.Code}}</td><td class="{{.Severity}}">{{.Severity}}</td><td>{{.Message}}</td><td>{{.Count}}</td><td>{{.Objects}}</td></tr>
+{{end}}</table>
+<h2>Top offending objects</h2>
+<table>
+<tr><th>Namespace</th><th>Type</th><th>Name</th><th>Owner</th><th>Errors</th><th>Warnings</th><th>Checks</th></tr>
+{{range .TopObjects}}<tr><td>{{.Namespace}}</td><td>{{.ObjectType}}</td><td>{{.Name}}</td><td>{{.Owner}}</td><td>{{.Errors}}</td><td>{{.Warnings}}</td><td>{{range .Checks}}<div class="{{.Severity}}">{{.Message}} ({{.Path}}{{if .Detail}}: {{.Detail}}{{end}})</div>{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// MeshValidationsReport is the API handler to fetch the validations summary of all the namespaces of the mesh.
+// The report is rendered as JSON by default, or as CSV or HTML with the format query param.
+func MeshValidationsReport(w http.ResponseWriter, r *http.Request) {
+	queryParams := r.URL.Query()
+	format := queryParams.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" && format != "html" {
+		RespondWithError(w, http.StatusBadRequest, "Invalid format: "+format)
+		return
+	}
+
+	top := 0
+	if topParam := queryParams.Get("top"); topParam != "" {
+		var err error
+		if top, err = strconv.Atoi(topParam); err != nil || top < 0 {
+			RespondWithError(w, http.StatusBadRequest, "Invalid top: "+topParam)
+			return
+		}
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		log.Error(err)
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	report, err := business.Validations.GetMeshValidationsReport(top)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	switch format {
+	case "csv":
+		respondWithValidationsReportCSV(w, report)
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if err := validationsReportTemplate.Execute(w, report); err != nil {
+			log.Errorf("Error rendering the validations report: %s", err)
+		}
+	default:
+		RespondWithJSON(w, http.StatusOK, report)
+	}
+}
+
+// respondWithValidationsReportCSV writes the sections of the report in a single CSV, told apart by the section column:
+// one row per namespace, one row per check type, then one row per check found on the top offending objects
+func respondWithValidationsReportCSV(w http.ResponseWriter, report models.MeshValidationsReport) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"validations-%s.csv\"", report.Timestamp.Format("20060102-150405")))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"section", "namespace", "objectType", "name", "owner", "code", "severity", "message", "path", "detail", "objects", "count", "errors", "warnings", "error"})
+	for _, ns := range report.Namespaces {
+		_ = writer.Write([]string{"namespace", ns.Namespace, "", "", "", "", "", "", "", "",
+			strconv.Itoa(ns.ObjectCount), "", strconv.Itoa(ns.Errors), strconv.Itoa(ns.Warnings), ns.Error})
+	}
+	for _, check := range report.Checks {
+		_ = writer.Write([]string{"check", "", "", "", "", check.Code, string(check.Severity), check.Message, "", "",
+			strconv.Itoa(check.Objects), strconv.Itoa(check.Count), "", "", ""})
+	}
+	for _, object := range report.TopObjects {
+		for _, check := range object.Checks {
+			_ = writer.Write([]string{"object", object.Namespace, object.ObjectType, object.Name, object.Owner, check.Code, string(check.Severity), check.Message, check.Path, check.Detail,
+				"", "", "", "", ""})
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Errorf("Error rendering the validations report: %s", err)
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestMeshValidationsReportInvalidParams(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, query := range []string{"format=xml", "top=-1", "top=ten"} {
+		req := httptest.NewRequest("GET", "/api/mesh/validations?"+query, nil)
+		w := httptest.NewRecorder()
+		MeshValidationsReport(w, req)
+		assert.Equal(http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestValidationsReportRendering(t *testing.T) {
+	assert := assert.New(t)
+
+	check := models.Build("authorizationpolicy.source.namespacenotfound", "spec/rules[0]/from[0]")
+	check.Detail = "<ns1>"
+	report := models.MeshValidationsReport{
+		Timestamp:  time.Date(2020, 5, 1, 10, 0, 0, 0, time.UTC),
+		Namespaces: []models.NamespaceValidationsReport{{Namespace: "bookinfo", IstioValidationSummary: models.IstioValidationSummary{ObjectCount: 1, Warnings: 1}}},
+		Checks:     []models.CheckTypeReport{{Code: "KIA0101", Message: check.Message, Severity: check.Severity, Count: 1, Objects: 1}},
+		TopObjects: []models.ObjectValidationsReport{{
+			IstioValidationKey: models.BuildKey("authorizationpolicy", "<ap1>", "bookinfo"),
+			Owner:              "reviews-team",
+			Warnings:           1,
+			Checks:             []*models.IstioCheck{&check},
+		}},
+	}
+
+	w := httptest.NewRecorder()
+	respondWithValidationsReportCSV(w, report)
+	assert.Equal("text/csv", w.Header().Get("Content-Type"))
+	assert.Equal("attachment; filename=\"validations-20200501-100000.csv\"", w.Header().Get("Content-Disposition"))
+	assert.Equal("section,namespace,objectType,name,owner,code,severity,message,path,detail,objects,count,errors,warnings,error\n"+
+		"namespace,bookinfo,,,,,,,,,1,,0,1,\n"+
+		"check,,,,,KIA0101,warning,KIA0101 Namespace not found for this rule,,,1,1,,,\n"+
+		"object,bookinfo,authorizationpolicy,<ap1>,reviews-team,KIA0101,warning,KIA0101 Namespace not found for this rule,spec/rules[0]/from[0],<ns1>,,,,,\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	assert.NoError(validationsReportTemplate.Execute(w, report))
+	assert.Contains(w.Body.String(), "<td>reviews-team</td>")
+	assert.Contains(w.Body.String(), "&lt;ap1&gt;")
+	assert.Contains(w.Body.String(), "(spec/rules[0]/from[0]: &lt;ns1&gt;)")
+}
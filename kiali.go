@@ -18,6 +18,7 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"os/signal"
@@ -25,8 +26,12 @@ import (
 	"regexp"
 	"strings"
 
+	"sigs.k8s.io/yaml"
+
+	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
 	"github.com/kiali/kiali/server"
 	"github.com/kiali/kiali/status"
@@ -42,6 +47,10 @@ var (
 // Command line arguments
 var (
 	argConfigFile = flag.String("config", "", "Path to the YAML configuration file. If not specified, environment variables will be used for configuration.")
+
+	argConvertGrafanaDashboard = flag.String("convert-grafana-dashboard", "", "Path to a Grafana dashboard JSON file (or - for stdin) to convert into a MonitoringDashboard resource. The YAML resource is written to stdout, the unconverted panels are reported on stderr, then Kiali exits.")
+	argDashboardName           = flag.String("dashboard-name", "", "Name of the MonitoringDashboard resource converted from Grafana. Defaults to the Grafana dashboard title.")
+	argDashboardRuntime        = flag.String("dashboard-runtime", "", "Runtime of the MonitoringDashboard resource converted from Grafana. Defaults to the Grafana dashboard title.")
 )
 
 func init() {
@@ -59,6 +68,13 @@ func main() {
 	flag.Parse()
 	validateFlags()
 
+	if *argConvertGrafanaDashboard != "" {
+		if err := convertGrafanaDashboard(*argConvertGrafanaDashboard, os.Stdout, os.Stderr); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// log startup information
 	log.Infof("Kiali: Version: %v, Commit: %v\n", version, commitHash)
 	log.Debugf("Kiali: Command line: [%v]", strings.Join(os.Args, " "))
@@ -158,6 +174,12 @@ func validateConfig() error {
 		return fmt.Errorf("Invalid authentication strategy [%v]", auth.Strategy)
 	}
 
+	for _, slo := range config.Get().HealthConfig.SLO {
+		if err := slo.Validate(); err != nil {
+			return fmt.Errorf("invalid health SLO [%s]: %v", slo.Id, err)
+		}
+	}
+
 	// Check the signing key for the JWT token is valid
 	signingKey := config.Get().LoginToken.SigningKey
 	if err := config.ValidateSigningKey(signingKey, auth.Strategy); err != nil {
@@ -177,6 +199,42 @@ func validateFlags() {
 	}
 }
 
+// convertGrafanaDashboard converts a Grafana dashboard JSON file into a MonitoringDashboard YAML resource
+func convertGrafanaDashboard(path string, out, report io.Writer) error {
+	var raw []byte
+	var err error
+	if path == "-" {
+		raw, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		raw, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	conversion, err := business.ConvertGrafanaDashboard(raw, models.GrafanaConversionOptions{
+		Name:    *argDashboardName,
+		Runtime: *argDashboardRuntime,
+	})
+	if err != nil {
+		return err
+	}
+	resource, err := yaml.Marshal(conversion.Dashboard)
+	if err != nil {
+		return err
+	}
+	if _, err = out.Write(resource); err != nil {
+		return err
+	}
+	for _, skipped := range conversion.Skipped {
+		if skipped.Expression != "" {
+			fmt.Fprintf(report, "Skipped %s of %q [%s]: %s\n", skipped.Kind, skipped.Title, skipped.Expression, skipped.Reason)
+		} else {
+			fmt.Fprintf(report, "Skipped %s %q: %s\n", skipped.Kind, skipped.Title, skipped.Reason)
+		}
+	}
+	return nil
+}
+
 // determineConsoleVersion will return the version of the UI console the server will serve to clients.
 // Note this method requires the configuration to be loaded and available via config.Get()
 func determineConsoleVersion() string {
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"bytes"
+	"io/ioutil"
 	"math/rand"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -88,3 +92,33 @@ func TestValidateAuthStrategy(t *testing.T) {
 		}
 	}
 }
+
+func TestConvertGrafanaDashboard(t *testing.T) {
+	file, err := ioutil.TempFile("", "grafana-*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	_, _ = file.WriteString(`{"title": "My Runtime", "panels": [
+		{"title": "Threads", "type": "graph", "targets": [{"expr": "sum(jvm_threads)"}]},
+		{"title": "Notes", "type": "text"}
+	]}`)
+	file.Close()
+
+	var out, report bytes.Buffer
+	if err := convertGrafanaDashboard(file.Name(), &out, &report); err != nil {
+		t.Fatalf("Conversion should have succeeded: %v", err)
+	}
+	for _, expected := range []string{"kind: MonitoringDashboard", "name: my-runtime", "metricName: jvm_threads"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Converted resource should contain [%v]: %v", expected, out.String())
+		}
+	}
+	if report.String() != "Skipped panel \"Notes\": panels of type \"text\" are not supported\n" {
+		t.Errorf("Unexpected conversion report: %v", report.String())
+	}
+
+	if err := convertGrafanaDashboard(file.Name()+".missing", &out, &report); err == nil {
+		t.Errorf("Conversion of a missing file should have failed")
+	}
+}
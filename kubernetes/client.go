@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"strings"
+	"sync"
 
 	osapps_v1 "github.com/openshift/api/apps/v1"
 	osproject_v1 "github.com/openshift/api/project/v1"
@@ -46,9 +47,12 @@ type IstioClientInterface interface {
 	GetIstioObjects(namespace, resourceType, labelSelector string) ([]IstioObject, error)
 	UpdateIstioObject(api, namespace, resourceType, name, jsonPatch string) (IstioObject, error)
 	GetProxyStatus() ([]*ProxyStatus, error)
+	GetConfigDump(namespace, podName string) (*ConfigDump, error)
+	HasLegacyResources() bool
 }
 
 type K8SClientInterface interface {
+	CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error)
 	GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error)
 	GetCronJobs(namespace string) ([]batch_v1beta1.CronJob, error)
 	GetDeployment(namespace string, deploymentName string) (*apps_v1.Deployment, error)
@@ -65,11 +69,14 @@ type K8SClientInterface interface {
 	GetPods(namespace, labelSelector string) ([]core_v1.Pod, error)
 	GetReplicationControllers(namespace string) ([]core_v1.ReplicationController, error)
 	GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet, error)
+	GetSecret(namespace, name string) (*core_v1.Secret, error)
 	GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error)
 	GetService(namespace string, serviceName string) (*core_v1.Service, error)
 	GetServices(namespace string, selectorLabels map[string]string) ([]core_v1.Service, error)
+	GetServiceAccounts(namespace string) ([]core_v1.ServiceAccount, error)
 	GetStatefulSet(namespace string, statefulsetName string) (*apps_v1.StatefulSet, error)
 	GetStatefulSets(namespace string) ([]apps_v1.StatefulSet, error)
+	UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error)
 	UpdateNamespace(namespace string, jsonPatch string) (*core_v1.Namespace, error)
 	UpdateWorkload(namespace string, workloadName string, workloadType string, jsonPatch string) error
 }
@@ -100,7 +107,10 @@ type K8SClient struct {
 	k8s                *kube.Clientset
 	istioNetworkingApi *rest.RESTClient
 	istioSecurityApi   *rest.RESTClient
-	iter8Api           *rest.RESTClient
+	// Legacy Istio APIs, only used to read the leftovers of previous Istio versions
+	istioAuthenticationApi *rest.RESTClient
+	istioRbacApi           *rest.RESTClient
+	iter8Api               *rest.RESTClient
 	// isOpenShift private variable will check if kiali is deployed under an OpenShift cluster or not
 	// It is represented as a pointer to include the initialization phase.
 	// See kubernetes_service.go#IsOpenShift() for more details.
@@ -120,6 +130,16 @@ type K8SClient struct {
 	// It is represented as a pointer to include the initialization phase.
 	// See istio_details_service.go#hasSecurityResource() for more details.
 	securityResources *map[string]bool
+
+	// legacyResources private variable will check which resources kiali has access to from the legacy Istio groups,
+	// indexed by API group. It is discovered once, as it is read by concurrent validations, and it is shared by the
+	// copies of the client. See istio.go#hasLegacyResource() for more details.
+	legacyResources *legacyResourcesDiscovery
+}
+
+type legacyResourcesDiscovery struct {
+	once      sync.Once
+	resources map[string]map[string]bool
 }
 
 // GetK8sApi returns the clientset referencing all K8s rest clients
@@ -215,7 +235,8 @@ func ConfigClient() (*rest.Config, error) {
 // It returns an error on any problem.
 func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 	client := K8SClient{
-		token: config.BearerToken,
+		token:           config.BearerToken,
+		legacyResources: &legacyResourcesDiscovery{},
 	}
 	log.Debugf("Rest perf config QPS: %f Burst: %d", config.QPS, config.Burst)
 
@@ -239,6 +260,14 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 				scheme.AddKnownTypeWithName(SecurityGroupVersion.WithKind(rt.objectKind), &GenericIstioObject{})
 				scheme.AddKnownTypeWithName(SecurityGroupVersion.WithKind(rt.collectionKind), &GenericIstioObjectList{})
 			}
+			for _, rt := range authenticationTypes {
+				scheme.AddKnownTypeWithName(AuthenticationGroupVersion.WithKind(rt.objectKind), &GenericIstioObject{})
+				scheme.AddKnownTypeWithName(AuthenticationGroupVersion.WithKind(rt.collectionKind), &GenericIstioObjectList{})
+			}
+			for _, rt := range rbacTypes {
+				scheme.AddKnownTypeWithName(RbacGroupVersion.WithKind(rt.objectKind), &GenericIstioObject{})
+				scheme.AddKnownTypeWithName(RbacGroupVersion.WithKind(rt.collectionKind), &GenericIstioObjectList{})
+			}
 			// Register Extension (iter8) types
 			for _, rt := range iter8Types {
 				// We will use a Iter8ExperimentObject which only contains metadata and spec with interfaces
@@ -249,6 +278,8 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 
 			meta_v1.AddToGroupVersion(scheme, NetworkingGroupVersion)
 			meta_v1.AddToGroupVersion(scheme, SecurityGroupVersion)
+			meta_v1.AddToGroupVersion(scheme, AuthenticationGroupVersion)
+			meta_v1.AddToGroupVersion(scheme, RbacGroupVersion)
 			meta_v1.AddToGroupVersion(scheme, Iter8GroupVersion)
 			return nil
 		})
@@ -268,6 +299,16 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 		return nil, err
 	}
 
+	istioAuthenticationApi, err := newClientForAPI(config, AuthenticationGroupVersion, types)
+	if err != nil {
+		return nil, err
+	}
+
+	istioRbacApi, err := newClientForAPI(config, RbacGroupVersion, types)
+	if err != nil {
+		return nil, err
+	}
+
 	iter8Api, err := newClientForAPI(config, Iter8GroupVersion, types)
 	if err != nil {
 		return nil, err
@@ -275,6 +316,8 @@ func NewClientFromConfig(config *rest.Config) (*K8SClient, error) {
 
 	client.istioNetworkingApi = istioNetworkingAPI
 	client.istioSecurityApi = istioSecurityApi
+	client.istioAuthenticationApi = istioAuthenticationApi
+	client.istioRbacApi = istioRbacApi
 	client.iter8Api = iter8Api
 	return &client, nil
 }
@@ -0,0 +1,158 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+)
+
+// ConfigDump is a loosely typed version of the Envoy admin config_dump.
+// Only the pieces Kiali needs to compare against Istio objects are extracted from it.
+type ConfigDump struct {
+	Configs []map[string]interface{} `json:"configs"`
+}
+
+// GetConfigDump fetches the Envoy configuration of a given proxy through the istiod debug endpoints.
+func (in *K8SClient) GetConfigDump(namespace, podName string) (*ConfigDump, error) {
+	c := config.Get()
+	istiods, err := in.GetPods(c.IstioNamespace, labels.Set(map[string]string{
+		c.IstioLabels.AppLabelName: "istiod",
+	}).String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(istiods) == 0 {
+		return nil, errors.New("unable to find any Pilot instances")
+	}
+
+	proxyID := fmt.Sprintf("%s.%s", podName, namespace)
+	var lastErr error
+	// The proxy is connected to a single istiod, the other instances will answer with an error
+	for _, istiod := range istiods {
+		res, err := in.k8s.CoreV1().RESTClient().Get().
+			Namespace(istiod.Namespace).
+			Resource("pods").
+			SubResource("proxy").
+			Name(istiod.Name).
+			Suffix("/debug/config_dump").
+			Param("proxyID", proxyID).
+			DoRaw()
+
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return ParseConfigDump(res)
+	}
+
+	return nil, lastErr
+}
+
+// ParseConfigDump unmarshals the raw config_dump output
+func ParseConfigDump(raw []byte) (*ConfigDump, error) {
+	cd := &ConfigDump{}
+	if err := json.Unmarshal(raw, cd); err != nil {
+		return nil, err
+	}
+	return cd, nil
+}
+
+// GetClusterNames returns the names of the dynamic clusters of the proxy.
+// Istio names them as <direction>|<port>|<subset>|<fqdn>
+func (cd *ConfigDump) GetClusterNames() map[string]bool {
+	names := map[string]bool{}
+	for _, c := range cd.configsOfType("ClustersConfigDump") {
+		for _, field := range []string{"static_clusters", "dynamic_active_clusters"} {
+			clusters, ok := c[field].([]interface{})
+			if !ok {
+				continue
+			}
+			for _, cl := range clusters {
+				if clMap, ok := cl.(map[string]interface{}); ok {
+					if cluster, ok := clMap["cluster"].(map[string]interface{}); ok {
+						if name, ok := cluster["name"].(string); ok {
+							names[name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+// GetIstioConfigReferences returns the Istio objects referenced in the proxy config metadata.
+// Istio annotates routes with the object that generated them, e.g.:
+// /apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtual-service/reviews
+func (cd *ConfigDump) GetIstioConfigReferences() map[string]bool {
+	refs := map[string]bool{}
+	for _, c := range cd.Configs {
+		walkConfig(c, func(key string, value interface{}) {
+			if key != "filter_metadata" {
+				return
+			}
+			if fm, ok := value.(map[string]interface{}); ok {
+				if istio, ok := fm["istio"].(map[string]interface{}); ok {
+					if ref, ok := istio["config"].(string); ok {
+						refs[ref] = true
+					}
+				}
+			}
+		})
+	}
+	return refs
+}
+
+// GetRBACPolicyNames returns the names of the RBAC policies found in the listeners' filters.
+// Istio names them as ns[<namespace>]-policy[<name>]-rule[<index>]
+func (cd *ConfigDump) GetRBACPolicyNames() map[string]bool {
+	names := map[string]bool{}
+	for _, c := range cd.configsOfType("ListenersConfigDump") {
+		walkConfig(c, func(key string, value interface{}) {
+			if key != "rules" && key != "shadow_rules" {
+				return
+			}
+			if rules, ok := value.(map[string]interface{}); ok {
+				if policies, ok := rules["policies"].(map[string]interface{}); ok {
+					for name := range policies {
+						names[name] = true
+					}
+				}
+			}
+		})
+	}
+	return names
+}
+
+func (cd *ConfigDump) configsOfType(dumpType string) []map[string]interface{} {
+	configs := make([]map[string]interface{}, 0)
+	for _, c := range cd.Configs {
+		if t, ok := c["@type"].(string); ok && strings.HasSuffix(t, "."+dumpType) {
+			configs = append(configs, c)
+		}
+	}
+	return configs
+}
+
+// walkConfig visits recursively all the key/values of a json tree
+func walkConfig(node interface{}, visit func(key string, value interface{})) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for k, v := range n {
+			visit(k, v)
+			walkConfig(v, visit)
+		}
+	case []interface{}:
+		for _, v := range n {
+			walkConfig(v, visit)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const fakeConfigDump = `{
+  "configs": [
+    {
+      "@type": "type.googleapis.com/envoy.admin.v3.ClustersConfigDump",
+      "dynamic_active_clusters": [
+        { "cluster": { "name": "outbound|9080||reviews.bookinfo.svc.cluster.local" } },
+        { "cluster": { "name": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local" } }
+      ]
+    },
+    {
+      "@type": "type.googleapis.com/envoy.admin.v3.ListenersConfigDump",
+      "dynamic_listeners": [
+        { "active_state": { "listener": { "filter_chains": [ { "filters": [ { "typed_config": { "http_filters": [
+          { "name": "envoy.filters.http.rbac", "typed_config": { "rules": { "policies": {
+            "ns[bookinfo]-policy[allow-get]-rule[0]": {}
+          } } } }
+        ] } } ] } ] } } }
+      ]
+    },
+    {
+      "@type": "type.googleapis.com/envoy.admin.v3.RoutesConfigDump",
+      "dynamic_route_configs": [
+        { "route_config": { "virtual_hosts": [ { "routes": [ { "metadata": { "filter_metadata": { "istio": {
+          "config": "/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtual-service/reviews"
+        } } } } ] } ] } }
+      ]
+    }
+  ]
+}`
+
+func TestParseConfigDump(t *testing.T) {
+	assert := assert.New(t)
+
+	cd, err := ParseConfigDump([]byte(fakeConfigDump))
+	assert.NoError(err)
+	assert.Len(cd.Configs, 3)
+
+	clusters := cd.GetClusterNames()
+	assert.Len(clusters, 2)
+	assert.True(clusters["outbound|9080|v1|reviews.bookinfo.svc.cluster.local"])
+
+	refs := cd.GetIstioConfigReferences()
+	assert.Len(refs, 1)
+	assert.True(refs["/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtual-service/reviews"])
+
+	policies := cd.GetRBACPolicyNames()
+	assert.Len(policies, 1)
+	assert.True(policies["ns[bookinfo]-policy[allow-get]-rule[0]"])
+}
+
+func TestParseConfigDumpInvalid(t *testing.T) {
+	_, err := ParseConfigDump([]byte("not json"))
+	assert.Error(t, err)
+}
@@ -9,9 +9,11 @@ import (
 
 	"gopkg.in/yaml.v2"
 	core_v1 "k8s.io/api/core/v1"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 
@@ -34,6 +36,17 @@ func (in *K8SClient) getApiClientVersion(apiGroup string) (*rest.RESTClient, str
 	return nil, ""
 }
 
+// Aux method to fetch proper (RESTClient, APIVersion) per legacy API group.
+// Legacy objects are only read, so these clients are not used by the write operations.
+func (in *K8SClient) getLegacyApiClientVersion(apiGroup string) (*rest.RESTClient, string) {
+	if apiGroup == AuthenticationGroupVersion.Group {
+		return in.istioAuthenticationApi, ApiAuthenticationVersion
+	} else if apiGroup == RbacGroupVersion.Group {
+		return in.istioRbacApi, ApiRbacVersion
+	}
+	return nil, ""
+}
+
 // CreateIstioObject creates an Istio object
 func (in *K8SClient) CreateIstioObject(api, namespace, resourceType, json string) (IstioObject, error) {
 	var result runtime.Object
@@ -112,6 +125,11 @@ func (in *K8SClient) GetIstioObjects(namespace, resourceType, labelSelector stri
 	var ok bool
 	if apiGroup, ok = ResourceTypesToAPI[resourceType]; ok {
 		apiClient, apiVersion = in.getApiClientVersion(apiGroup)
+	} else if apiGroup, ok = LegacyResourceTypesToAPI[resourceType]; ok {
+		if !in.hasLegacyResource(apiGroup, resourceType) {
+			return []IstioObject{}, nil
+		}
+		apiClient, apiVersion = in.getLegacyApiClientVersion(apiGroup)
 	} else {
 		return []IstioObject{}, fmt.Errorf("%s not found in ResourcesTypeToAPI", resourceType)
 	}
@@ -298,6 +316,49 @@ func (in *K8SClient) getSecurityResources() map[string]bool {
 	return *in.securityResources
 }
 
+func (in *K8SClient) hasLegacyResource(apiGroup, resource string) bool {
+	return in.getLegacyResources()[apiGroup][resource]
+}
+
+// HasLegacyResources returns true when any resource of the legacy Istio API groups is still served
+func (in *K8SClient) HasLegacyResources() bool {
+	for _, resources := range in.getLegacyResources() {
+		if len(resources) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getLegacyResources discovers the resources still served by the legacy Istio API groups, indexed by group.
+// Groups not installed in the cluster are left empty. The discovery runs once per client.
+func (in *K8SClient) getLegacyResources() map[string]map[string]bool {
+	in.legacyResources.once.Do(func() {
+		legacyResources := map[string]map[string]bool{}
+		for _, gv := range []schema.GroupVersion{AuthenticationGroupVersion, RbacGroupVersion} {
+			legacyResources[gv.Group] = map[string]bool{}
+			path := fmt.Sprintf("/apis/%s/%s", gv.Group, gv.Version)
+			resourceListRaw, err := in.k8s.RESTClient().Get().AbsPath(path).Do().Raw()
+			if err != nil {
+				if !errors2.IsNotFound(err) {
+					log.Warningf("Legacy resources of %s can't be discovered: %v", path, err)
+				}
+				continue
+			}
+			resourceList := meta_v1.APIResourceList{}
+			if errMarshall := json.Unmarshal(resourceListRaw, &resourceList); errMarshall != nil {
+				log.Warningf("Legacy resources of %s can't be parsed: %v", path, errMarshall)
+				continue
+			}
+			for _, resource := range resourceList.APIResources {
+				legacyResources[gv.Group][resource.Name] = true
+			}
+		}
+		in.legacyResources.resources = legacyResources
+	})
+	return in.legacyResources.resources
+}
+
 func GetIstioConfigMap(istioConfig *core_v1.ConfigMap) (*IstioMeshConfig, error) {
 	meshConfig := &IstioMeshConfig{}
 
@@ -440,6 +501,19 @@ func MatchPortNameWithValidProtocols(portName string) bool {
 	return false
 }
 
+// PortNameProtocol returns the protocol declared by the prefix of a port name, or "" when the name doesn't declare one
+func PortNameProtocol(portName string) string {
+	protocol := ""
+	for _, p := range portProtocols {
+		// The longest matching prefix is kept, i.e. http2 over http
+		if len(p) > len(protocol) && strings.HasPrefix(portName, p) &&
+			(portName == p || portNameMatcher.MatchString(portName[len(p):])) {
+			protocol = p
+		}
+	}
+	return protocol
+}
+
 // GatewayNames extracts the gateway names for easier matching
 func GatewayNames(gateways [][]IstioObject) map[string]struct{} {
 	var empty struct{}
@@ -86,6 +86,14 @@ func TestInvalidPortNameMatcher(t *testing.T) {
 	assert.False(t, MatchPortNameWithValidProtocols("name"))
 }
 
+func TestPortNameProtocol(t *testing.T) {
+	assert.Equal(t, "http", PortNameProtocol("http"))
+	assert.Equal(t, "http", PortNameProtocol("http-name"))
+	assert.Equal(t, "http2", PortNameProtocol("http2-name"))
+	assert.Equal(t, "", PortNameProtocol("httpname"))
+	assert.Equal(t, "", PortNameProtocol("name"))
+}
+
 func TestPolicyHasMtlsEnabledStructMode(t *testing.T) {
 	policy := createPeerAuthn("default", "bookinfo", map[string]interface{}{
 		"mode": map[string]interface{}{},
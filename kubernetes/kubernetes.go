@@ -21,6 +21,16 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 )
 
+// CreateConfigMap creates the given ConfigMap in the cluster
+func (in *K8SClient) CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	return in.k8s.CoreV1().ConfigMaps(namespace).Create(configMap)
+}
+
+// UpdateConfigMap replaces the given ConfigMap in the cluster
+func (in *K8SClient) UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	return in.k8s.CoreV1().ConfigMaps(namespace).Update(configMap)
+}
+
 // GetConfigMap fetches and returns the specified ConfigMap definition
 // from the cluster
 func (in *K8SClient) GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error) {
@@ -43,6 +53,26 @@ func (in *K8SClient) GetNamespace(namespace string) (*core_v1.Namespace, error)
 	return ns, nil
 }
 
+// GetSecret fetches and returns the specified Secret definition
+// from the cluster
+func (in *K8SClient) GetSecret(namespace, name string) (*core_v1.Secret, error) {
+	secret, err := in.k8s.CoreV1().Secrets(namespace).Get(name, emptyGetOptions)
+	if err != nil {
+		return &core_v1.Secret{}, err
+	}
+
+	return secret, nil
+}
+
+// GetServiceAccounts returns the ServiceAccounts of the namespace
+func (in *K8SClient) GetServiceAccounts(namespace string) ([]core_v1.ServiceAccount, error) {
+	if serviceAccountList, err := in.k8s.CoreV1().ServiceAccounts(namespace).List(emptyListOptions); err == nil {
+		return serviceAccountList.Items, nil
+	} else {
+		return []core_v1.ServiceAccount{}, err
+	}
+}
+
 // GetServerVersion fetches and returns information about the version Kubernetes that is running
 func (in *K8SClient) GetServerVersion() (*version.Info, error) {
 	return in.k8s.Discovery().ServerVersion()
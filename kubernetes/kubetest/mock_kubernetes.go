@@ -10,6 +10,11 @@ import (
 	"github.com/kiali/kiali/kubernetes"
 )
 
+func (o *K8SClientMock) CreateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	args := o.Called(namespace, configMap)
+	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
+}
+
 func (o *K8SClientMock) GetConfigMap(namespace, configName string) (*core_v1.ConfigMap, error) {
 	args := o.Called(namespace, configName)
 	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
@@ -80,11 +85,21 @@ func (o *K8SClientMock) GetReplicaSets(namespace string) ([]apps_v1.ReplicaSet,
 	return args.Get(0).([]apps_v1.ReplicaSet), args.Error(1)
 }
 
+func (o *K8SClientMock) GetSecret(namespace, name string) (*core_v1.Secret, error) {
+	args := o.Called(namespace, name)
+	return args.Get(0).(*core_v1.Secret), args.Error(1)
+}
+
 func (o *K8SClientMock) GetSelfSubjectAccessReview(namespace, api, resourceType string, verbs []string) ([]*auth_v1.SelfSubjectAccessReview, error) {
 	args := o.Called(namespace, api, resourceType, verbs)
 	return args.Get(0).([]*auth_v1.SelfSubjectAccessReview), args.Error(1)
 }
 
+func (o *K8SClientMock) GetServiceAccounts(namespace string) ([]core_v1.ServiceAccount, error) {
+	args := o.Called(namespace)
+	return args.Get(0).([]core_v1.ServiceAccount), args.Error(1)
+}
+
 func (o *K8SClientMock) GetService(namespace string, serviceName string) (*core_v1.Service, error) {
 	args := o.Called(namespace, serviceName)
 	return args.Get(0).(*core_v1.Service), args.Error(1)
@@ -105,6 +120,11 @@ func (o *K8SClientMock) GetStatefulSets(namespace string) ([]apps_v1.StatefulSet
 	return args.Get(0).([]apps_v1.StatefulSet), args.Error(1)
 }
 
+func (o *K8SClientMock) UpdateConfigMap(namespace string, configMap *core_v1.ConfigMap) (*core_v1.ConfigMap, error) {
+	args := o.Called(namespace, configMap)
+	return args.Get(0).(*core_v1.ConfigMap), args.Error(1)
+}
+
 func (o *K8SClientMock) UpdateNamespace(namespace string, jsonPatch string) (*core_v1.Namespace, error) {
 	args := o.Called(namespace, jsonPatch)
 	return args.Get(0).(*core_v1.Namespace), args.Error(1)
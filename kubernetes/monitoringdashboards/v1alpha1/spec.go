@@ -38,6 +38,7 @@ type MonitoringDashboardSpec struct {
 	DiscoverOn    string                            `json:"discoverOn"`
 	Items         []MonitoringDashboardItem         `json:"items"`
 	ExternalLinks []MonitoringDashboardExternalLink `json:"externalLinks"`
+	Variables     []MonitoringDashboardVariable     `json:"variables"` // Templated variables, substituted in the expressions of the charts
 }
 
 type MonitoringDashboardItem struct {
@@ -67,11 +68,31 @@ type MonitoringDashboardChart struct {
 	GroupLabels      []string                         `json:"groupLabels"`      // Prometheus label to be used for grouping; Similar to Aggregations, except this grouping will be always turned on
 	SortLabel        string                           `json:"sortLabel"`        // Prometheus label to be used for sorting
 	SortLabelParseAs string                           `json:"sortLabelParseAs"` // Set "int" if the SortLabel needs to be parsed and compared as an integer
+	Thresholds       []MonitoringDashboardThreshold   `json:"thresholds"`       // Values annotated on the chart, in the base unit
 }
 
 type MonitoringDashboardMetric struct {
 	MetricName  string `json:"metricName"`
 	DisplayName string `json:"displayName"`
+	// Expression is a PromQL expression replacing MetricName, DataType and the aggregations of the chart.
+	// It can reference the dashboard variables as $name or ${name}, and the built-in $namespace and $rate_interval.
+	// The namespace and labels filters of the dashboard are enforced on all its selectors.
+	Expression string `json:"expression"`
+}
+
+type MonitoringDashboardVariable struct {
+	Name        string `json:"name"` // Referenced as $name or ${name} in the expressions
+	DisplayName string `json:"displayName"`
+	Label       string `json:"label"`  // Prometheus label whose values, in the dashboard namespace, are the options of the variable
+	Metric      string `json:"metric"` // Optional metric name restricting the series the options are read from
+	Multi       bool   `json:"multi"`  // Multiple values are substituted as a regular expression alternation, i.e. "a|b"
+	Default     string `json:"default"`
+}
+
+type MonitoringDashboardThreshold struct {
+	Value    float64 `json:"value"`
+	Label    string  `json:"label"`
+	Severity string  `json:"severity"` // "warning" or "critical"
 }
 
 type MonitoringDashboardAggregation struct {
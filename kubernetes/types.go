@@ -67,6 +67,33 @@ const (
 	RequestAuthenticationsType     = "RequestAuthentication"
 	RequestAuthenticationsTypeList = "RequestAuthenticationList"
 
+	// Legacy authentication and RBAC types, removed in Istio 1.6.
+	// They are only read to report the leftovers of previous Istio versions.
+
+	Policies       = "policies"
+	PolicyType     = "Policy"
+	PolicyTypeList = "PolicyList"
+
+	MeshPolicies       = "meshpolicies"
+	MeshPolicyType     = "MeshPolicy"
+	MeshPolicyTypeList = "MeshPolicyList"
+
+	ClusterRbacConfigs        = "clusterrbacconfigs"
+	ClusterRbacConfigType     = "ClusterRbacConfig"
+	ClusterRbacConfigTypeList = "ClusterRbacConfigList"
+
+	RbacConfigs        = "rbacconfigs"
+	RbacConfigType     = "RbacConfig"
+	RbacConfigTypeList = "RbacConfigList"
+
+	ServiceRoles        = "serviceroles"
+	ServiceRoleType     = "ServiceRole"
+	ServiceRoleTypeList = "ServiceRoleList"
+
+	ServiceRoleBindings        = "servicerolebindings"
+	ServiceRoleBindingType     = "ServiceRoleBinding"
+	ServiceRoleBindingTypeList = "ServiceRoleBindingList"
+
 	// Iter8 types
 
 	Iter8Experiments        = "experiments"
@@ -88,6 +115,19 @@ var (
 	}
 	ApiSecurityVersion = SecurityGroupVersion.Group + "/" + SecurityGroupVersion.Version
 
+	// Legacy API groups, removed in Istio 1.6
+	AuthenticationGroupVersion = schema.GroupVersion{
+		Group:   "authentication.istio.io",
+		Version: "v1alpha1",
+	}
+	ApiAuthenticationVersion = AuthenticationGroupVersion.Group + "/" + AuthenticationGroupVersion.Version
+
+	RbacGroupVersion = schema.GroupVersion{
+		Group:   "rbac.istio.io",
+		Version: "v1alpha1",
+	}
+	ApiRbacVersion = RbacGroupVersion.Group + "/" + RbacGroupVersion.Version
+
 	// We will add a new extesion API in a similar way as we added the Kubernetes + Istio APIs
 	Iter8GroupVersion = schema.GroupVersion{
 		Group:   "iter8.tools",
@@ -147,6 +187,42 @@ var (
 		},
 	}
 
+	authenticationTypes = []struct {
+		objectKind     string
+		collectionKind string
+	}{
+		{
+			objectKind:     PolicyType,
+			collectionKind: PolicyTypeList,
+		},
+		{
+			objectKind:     MeshPolicyType,
+			collectionKind: MeshPolicyTypeList,
+		},
+	}
+
+	rbacTypes = []struct {
+		objectKind     string
+		collectionKind string
+	}{
+		{
+			objectKind:     ClusterRbacConfigType,
+			collectionKind: ClusterRbacConfigTypeList,
+		},
+		{
+			objectKind:     RbacConfigType,
+			collectionKind: RbacConfigTypeList,
+		},
+		{
+			objectKind:     ServiceRoleType,
+			collectionKind: ServiceRoleTypeList,
+		},
+		{
+			objectKind:     ServiceRoleBindingType,
+			collectionKind: ServiceRoleBindingTypeList,
+		},
+	}
+
 	iter8Types = []struct {
 		objectKind     string
 		collectionKind string
@@ -173,6 +249,14 @@ var (
 		PeerAuthentications:    PeerAuthenticationsType,
 		RequestAuthentications: RequestAuthenticationsType,
 
+		// Legacy
+		Policies:            PolicyType,
+		MeshPolicies:        MeshPolicyType,
+		ClusterRbacConfigs:  ClusterRbacConfigType,
+		RbacConfigs:         RbacConfigType,
+		ServiceRoles:        ServiceRoleType,
+		ServiceRoleBindings: ServiceRoleBindingType,
+
 		// Iter8
 		Iter8Experiments: Iter8ExperimentType,
 	}
@@ -192,6 +276,16 @@ var (
 		Iter8Experiments: Iter8GroupVersion.Group,
 	}
 
+	// Legacy types can only be read, so they are kept apart from ResourceTypesToAPI
+	LegacyResourceTypesToAPI = map[string]string{
+		Policies:            AuthenticationGroupVersion.Group,
+		MeshPolicies:        AuthenticationGroupVersion.Group,
+		ClusterRbacConfigs:  RbacGroupVersion.Group,
+		RbacConfigs:         RbacGroupVersion.Group,
+		ServiceRoles:        RbacGroupVersion.Group,
+		ServiceRoleBindings: RbacGroupVersion.Group,
+	}
+
 	ApiToVersion = map[string]string{
 		NetworkingGroupVersion.Group: ApiNetworkingVersion,
 		SecurityGroupVersion.Group:   ApiSecurityVersion,
@@ -265,7 +359,8 @@ type MTLSDetails struct {
 
 // RBACDetails is a wrapper for objects related to Istio RBAC (Role Based Access Control)
 type RBACDetails struct {
-	AuthorizationPolicies []IstioObject `json:"authorizationpolicies"`
+	AuthorizationPolicies     []IstioObject `json:"authorizationpolicies"`
+	MeshAuthorizationPolicies []IstioObject `json:"meshauthorizationpolicies"`
 }
 
 // GenericIstioObject is a type to test Istio types defined by Istio as a Kubernetes extension.
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Signals compared to their baselines
+const (
+	AnomalyRequestRate = "requestRate"
+	AnomalyErrorRatio  = "errorRatio"
+	AnomalyLatency     = "latency"
+)
+
+// Anomaly compares a signal of the requests received by an app, service or workload to its baseline:
+// the same signal at the same time one period ago
+// swagger:model Anomaly
+type Anomaly struct {
+	// Signal compared: requestRate (requests per second), errorRatio (0 to 1) or latency (milliseconds)
+	// required: true
+	// example: requestRate
+	Signal string `json:"signal"`
+
+	// Offset of the baseline, i.e. 1d for the same time yesterday or 1w for the same time last week
+	// required: true
+	// example: 1w
+	Baseline string `json:"baseline"`
+
+	// Value of the signal over the rate interval, empty when it could not be computed
+	// example: 2.5
+	Current *float64 `json:"current"`
+
+	// Value of the signal at the same time of the baseline period, empty when it could not be computed
+	// example: 12.5
+	Expected *float64 `json:"expected"`
+
+	// Relative deviation from the expected value, i.e. -0.8 when the signal is 80% lower than expected
+	// required: true
+	// example: -0.8
+	Score float64 `json:"score"`
+
+	// Whether the deviation is significant. Traffic drops and spikes are anomalies,
+	// only increases of the error ratio and of the latency are.
+	// required: true
+	// example: true
+	Anomalous bool `json:"anomalous"`
+}
+
+// AnomaliesQuery selects the apps, services or workloads of a namespace whose requests are compared to their baselines
+type AnomaliesQuery struct {
+	Namespace string
+	// Kind of the objects: app, service or workload
+	Kind string
+	// Name of the object, all the objects of the namespace when empty
+	Name         string
+	RateInterval string
+	QueryTime    time.Time
+}
@@ -15,6 +15,7 @@ type DashboardQuery struct {
 	LabelsFilters     map[string]string
 	AdditionalLabels  []Aggregation
 	RawDataAggregator string
+	Variables         map[string][]string
 }
 
 // FillDefaults fills the struct with default parameters
@@ -29,20 +30,38 @@ type MonitoringDashboard struct {
 	Charts        []Chart        `json:"charts"`
 	Aggregations  []Aggregation  `json:"aggregations"`
 	ExternalLinks []ExternalLink `json:"externalLinks"`
+	Variables     []Variable     `json:"variables"`
+}
+
+// Variable is the model representing a templated variable of a custom dashboard, with its options and selected values
+type Variable struct {
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName"`
+	Multi       bool     `json:"multi"`
+	Options     []string `json:"options"`
+	Values      []string `json:"values"`
 }
 
 // Chart is the model representing a custom chart, transformed from charts in MonitoringDashboard k8s resource
 type Chart struct {
-	Name           string   `json:"name"`
-	Unit           string   `json:"unit"`
-	Spans          int      `json:"spans"`
-	StartCollapsed bool     `json:"startCollapsed"`
-	ChartType      *string  `json:"chartType,omitempty"`
-	Min            *int     `json:"min,omitempty"`
-	Max            *int     `json:"max,omitempty"`
-	Metrics        []Metric `json:"metrics"`
-	XAxis          *string  `json:"xAxis"`
-	Error          string   `json:"error"`
+	Name           string      `json:"name"`
+	Unit           string      `json:"unit"`
+	Spans          int         `json:"spans"`
+	StartCollapsed bool        `json:"startCollapsed"`
+	ChartType      *string     `json:"chartType,omitempty"`
+	Min            *int        `json:"min,omitempty"`
+	Max            *int        `json:"max,omitempty"`
+	Metrics        []Metric    `json:"metrics"`
+	XAxis          *string     `json:"xAxis"`
+	Thresholds     []Threshold `json:"thresholds,omitempty"`
+	Error          string      `json:"error"`
+}
+
+// Threshold is a value annotated on a chart, transformed from thresholds in MonitoringDashboard k8s resource
+type Threshold struct {
+	Value    float64 `json:"value"`
+	Label    string  `json:"label"`
+	Severity string  `json:"severity"`
 }
 
 // ConvertChart converts a k8s chart (from MonitoringDashboard k8s resource) into this models chart
@@ -57,7 +76,19 @@ func ConvertChart(from v1alpha1.MonitoringDashboardChart) Chart {
 		Max:            from.Max,
 		Metrics:        []Metric{},
 		XAxis:          from.XAxis,
+		Thresholds:     convertThresholds(from.Thresholds),
+	}
+}
+
+func convertThresholds(from []v1alpha1.MonitoringDashboardThreshold) []Threshold {
+	if len(from) == 0 {
+		return nil
+	}
+	thresholds := make([]Threshold, len(from))
+	for i, t := range from {
+		thresholds[i] = Threshold{Value: t.Value, Label: t.Label, Severity: t.Severity}
 	}
+	return thresholds
 }
 
 // Aggregation is the model representing label's allowed aggregation, transformed from aggregation in MonitoringDashboard k8s resource
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"github.com/kiali/kiali/kubernetes/monitoringdashboards/v1alpha1"
+)
+
+// Kinds of Grafana items that can be skipped during a conversion
+const (
+	GrafanaPanel    = "panel"
+	GrafanaTarget   = "target"
+	GrafanaVariable = "variable"
+)
+
+// GrafanaConversionOptions holds the optional settings of a Grafana dashboard conversion
+type GrafanaConversionOptions struct {
+	// Name of the MonitoringDashboard resource. Defaults to the Grafana title, as a Kubernetes name.
+	Name string
+	// Runtime of the MonitoringDashboard resource. Defaults to the Grafana title.
+	Runtime string
+}
+
+// GrafanaDashboardConversion is the result of the conversion of a Grafana dashboard into a MonitoringDashboard resource
+// swagger:model GrafanaDashboardConversion
+type GrafanaDashboardConversion struct {
+	// The converted resource
+	Dashboard v1alpha1.MonitoringDashboard `json:"dashboard"`
+	// Grafana items that could not be converted
+	Skipped []SkippedGrafanaItem `json:"skipped"`
+}
+
+// SkippedGrafanaItem reports a Grafana panel, target or variable that could not be converted
+type SkippedGrafanaItem struct {
+	// Kind is either "panel", "target" or "variable"
+	Kind string `json:"kind"`
+	// Title of the panel, or name of the variable
+	Title string `json:"title"`
+	// For targets, the PromQL expression
+	Expression string `json:"expression,omitempty"`
+	Reason     string `json:"reason"`
+}
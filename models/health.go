@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/prometheus/common/model"
 )
 
@@ -15,13 +17,19 @@ type NamespaceWorkloadHealth map[string]*WorkloadHealth
 
 // ServiceHealth contains aggregated health from various sources, for a given service
 type ServiceHealth struct {
-	Requests RequestHealth `json:"requests"`
+	Requests  RequestHealth   `json:"requests"`
+	Latency   []LatencyHealth `json:"latency,omitempty"`
+	SLOs      []SLOStatus     `json:"slos,omitempty"`
+	Anomalies []Anomaly       `json:"anomalies,omitempty"`
 }
 
 // AppHealth contains aggregated health from various sources, for a given app
 type AppHealth struct {
 	WorkloadStatuses []*WorkloadStatus `json:"workloadStatuses"`
 	Requests         RequestHealth     `json:"requests"`
+	Latency          []LatencyHealth   `json:"latency,omitempty"`
+	SLOs             []SLOStatus       `json:"slos,omitempty"`
+	Anomalies        []Anomaly         `json:"anomalies,omitempty"`
 }
 
 func NewEmptyRequestHealth() RequestHealth {
@@ -54,6 +62,9 @@ func EmptyWorkloadHealth() *WorkloadHealth {
 type WorkloadHealth struct {
 	WorkloadStatus *WorkloadStatus `json:"workloadStatus"`
 	Requests       RequestHealth   `json:"requests"`
+	Latency        []LatencyHealth `json:"latency,omitempty"`
+	SLOs           []SLOStatus     `json:"slos,omitempty"`
+	Anomalies      []Anomaly       `json:"anomalies,omitempty"`
 }
 
 // WorkloadStatus gives
@@ -71,6 +82,121 @@ type WorkloadStatus struct {
 	CurrentReplicas   int32  `json:"currentReplicas"`
 	AvailableReplicas int32  `json:"availableReplicas"`
 	SyncedProxies     int32  `json:"syncedProxies"`
+	// Pod-level signals, missing without pods
+	Pods *PodsHealth `json:"pods,omitempty"`
+}
+
+// PodsHealth summarizes the pod-level signals of the pods of a workload, since the start of the rate interval.
+// The signals of the istio-proxy containers are kept apart from the ones of the app containers.
+type PodsHealth struct {
+	// Pods in Pending phase since before the rate interval
+	Pending int32 `json:"pending"`
+	// Running pods with some app container not ready
+	NotReady int32 `json:"notReady"`
+	// Running pods with the proxy not ready
+	ProxyNotReady int32            `json:"proxyNotReady"`
+	App           ContainersHealth `json:"app"`
+	Proxy         ContainersHealth `json:"proxy"`
+	// healthy, degraded (pending, not ready, restarted or recently terminated containers) or failure (crash-looping or OOMKilled containers)
+	Status string `json:"status"`
+}
+
+// ContainersHealth summarizes the restarts and failures of a set of containers
+type ContainersHealth struct {
+	// Restarts of the containers in the rate interval, from the kube_pod_container_status_restarts_total metric of
+	// kube-state-metrics. Missing when Prometheus doesn't scrape it.
+	Restarts *int32 `json:"restarts,omitempty"`
+	// Restarts of the containers since their pod started, whenever they happened: they are not limited to the rate interval
+	TotalRestarts int32 `json:"totalRestarts"`
+	// Containers whose last termination is in the rate interval, so a container terminated several times counts once.
+	// Containers that completed, such as the ones of Job pods, are not counted.
+	RecentlyTerminated int32 `json:"recentlyTerminated"`
+	// Reasons of the last terminations in the rate interval, i.e. {"OOMKilled": 1, "Error": 2}
+	TerminationReasons map[string]int32 `json:"terminationReasons,omitempty"`
+	// Containers waiting in CrashLoopBackOff
+	CrashLooping int32 `json:"crashLooping"`
+}
+
+// ContainerRestarts are the restarts of the containers in the rate interval, by pod name then container name
+type ContainerRestarts map[string]map[string]int32
+
+func (in *ContainersHealth) aggregate(container *ContainerInfo, since time.Time, restarts map[string]int32) {
+	in.TotalRestarts += container.RestartCount
+	if in.Restarts != nil {
+		*in.Restarts += restarts[container.Name]
+	}
+	if container.WaitingReason == "CrashLoopBackOff" {
+		in.CrashLooping++
+	}
+	if container.TerminatedAt == "" || container.TerminationReason == "Completed" {
+		return
+	}
+	if terminatedAt, err := time.Parse(time.RFC3339, container.TerminatedAt); err == nil && !terminatedAt.Before(since) {
+		in.RecentlyTerminated++
+		if container.TerminationReason != "" {
+			if in.TerminationReasons == nil {
+				in.TerminationReasons = make(map[string]int32)
+			}
+			in.TerminationReasons[container.TerminationReason]++
+		}
+	}
+}
+
+func (in ContainersHealth) failing() bool {
+	return in.CrashLooping > 0 || in.TerminationReasons["OOMKilled"] > 0
+}
+
+func (in ContainersHealth) restarted() bool {
+	return in.Restarts != nil && *in.Restarts > 0
+}
+
+// CastPodsHealth returns the pod-level signals of the pods since the given time, nil without pods.
+// The restarts in the rate interval are unknown when restarts is nil.
+func (pods Pods) CastPodsHealth(since time.Time, restarts ContainerRestarts) *PodsHealth {
+	if len(pods) == 0 {
+		return nil
+	}
+
+	health := PodsHealth{}
+	if restarts != nil {
+		health.App.Restarts, health.Proxy.Restarts = new(int32), new(int32)
+	}
+	for _, pod := range pods {
+		if pod.Status == "Pending" {
+			if createdAt, err := time.Parse(time.RFC3339, pod.CreatedAt); err == nil && createdAt.Before(since) {
+				health.Pending++
+			}
+		}
+		appReady, proxyReady := true, true
+		for _, c := range pod.Containers {
+			health.App.aggregate(c, since, restarts[pod.Name])
+			appReady = appReady && c.IsReady
+		}
+		for _, c := range pod.IstioContainers {
+			health.Proxy.aggregate(c, since, restarts[pod.Name])
+			proxyReady = proxyReady && c.IsReady
+		}
+		if pod.Status == "Running" {
+			if !appReady {
+				health.NotReady++
+			}
+			if !proxyReady {
+				health.ProxyNotReady++
+			}
+		}
+	}
+
+	switch {
+	case health.App.failing() || health.Proxy.failing():
+		health.Status = FailureStatus
+	case health.Pending > 0 || health.NotReady > 0 || health.ProxyNotReady > 0 ||
+		health.App.RecentlyTerminated > 0 || health.Proxy.RecentlyTerminated > 0 ||
+		health.App.restarted() || health.Proxy.restarted():
+		health.Status = DegradedStatus
+	default:
+		health.Status = HealthyStatus
+	}
+	return &health
 }
 
 // ProxyStatus gives the sync status of the sidecar proxy.
@@ -91,6 +217,27 @@ type RequestHealth struct {
 	Outbound map[string]map[string]float64 `json:"outbound"`
 }
 
+// Status of a health signal
+const (
+	HealthyStatus  = "healthy"
+	DegradedStatus = "degraded"
+	FailureStatus  = "failure"
+	// Nothing to evaluate, i.e. no request was received in the rate interval
+	NoDataStatus = "nodata"
+)
+
+// LatencyHealth is the latency of the requests at the quantile of a latency tolerance, compared to its thresholds.
+// Latency and thresholds are in milliseconds. Latency is missing when there was no request.
+type LatencyHealth struct {
+	Quantile  float64  `json:"quantile"`
+	Protocol  string   `json:"protocol,omitempty"`
+	Direction string   `json:"direction"`
+	Latency   *float64 `json:"latency,omitempty"`
+	Degraded  float64  `json:"degraded,omitempty"`
+	Failure   float64  `json:"failure,omitempty"`
+	Status    string   `json:"status"`
+}
+
 // AggregateInbound adds the provided metric sample to internal inbound counters and updates error ratios
 func (in *RequestHealth) AggregateInbound(sample *model.Sample) {
 	aggregate(sample, in.Inbound)
@@ -122,8 +269,8 @@ func aggregate(sample *model.Sample, requests map[string]map[string]float64) {
 	}
 }
 
-// CastWorkloadStatus returns a WorkloadStatus out of a given Workload
-func (w Workload) CastWorkloadStatus() *WorkloadStatus {
+// CastWorkloadStatus returns a WorkloadStatus out of a given Workload, with the pod-level signals since the given time
+func (w Workload) CastWorkloadStatus(since time.Time, restarts ContainerRestarts) *WorkloadStatus {
 	syncedProxies := int32(-1)
 	if w.HasIstioSidecar() {
 		syncedProxies = w.Pods.SyncedPodProxiesCount()
@@ -135,14 +282,15 @@ func (w Workload) CastWorkloadStatus() *WorkloadStatus {
 		CurrentReplicas:   w.CurrentReplicas,
 		AvailableReplicas: w.AvailableReplicas,
 		SyncedProxies:     syncedProxies,
+		Pods:              w.Pods.CastPodsHealth(since, restarts),
 	}
 }
 
 // CastWorkloadStatuses returns a WorkloadStatus array out of a given set of Workloads
-func (ws Workloads) CastWorkloadStatuses() []*WorkloadStatus {
+func (ws Workloads) CastWorkloadStatuses(since time.Time, restarts ContainerRestarts) []*WorkloadStatus {
 	statuses := make([]*WorkloadStatus, 0)
 	for _, w := range ws {
-		statuses = append(statuses, w.CastWorkloadStatus())
+		statuses = append(statuses, w.CastWorkloadStatus(since, restarts))
 	}
 	return statuses
 }
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// HealthEvent is a change of the health status of an app, service or workload, detected by the background health evaluator
+// swagger:model HealthEvent
+type HealthEvent struct {
+	// Increasing identifier of the event, to poll the newer events
+	// required: true
+	// example: 42
+	Id int64 `json:"id"`
+
+	// When the new status was notified, after the debounce
+	// required: true
+	Timestamp time.Time `json:"timestamp"`
+
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// Kind of the object: app, service or workload
+	// required: true
+	// example: workload
+	Kind string `json:"kind"`
+
+	// required: true
+	// example: reviews-v2
+	Name string `json:"name"`
+
+	// Previous status: healthy, degraded or failure
+	// required: true
+	// example: healthy
+	From string `json:"from"`
+
+	// New status: healthy, degraded or failure
+	// required: true
+	// example: failure
+	To string `json:"to"`
+}
+
+// HealthEvents is a list of health change events, oldest first
+// swagger:model HealthEvents
+type HealthEvents []HealthEvent
+
+// String describes the event in a sentence
+func (e HealthEvent) String() string {
+	return fmt.Sprintf("Health of %s %s/%s changed from %s to %s", e.Kind, e.Namespace, e.Name, e.From, e.To)
+}
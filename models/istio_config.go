@@ -40,6 +40,53 @@ type IstioConfigDetails struct {
 	IstioValidation       *IstioValidation       `json:"validation"`
 }
 
+// IstioConfigPreview holds the expected effects of applying a change on an Istio object
+type IstioConfigPreview struct {
+	// Validations of the changed object and the objects related to it, once the change is applied
+	// required: true
+	Validations IstioValidations `json:"validations"`
+
+	// Checks introduced and resolved by the change
+	// required: true
+	ValidationsDiff IstioValidationsDiff `json:"validationsDiff"`
+
+	// Estimated traffic shift on the route destinations (only for VirtualServices)
+	TrafficImpact []TrafficShift `json:"trafficImpact"`
+}
+
+// TrafficShift represents the estimated traffic received by a route destination before and after a change
+type TrafficShift struct {
+	// Destination host
+	// required: true
+	// example: reviews.bookinfo.svc.cluster.local
+	Host string `json:"host"`
+
+	// Destination subset
+	// example: v1
+	Subset string `json:"subset"`
+
+	// Workloads behind the destination
+	Workloads []string `json:"workloads"`
+
+	// Share of the requests, in percentage, before the change
+	// required: true
+	// example: 100
+	CurrentWeight float64 `json:"currentWeight"`
+
+	// Share of the requests, in percentage, after the change
+	// required: true
+	// example: 50
+	ProposedWeight float64 `json:"proposedWeight"`
+
+	// Estimated request rate (req/s) before the change
+	// required: true
+	CurrentRate float64 `json:"currentRate"`
+
+	// Estimated request rate (req/s) after the change
+	// required: true
+	ProposedRate float64 `json:"proposedRate"`
+}
+
 // ResourcePermissions holds permission flags for an object type
 // True means allowed.
 type ResourcePermissions struct {
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"sort"
+)
+
+// Kinds of the references between objects
+const (
+	// VirtualService bound to a Gateway
+	DependencyGateway = "gateway"
+	// Route destination or DestinationRule host resolved to a Service or ServiceEntry
+	DependencyHost = "host"
+	// Route destination using a subset of a DestinationRule
+	DependencySubset = "subset"
+	// Policy or Sidecar applied to a workload by its selector
+	DependencySelector = "selector"
+	// Host imported by a Sidecar egress listener
+	DependencyEgress = "egress"
+	// WorkloadEntry selected by a ServiceEntry
+	DependencyWorkloadEntry = "workloadentry"
+)
+
+// IstioDependencyGraph describes the references between the Istio objects and the Kubernetes objects of a namespace.
+// An edge goes from the object declaring the reference to the object it depends on.
+// swagger:model IstioDependencyGraph
+type IstioDependencyGraph struct {
+	// Objects of the graph
+	// required: true
+	Nodes []IstioValidationKey `json:"nodes"`
+
+	// References between the objects
+	// required: true
+	Edges []IstioDependencyEdge `json:"edges"`
+}
+
+// IstioDependencyEdge is a reference from an object to another one
+type IstioDependencyEdge struct {
+	// Object declaring the reference
+	// required: true
+	Source IstioValidationKey `json:"source"`
+
+	// Object referenced
+	// required: true
+	Target IstioValidationKey `json:"target"`
+
+	// Kind of the reference: gateway, host, subset, selector, egress or workloadentry
+	// required: true
+	// example: gateway
+	Kind string `json:"kind"`
+
+	// Path of the field declaring the reference in the source object
+	// example: spec/gateways[0]
+	Path string `json:"path"`
+}
+
+// NewIstioDependencyGraph builds a graph from its edges, adding the objects without references as standalone nodes
+func NewIstioDependencyGraph(objects []IstioValidationKey, edges []IstioDependencyEdge) IstioDependencyGraph {
+	seen := map[IstioValidationKey]bool{}
+	graph := IstioDependencyGraph{Nodes: []IstioValidationKey{}, Edges: []IstioDependencyEdge{}}
+
+	addNode := func(key IstioValidationKey) {
+		if !seen[key] {
+			seen[key] = true
+			graph.Nodes = append(graph.Nodes, key)
+		}
+	}
+	for _, key := range objects {
+		addNode(key)
+	}
+	seenEdges := map[IstioDependencyEdge]bool{}
+	for _, edge := range edges {
+		if seenEdges[edge] {
+			continue
+		}
+		seenEdges[edge] = true
+		addNode(edge.Source)
+		addNode(edge.Target)
+		graph.Edges = append(graph.Edges, edge)
+	}
+
+	graph.sort()
+	return graph
+}
+
+// Dependents returns the subgraph of the objects depending, directly or transitively, on the given object:
+// the objects that may break when it is deleted. The object itself is included.
+func (g IstioDependencyGraph) Dependents(key IstioValidationKey) IstioDependencyGraph {
+	incoming := map[IstioValidationKey][]IstioDependencyEdge{}
+	for _, edge := range g.Edges {
+		incoming[edge.Target] = append(incoming[edge.Target], edge)
+	}
+
+	visited := map[IstioValidationKey]bool{key: true}
+	edges := []IstioDependencyEdge{}
+	pending := []IstioValidationKey{key}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		for _, edge := range incoming[current] {
+			edges = append(edges, edge)
+			if !visited[edge.Source] {
+				visited[edge.Source] = true
+				pending = append(pending, edge.Source)
+			}
+		}
+	}
+
+	return NewIstioDependencyGraph([]IstioValidationKey{key}, edges)
+}
+
+// HasNode checks whether the object is part of the graph
+func (g IstioDependencyGraph) HasNode(key IstioValidationKey) bool {
+	for _, node := range g.Nodes {
+		if node == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *IstioDependencyGraph) sort() {
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		return keyLess(g.Nodes[i], g.Nodes[j])
+	})
+	sort.Slice(g.Edges, func(i, j int) bool {
+		ei, ej := g.Edges[i], g.Edges[j]
+		if ei.Source != ej.Source {
+			return keyLess(ei.Source, ej.Source)
+		}
+		if ei.Target != ej.Target {
+			return keyLess(ei.Target, ej.Target)
+		}
+		if ei.Kind != ej.Kind {
+			return ei.Kind < ej.Kind
+		}
+		return ei.Path < ej.Path
+	})
+}
+
+func keyLess(ki, kj IstioValidationKey) bool {
+	if ki.Namespace != kj.Namespace {
+		return ki.Namespace < kj.Namespace
+	}
+	if ki.ObjectType != kj.ObjectType {
+		return ki.ObjectType < kj.ObjectType
+	}
+	return ki.Name < kj.Name
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIstioDependencyGraphDependents(t *testing.T) {
+	assert := assert.New(t)
+
+	gw := BuildKey("gateway", "bookinfo-gateway", "bookinfo")
+	vs := BuildKey("virtualservice", "bookinfo", "bookinfo")
+	dr := BuildKey("destinationrule", "reviews", "bookinfo")
+	svc := BuildKey("service", "reviews", "bookinfo")
+	ap := BuildKey("authorizationpolicy", "allow", "bookinfo")
+	wk := BuildKey("workload", "reviews-v1", "bookinfo")
+
+	graph := NewIstioDependencyGraph([]IstioValidationKey{gw, svc, wk}, []IstioDependencyEdge{
+		{Source: vs, Target: gw, Kind: DependencyGateway, Path: "spec/gateways[0]"},
+		{Source: vs, Target: svc, Kind: DependencyHost, Path: "spec/http[0]/route[0]/destination/host"},
+		{Source: vs, Target: dr, Kind: DependencySubset, Path: "spec/http[0]/route[0]/destination/subset"},
+		{Source: vs, Target: dr, Kind: DependencySubset, Path: "spec/http[0]/route[0]/destination/subset"},
+		{Source: dr, Target: svc, Kind: DependencyHost, Path: "spec/host"},
+		{Source: ap, Target: wk, Kind: DependencySelector, Path: "spec/selector"},
+	})
+
+	// Nodes are added once, and duplicated edges are removed
+	assert.Len(graph.Nodes, 6)
+	assert.Len(graph.Edges, 5)
+	assert.True(graph.HasNode(dr))
+	assert.False(graph.HasNode(BuildKey("gateway", "other", "bookinfo")))
+
+	dependents := graph.Dependents(gw)
+	assert.Equal([]IstioValidationKey{gw, vs}, dependents.Nodes)
+	assert.Len(dependents.Edges, 1)
+
+	// Dependents are found transitively
+	dependents = graph.Dependents(svc)
+	assert.Equal([]IstioValidationKey{dr, svc, vs}, dependents.Nodes)
+	assert.Len(dependents.Edges, 3)
+
+	dependents = graph.Dependents(ap)
+	assert.Equal([]IstioValidationKey{ap}, dependents.Nodes)
+	assert.Empty(dependents.Edges)
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"encoding/json"
+	"sort"
+	"strings"
 )
 
 // NamespaceValidations represents a set of IstioValidations grouped by namespace
@@ -54,6 +56,9 @@ type IstioValidation struct {
 	// Array of checks. It might be empty.
 	Checks []*IstioCheck `json:"checks"`
 
+	// Checks ignored by the Kiali configuration or by the kiali.io/ignore-checks annotation of the object
+	Suppressed []*IstioCheck `json:"suppressed,omitempty"`
+
 	// Related objects (only validation errors)
 	References []IstioValidationKey `json:"references"`
 }
@@ -61,6 +66,11 @@ type IstioValidation struct {
 // IstioCheck represents an individual check.
 // swagger:model
 type IstioCheck struct {
+	// Stable identifier of the check
+	// required: true
+	// example: KIA0203
+	Code string `json:"code"`
+
 	// Description of the check
 	// required: true
 	// example: Weight sum should be 100
@@ -74,6 +84,68 @@ type IstioCheck struct {
 	// String that describes where in the yaml file is the check located
 	// example: spec/http[0]/route
 	Path string `json:"path"`
+
+	// Optional detail of the finding, like the offending value, as the message is the same for all the objects
+	// example: details.bookinfo.svc.cluster.local
+	Detail string `json:"detail,omitempty"`
+
+	// Optional change that resolves the check
+	Fix *IstioCheckFix `json:"fix,omitempty"`
+}
+
+// IstioCheckFix represents a machine-applicable change that resolves a check:
+// either a JSON Merge Patch of an existing object or the creation of a new object.
+type IstioCheckFix struct {
+	// Description of the change
+	// required: true
+	// example: Add subset v3 to DestinationRule reviews
+	Description string `json:"description"`
+
+	// Object patched or created by the fix. It may be different than the object of the check.
+	// required: true
+	Target IstioValidationKey `json:"target"`
+
+	// JSON Merge Patch applied to the target object
+	// example: {"spec":{"subsets":[{"name":"v3","labels":{"version":"v3"}}]}}
+	Patch string `json:"patch,omitempty"`
+
+	// Object created by the fix, in JSON format
+	Object string `json:"object,omitempty"`
+}
+
+// IstioCheckFixRequest identifies the check of an object whose fix is applied
+type IstioCheckFixRequest struct {
+	// Code of the check
+	// required: true
+	// example: KIA1107
+	Code string `json:"code"`
+
+	// Path of the check
+	// required: true
+	// example: spec/http[0]/route[0]/destination
+	Path string `json:"path"`
+}
+
+// IstioCheckChange represents a check that appears or disappears on an object
+type IstioCheckChange struct {
+	// Object where the check is found
+	// required: true
+	Key IstioValidationKey `json:"key"`
+
+	// The check itself
+	// required: true
+	Check *IstioCheck `json:"check"`
+}
+
+// IstioValidationsDiff represents the differences between two sets of Istio Validations
+type IstioValidationsDiff struct {
+	// Checks only present in the new set of validations
+	// required: true
+	Added []IstioCheckChange `json:"added"`
+
+	// Checks only present in the old set of validations
+	// required: true
+	Removed []IstioCheckChange `json:"removed"`
 }
 
 type SeverityLevel string
@@ -94,6 +166,8 @@ var ObjectTypeSingular = map[string]string{
 	"quotaspecbindings":      "quotaspecbinding",
 	"servicemeshpolicies":    "servicemeshpolicy",
 	"policies":               "policy",
+	"meshpolicies":           "meshpolicy",
+	"rbacconfigs":            "rbacconfig",
 	"serviceroles":           "servicerole",
 	"servicerolebindings":    "servicerolebinding",
 	"clusterrbacconfigs":     "clusterrbacconfig",
@@ -120,6 +194,30 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA0105 This field requires mTLS to be enabled",
 		Severity: ErrorSeverity,
 	},
+	"authorizationpolicy.proxy.notapplied": {
+		Message:  "KIA0106 Policy not found in the proxy configuration of a selected workload",
+		Severity: WarningSeverity,
+	},
+	"authorizationpolicy.source.principalnotfound": {
+		Message:  "KIA0107 Service Account not found for this principal",
+		Severity: WarningSeverity,
+	},
+	"authorizationpolicy.to.portnotfound": {
+		Message:  "KIA0108 Port not found in the Services of the selected workloads",
+		Severity: WarningSeverity,
+	},
+	"authorizationpolicy.when.invalidkey": {
+		Message:  "KIA0109 Condition key not supported",
+		Severity: ErrorSeverity,
+	},
+	"authorizationpolicy.rule.unreachable": {
+		Message:  "KIA0110 Rule is unreachable: a DENY policy matches the same requests",
+		Severity: WarningSeverity,
+	},
+	"authorizationpolicy.allow.denyall": {
+		Message:  "KIA0111 ALLOW policy without rules denies all requests to the selected workloads",
+		Severity: WarningSeverity,
+	},
 	"destinationrules.multimatch": {
 		Message:  "KIA0201 More than one DestinationRules for the same host subset combination",
 		Severity: WarningSeverity,
@@ -156,6 +254,14 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA0209 This subset has not labels",
 		Severity: WarningSeverity,
 	},
+	"destinationrules.mtls.portpolicystrict": {
+		Message:  "KIA0211 Port-level settings disable TLS on a port where PeerAuthentication requires STRICT mTLS",
+		Severity: ErrorSeverity,
+	},
+	"destinationrules.proxy.subsetnotapplied": {
+		Message:  "KIA0210 Subset not found in the proxy configuration of a client workload",
+		Severity: WarningSeverity,
+	},
 	"gateways.multimatch": {
 		Message:  "KIA0301 More than one Gateway for the same host port combination",
 		Severity: WarningSeverity,
@@ -164,6 +270,30 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA0302 No matching workload found for gateway selector in this namespace",
 		Severity: WarningSeverity,
 	},
+	"gateways.tls.secretnotfound": {
+		Message:  "KIA0303 Secret referenced by credentialName not found in the namespace of the gateway workload",
+		Severity: ErrorSeverity,
+	},
+	"gateways.tls.invalidcertificate": {
+		Message:  "KIA0304 Secret referenced by credentialName doesn't contain a valid certificate",
+		Severity: ErrorSeverity,
+	},
+	"gateways.tls.certificateexpired": {
+		Message:  "KIA0305 Certificate referenced by credentialName has expired",
+		Severity: ErrorSeverity,
+	},
+	"gateways.tls.certificateexpiring": {
+		Message:  "KIA0306 Certificate referenced by credentialName expires soon",
+		Severity: WarningSeverity,
+	},
+	"gateways.tls.sanmismatch": {
+		Message:  "KIA0307 Host not covered by the Subject Alternative Names of the certificate",
+		Severity: WarningSeverity,
+	},
+	"gateways.tls.missing": {
+		Message:  "KIA0308 HTTPS server without TLS settings",
+		Severity: ErrorSeverity,
+	},
 	"generic.multimatch.selectorless": {
 		Message:  "KIA0002 More than one selector-less object in the same namespace",
 		Severity: ErrorSeverity,
@@ -192,14 +322,66 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA0506 Destination Rule disabling mesh-wide mTLS is missing",
 		Severity: ErrorSeverity,
 	},
+	"peerauthentications.mtls.portnotfound": {
+		Message:  "KIA0507 Port not found in the Services of the selected workloads",
+		Severity: WarningSeverity,
+	},
+	"peerauthentications.mtls.portlevelnoselector": {
+		Message:  "KIA0508 Port-level mTLS requires a workload selector",
+		Severity: ErrorSeverity,
+	},
+	"peerauthentications.mtls.portdestinationruledisabled": {
+		Message:  "KIA0509 STRICT mTLS port is called without TLS by a DestinationRule",
+		Severity: ErrorSeverity,
+	},
+	"peerauthentications.mtls.portconflict": {
+		Message:  "KIA0510 Another PeerAuthentication selecting the same workload sets a different mTLS mode on this port",
+		Severity: WarningSeverity,
+	},
 	"port.name.mismatch": {
 		Message:  "KIA0601 Port name must follow <protocol>[-suffix] form",
 		Severity: ErrorSeverity,
 	},
+	"requestauthentication.jwt.issuermissing": {
+		Message:  "KIA1301 JWT rule without issuer",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentication.jwt.jwksunreachable": {
+		Message:  "KIA1302 JWKS can't be fetched from the jwksUri",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentication.jwt.jwksinvalid": {
+		Message:  "KIA1303 jwksUri doesn't return a valid JWKS",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentication.jwt.inlinejwksinvalid": {
+		Message:  "KIA1304 Inline JWKS is not valid",
+		Severity: ErrorSeverity,
+	},
+	"requestauthentication.jwt.locationcollision": {
+		Message:  "KIA1305 Token location is used by another JWT rule",
+		Severity: WarningSeverity,
+	},
+	"requestauthentication.authorization.notenforced": {
+		Message:  "KIA1306 Requests without token are allowed: no AuthorizationPolicy requires requestPrincipals on the selected workloads",
+		Severity: WarningSeverity,
+	},
 	"service.deployment.port.mismatch": {
 		Message:  "KIA0701 Deployment exposing same port as Service not found",
 		Severity: WarningSeverity,
 	},
+	"service.port.protocolconflict": {
+		Message:  "KIA0702 Ports targeting the same container port declare different protocols",
+		Severity: WarningSeverity,
+	},
+	"service.port.protocolmismatch": {
+		Message:  "KIA0703 Protocol declared in the port name doesn't match the port protocol",
+		Severity: WarningSeverity,
+	},
+	"service.headless.virtualservice": {
+		Message:  "KIA0704 Headless Service routed by a VirtualService: requests go straight to the pod IPs and most routing rules are not applied",
+		Severity: WarningSeverity,
+	},
 	"servicerole.invalid.services": {
 		Message:  "KIA0901 Unable to find all the defined services",
 		Severity: ErrorSeverity,
@@ -224,6 +406,14 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA1006 Global default sidecar should not have workloadSelector",
 		Severity: WarningSeverity,
 	},
+	"sidecar.egress.undeclaredhost": {
+		Message:  "KIA1007 Workloads send requests to a host not declared in the egress hosts",
+		Severity: WarningSeverity,
+	},
+	"sidecar.egress.unusedhost": {
+		Message:  "KIA1008 No requests to this host have been observed",
+		Severity: Unknown,
+	},
 	"virtualservices.gateway.oldnomenclature": {
 		Message:  "KIA1108 Preferred nomenclature: <gateway namespace>/<gateway name>",
 		Severity: Unknown,
@@ -256,22 +446,120 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "KIA1107 Subset not found",
 		Severity: WarningSeverity,
 	},
+	"virtualservices.proxy.notapplied": {
+		Message:  "KIA1109 Routes not found in the proxy configuration of a client workload",
+		Severity: WarningSeverity,
+	},
+	"virtualservices.route.unreachable": {
+		Message:  "KIA1110 Route is unreachable: a previous route matches all the requests",
+		Severity: WarningSeverity,
+	},
+	"virtualservices.match.invalidregex": {
+		Message:  "KIA1111 Invalid regular expression",
+		Severity: ErrorSeverity,
+	},
+	"virtualservices.retries.exceedtimeout": {
+		Message:  "KIA1112 Retries can't be completed: perTryTimeout multiplied by attempts exceeds the route timeout",
+		Severity: WarningSeverity,
+	},
+	"virtualservices.mirror.nosubset": {
+		Message:  "KIA1113 Mirror destination without subset: requests are mirrored to all the versions of the service",
+		Severity: WarningSeverity,
+	},
+	"virtualservices.fault.fullpercentage": {
+		Message:  "KIA1114 Fault injection is applied to all the requests",
+		Severity: WarningSeverity,
+	},
+	"workload.proxy.configdrift": {
+		Message:  "KIA1201 Istio object not applied to the proxy configuration",
+		Severity: WarningSeverity,
+	},
+	"workload.labels.missing": {
+		Message:  "KIA1202 Workload without the app and version labels used by Istio telemetry",
+		Severity: WarningSeverity,
+	},
+	"workload.pod.hostnetwork": {
+		Message:  "KIA1203 Pods using the host network are not injected with the sidecar",
+		Severity: WarningSeverity,
+	},
+	"workload.injection.disabled": {
+		Message:  "KIA1204 Sidecar injection is disabled in a namespace with injection enabled",
+		Severity: WarningSeverity,
+	},
+	"workload.pod.proxyuid": {
+		Message:  "KIA1205 Container running with the UID of the sidecar proxy: its traffic bypasses the proxy",
+		Severity: ErrorSeverity,
+	},
+	"version.resource.removed": {
+		Message:  "KIA1401 API removed in the validated Istio version",
+		Severity: ErrorSeverity,
+	},
+	"version.resource.unsupported": {
+		Message:  "KIA1402 API not supported by the validated Istio version",
+		Severity: ErrorSeverity,
+	},
+	"version.resource.deprecated": {
+		Message:  "KIA1403 API deprecated",
+		Severity: WarningSeverity,
+	},
+	"version.field.removed": {
+		Message:  "KIA1404 Field removed in the validated Istio version",
+		Severity: ErrorSeverity,
+	},
+	"version.field.unsupported": {
+		Message:  "KIA1405 Field not supported by the validated Istio version",
+		Severity: ErrorSeverity,
+	},
+	"version.field.deprecated": {
+		Message:  "KIA1406 Field deprecated",
+		Severity: WarningSeverity,
+	},
 	"validation.unable.cross-namespace": {
 		Message:  "KIA0001 Unable to verify the validity, cross-namespace validation is not supported for this field",
 		Severity: Unknown,
 	},
 }
 
+// IgnoreChecksAnnotation lists, comma separated, the codes of the checks suppressed on an object
+const IgnoreChecksAnnotation = "kiali.io/ignore-checks"
+
 func Build(checkId string, path string) IstioCheck {
 	check := checkDescriptors[checkId]
+	check.Code = CheckCode(check.Message)
 	check.Path = path
 	return check
 }
 
+// BuildPatchFix returns a fix applying the patch, a JSON Merge Patch, to the target object
+func BuildPatchFix(description string, target IstioValidationKey, patch map[string]interface{}) *IstioCheckFix {
+	bytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil
+	}
+	return &IstioCheckFix{Description: description, Target: target, Patch: string(bytes)}
+}
+
+// BuildCreateFix returns a fix creating the target object
+func BuildCreateFix(description string, target IstioValidationKey, object interface{}) *IstioCheckFix {
+	bytes, err := json.Marshal(object)
+	if err != nil {
+		return nil
+	}
+	return &IstioCheckFix{Description: description, Target: target, Object: string(bytes)}
+}
+
 func BuildKey(objectType, name, namespace string) IstioValidationKey {
 	return IstioValidationKey{ObjectType: objectType, Namespace: namespace, Name: name}
 }
 
+// CheckCode returns the code, like KIA0101, at the start of a check message
+func CheckCode(message string) string {
+	if fields := strings.Fields(message); len(fields) > 0 && strings.HasPrefix(fields[0], "KIA") {
+		return fields[0]
+	}
+	return ""
+}
+
 func CheckMessage(checkId string) string {
 	return checkDescriptors[checkId].Message
 }
@@ -320,6 +608,66 @@ func (iv IstioValidations) FilterByTypes(objectTypes []string) IstioValidations
 	return fiv
 }
 
+// FilterByReferences returns the validations of the object identified by key, the objects it references
+// and the objects referencing it
+func (iv IstioValidations) FilterByReferences(key IstioValidationKey) IstioValidations {
+	fiv := IstioValidations{}
+	if v, ok := iv[key]; ok {
+		fiv[key] = v
+		for _, ref := range v.References {
+			if refV, ok := iv[ref]; ok {
+				fiv[ref] = refV
+			}
+		}
+	}
+	for k, v := range iv {
+		for _, ref := range v.References {
+			if ref == key {
+				fiv[k] = v
+			}
+		}
+	}
+
+	return fiv
+}
+
+// Diff returns the checks added and removed in the proposed validations compared to the current ones
+func (iv IstioValidations) Diff(proposed IstioValidations) IstioValidationsDiff {
+	diff := IstioValidationsDiff{
+		Added:   checksNotIn(proposed, iv),
+		Removed: checksNotIn(iv, proposed),
+	}
+	return diff
+}
+
+// checksNotIn returns the checks from source that are not found in target for the same object
+func checksNotIn(source, target IstioValidations) []IstioCheckChange {
+	changes := make([]IstioCheckChange, 0)
+	for key, validation := range source {
+		var targetChecks []*IstioCheck
+		if t, ok := target[key]; ok {
+			targetChecks = t.Checks
+		}
+	NextCheck:
+		for _, c := range validation.Checks {
+			for _, tc := range targetChecks {
+				if c.Path == tc.Path && c.Severity == tc.Severity && c.Message == tc.Message && c.Detail == tc.Detail {
+					continue NextCheck
+				}
+			}
+			changes = append(changes, IstioCheckChange{Key: key, Check: c})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		ki, kj := changes[i].Key, changes[j].Key
+		if ki != kj {
+			return ki.Namespace+"/"+ki.ObjectType+"/"+ki.Name < kj.Namespace+"/"+kj.ObjectType+"/"+kj.Name
+		}
+		return changes[i].Check.Path < changes[j].Check.Path
+	})
+	return changes
+}
+
 func (iv IstioValidations) MergeValidations(validations IstioValidations) IstioValidations {
 	for key, validation := range validations {
 		v, ok := iv[key]
@@ -331,7 +679,8 @@ func (iv IstioValidations) MergeValidations(validations IstioValidations) IstioV
 				for _, existing := range v.Checks {
 					if toAdd.Path == existing.Path &&
 						toAdd.Severity == existing.Severity &&
-						toAdd.Message == existing.Message {
+						toAdd.Message == existing.Message &&
+						toAdd.Detail == existing.Detail {
 						continue AddUnique
 					}
 				}
@@ -365,6 +714,57 @@ func (iv IstioValidations) MergeReferences(validations IstioValidations) IstioVa
 	return iv
 }
 
+// SuppressChecks moves the checks whose code is ignored, globally or for the object, from the checks to the suppressed checks.
+// A validation with suppressed checks is valid again when none of its remaining checks is an error.
+func (iv IstioValidations) SuppressChecks(globalCodes []string, objectCodes map[IstioValidationKey][]string) IstioValidations {
+	if len(globalCodes) == 0 && len(objectCodes) == 0 {
+		return iv
+	}
+	for key, validation := range iv {
+		ignored := map[string]bool{}
+		for _, code := range globalCodes {
+			ignored[code] = true
+		}
+		for _, code := range objectCodes[key] {
+			ignored[code] = true
+		}
+		if len(ignored) == 0 {
+			continue
+		}
+
+		checks := make([]*IstioCheck, 0, len(validation.Checks))
+		for _, check := range validation.Checks {
+			if ignored[check.Code] {
+				validation.Suppressed = append(validation.Suppressed, check)
+			} else {
+				checks = append(checks, check)
+			}
+		}
+		if len(checks) == len(validation.Checks) {
+			continue
+		}
+		validation.Checks = checks
+		validation.Valid = true
+		for _, check := range checks {
+			if check.Severity == ErrorSeverity {
+				validation.Valid = false
+			}
+		}
+	}
+	return iv
+}
+
+// ParseIgnoredChecks returns the check codes listed in the value of the kiali.io/ignore-checks annotation
+func ParseIgnoredChecks(annotation string) []string {
+	codes := make([]string, 0)
+	for _, code := range strings.Split(annotation, ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, strings.ToUpper(code))
+		}
+	}
+	return codes
+}
+
 func (iv IstioValidations) SummarizeValidation(ns string) IstioValidationSummary {
 	ivs := IstioValidationSummary{}
 	for k, v := range iv {
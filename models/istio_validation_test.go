@@ -69,3 +69,129 @@ func TestSummarizeValidations(t *testing.T) {
 	assert.Equal(2, summary.Errors)
 	assert.Equal(2, summary.Errors)
 }
+
+func TestIstioValidationsFilterByReferences(t *testing.T) {
+	assert := assert.New(t)
+
+	vsKey := IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}
+	drKey := IstioValidationKey{ObjectType: "destinationrule", Name: "reviews", Namespace: "bookinfo"}
+	gwKey := IstioValidationKey{ObjectType: "gateway", Name: "bookinfo-gateway", Namespace: "bookinfo"}
+	otherKey := IstioValidationKey{ObjectType: "virtualservice", Name: "ratings", Namespace: "bookinfo"}
+
+	validations := IstioValidations{
+		vsKey:    &IstioValidation{Name: "reviews", ObjectType: "virtualservice", References: []IstioValidationKey{gwKey}},
+		drKey:    &IstioValidation{Name: "reviews", ObjectType: "destinationrule", References: []IstioValidationKey{vsKey}},
+		gwKey:    &IstioValidation{Name: "bookinfo-gateway", ObjectType: "gateway"},
+		otherKey: &IstioValidation{Name: "ratings", ObjectType: "virtualservice"},
+	}
+
+	filtered := validations.FilterByReferences(vsKey)
+	assert.Len(filtered, 3)
+	assert.Contains(filtered, vsKey)
+	assert.Contains(filtered, drKey)
+	assert.Contains(filtered, gwKey)
+	assert.NotContains(filtered, otherKey)
+}
+
+func TestIstioValidationsDiff(t *testing.T) {
+	assert := assert.New(t)
+
+	vsKey := IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}
+	drKey := IstioValidationKey{ObjectType: "destinationrule", Name: "reviews", Namespace: "bookinfo"}
+
+	subsetNotFound := Build("virtualservices.subsetpresent.subsetnotfound", "spec/http[0]/route[0]/destination")
+	weightSum := Build("virtualservices.route.repeatedsubset", "spec/http[0]/route[1]")
+	multipleDr := Build("destinationrules.multimatch", "spec/host")
+
+	current := IstioValidations{
+		vsKey: &IstioValidation{Name: "reviews", ObjectType: "virtualservice", Checks: []*IstioCheck{&subsetNotFound}},
+	}
+	proposed := IstioValidations{
+		vsKey: &IstioValidation{Name: "reviews", ObjectType: "virtualservice", Checks: []*IstioCheck{&weightSum}},
+		drKey: &IstioValidation{Name: "reviews", ObjectType: "destinationrule", Checks: []*IstioCheck{&multipleDr}},
+	}
+
+	diff := current.Diff(proposed)
+	assert.Len(diff.Added, 2)
+	assert.Equal(drKey, diff.Added[0].Key)
+	assert.Equal(multipleDr.Message, diff.Added[0].Check.Message)
+	assert.Equal(vsKey, diff.Added[1].Key)
+	assert.Equal(weightSum.Message, diff.Added[1].Check.Message)
+	assert.Len(diff.Removed, 1)
+	assert.Equal(vsKey, diff.Removed[0].Key)
+	assert.Equal(subsetNotFound.Message, diff.Removed[0].Check.Message)
+
+	assert.Empty(current.Diff(current).Added)
+	assert.Empty(current.Diff(current).Removed)
+
+	// Checks with the same message but a different detail are different findings
+	reviewsHost, ratingsHost := Build("sidecar.egress.undeclaredhost", "spec/egress"), Build("sidecar.egress.undeclaredhost", "spec/egress")
+	reviewsHost.Detail, ratingsHost.Detail = "reviews.bookinfo.svc.cluster.local", "ratings.bookinfo.svc.cluster.local"
+	scKey := IstioValidationKey{ObjectType: "sidecar", Name: "default", Namespace: "bookinfo"}
+	current = IstioValidations{scKey: &IstioValidation{Name: "default", ObjectType: "sidecar", Checks: []*IstioCheck{&reviewsHost}}}
+	proposed = IstioValidations{scKey: &IstioValidation{Name: "default", ObjectType: "sidecar", Checks: []*IstioCheck{&ratingsHost}}}
+	diff = current.Diff(proposed)
+	assert.Len(diff.Added, 1)
+	assert.Equal(ratingsHost.Detail, diff.Added[0].Check.Detail)
+	assert.Len(diff.Removed, 1)
+	assert.Equal(reviewsHost.Detail, diff.Removed[0].Check.Detail)
+}
+
+func TestBuildFixes(t *testing.T) {
+	assert := assert.New(t)
+
+	target := BuildKey("destinationrule", "reviews", "bookinfo")
+	patch := BuildPatchFix("Add subset", target, map[string]interface{}{"spec": map[string]interface{}{"subsets": []interface{}{}}})
+	assert.Equal("Add subset", patch.Description)
+	assert.Equal(target, patch.Target)
+	assert.Equal(`{"spec":{"subsets":[]}}`, patch.Patch)
+	assert.Empty(patch.Object)
+
+	create := BuildCreateFix("Create DestinationRule", target, map[string]interface{}{"kind": "DestinationRule"})
+	assert.Equal(target, create.Target)
+	assert.Equal(`{"kind":"DestinationRule"}`, create.Object)
+	assert.Empty(create.Patch)
+
+	// Fix is omitted when the check doesn't provide one
+	check := Build("virtualservices.route.singleweight", "spec/http[0]/route[0]/weight")
+	bytes, err := json.Marshal(check)
+	assert.NoError(err)
+	assert.NotContains(string(bytes), "fix")
+}
+
+func TestCheckCode(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal("KIA0101", CheckCode(CheckMessage("authorizationpolicy.source.namespacenotfound")))
+	assert.Equal("", CheckCode("Unknown message"))
+	assert.Equal("", CheckCode(""))
+
+	// Every check has a stable code
+	for id := range checkDescriptors {
+		assert.NotEmpty(Build(id, "").Code, id)
+	}
+}
+
+func TestSuppressChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	notFound := Build("authorizationpolicy.source.namespacenotfound", "spec/rules[0]/from[0]")
+	noDest := Build("authorizationpolicy.nodest.matchingregistry", "spec/rules[0]/to[0]")
+	ap1 := BuildKey("authorizationpolicy", "ap1", "bookinfo")
+	ap2 := BuildKey("authorizationpolicy", "ap2", "bookinfo")
+	validations := IstioValidations{
+		ap1: {Name: "ap1", ObjectType: "authorizationpolicy", Valid: false, Checks: []*IstioCheck{&notFound, &noDest}},
+		ap2: {Name: "ap2", ObjectType: "authorizationpolicy", Valid: false, Checks: []*IstioCheck{&notFound, &noDest}},
+	}
+
+	validations.SuppressChecks([]string{"KIA0101"}, map[IstioValidationKey][]string{ap2: ParseIgnoredChecks(" kia0104, ")})
+
+	// Only the globally suppressed check is moved, so the object is still invalid
+	assert.False(validations[ap1].Valid)
+	assert.Equal([]*IstioCheck{&noDest}, validations[ap1].Checks)
+	assert.Equal([]*IstioCheck{&notFound}, validations[ap1].Suppressed)
+
+	// No error remains once the object's own codes are suppressed
+	assert.True(validations[ap2].Valid)
+	assert.Empty(validations[ap2].Checks)
+	assert.Equal([]*IstioCheck{&notFound, &noDest}, validations[ap2].Suppressed)
+}
@@ -60,6 +60,7 @@ type Target struct {
 	Namespace string
 	Name      string
 	Kind      string // app | workload | service
+	Version   string // optional, app version for kind app
 }
 
 type MetricsStatsQuery struct {
@@ -107,6 +108,9 @@ func (q *MetricsStatsQuery) GenKey() string {
 	return fmt.Sprintf("%s:%s:%s:%s", q.Target.GenKey(), peer, q.Direction, q.RawInterval)
 }
 func (t *Target) GenKey() string {
+	if t.Version != "" {
+		return fmt.Sprintf("%s:%s:%s:%s", t.Namespace, t.Kind, t.Name, t.Version)
+	}
 	return fmt.Sprintf("%s:%s:%s", t.Namespace, t.Kind, t.Name)
 }
 
@@ -0,0 +1,117 @@
+package models
+
+import (
+	"time"
+
+	"github.com/kiali/kiali/util"
+)
+
+// Metrics compared between two targets
+const (
+	ComparisonRequestRate = "requestRate"
+	ComparisonErrorRatio  = "errorRatio"
+	ComparisonLatency     = "latency"
+)
+
+// Verdicts of a comparison
+const (
+	// The candidate is not significantly worse than the baseline
+	ComparisonPass = "pass"
+	// The candidate is significantly worse than the baseline, beyond the tolerance
+	ComparisonFail = "fail"
+	// Not enough requests to compare the targets
+	ComparisonNoData = "nodata"
+)
+
+// MetricsComparisonQuery compares the requests of a candidate (i.e. reviews-v2) to the ones of a baseline (i.e. reviews-v1)
+// over a window ending at the query time
+type MetricsComparisonQuery struct {
+	Baseline     Target
+	Candidate    Target
+	RawQueryTime int64     `json:"queryTime"`
+	QueryTime    time.Time `json:"-"`
+	RawInterval  string    `json:"interval"`
+	Interval     string    `json:"-"`
+	Direction    string    // outbound | inbound
+	Quantiles    []string
+	// Significance level of the statistical tests, 0.05 by default
+	Alpha float64
+	// Relative degradation of the candidate tolerated even when it is significant, 0.1 (10%) by default
+	Tolerance float64
+}
+
+// FillDefaults fills the optional parameters of the comparison
+func (q *MetricsComparisonQuery) FillDefaults() {
+	if len(q.Quantiles) == 0 {
+		q.Quantiles = []string{"0.5", "0.95", "0.99"}
+	}
+	if q.Alpha <= 0 {
+		q.Alpha = 0.05
+	}
+	if q.Tolerance <= 0 {
+		q.Tolerance = 0.1
+	}
+}
+
+// StatsQueries returns the stats queries of the baseline and of the candidate
+func (q *MetricsComparisonQuery) StatsQueries() []MetricsStatsQuery {
+	queries := []MetricsStatsQuery{}
+	for _, target := range []Target{q.Baseline, q.Candidate} {
+		queries = append(queries, MetricsStatsQuery{
+			Target:       target,
+			RawQueryTime: q.RawQueryTime,
+			QueryTime:    q.QueryTime,
+			RawInterval:  q.RawInterval,
+			Interval:     q.Interval,
+			Direction:    q.Direction,
+			Avg:          true,
+			Quantiles:    q.Quantiles,
+		})
+	}
+	return queries
+}
+
+// Validate checks the targets, and that the candidate is not the baseline
+func (q *MetricsComparisonQuery) Validate() *util.Errors {
+	var errs util.Errors
+	for _, sq := range q.StatsQueries() {
+		if err := sq.Validate(); err != nil {
+			errs.Merge(err)
+		}
+	}
+	if q.Baseline == q.Candidate {
+		errs.AddString("bad request: 'baseline' and 'candidate' must be different targets")
+	}
+	if q.Alpha >= 1 {
+		errs.AddString("bad request: 'alpha' must be lower than 1")
+	}
+	q.QueryTime = time.Unix(q.RawQueryTime, 0)
+	return errs.OrNil()
+}
+
+// MetricComparison holds a metric of the baseline and of the candidate side by side
+type MetricComparison struct {
+	// requestRate (rps), errorRatio (0 to 1) or latency (ms)
+	Name string `json:"name"`
+	// Latency statistic: avg or a quantile
+	Stat string `json:"stat,omitempty"`
+	// Value over the window, empty without requests
+	Baseline  *float64 `json:"baseline"`
+	Candidate *float64 `json:"candidate"`
+	// P-value of the one-sided Mann-Whitney U test of the candidate being higher than the baseline, on the samples of the window.
+	// Empty when there are not enough samples.
+	PValue *float64 `json:"pValue"`
+	// pass, fail or nodata. The request rate only tells whether both targets received requests,
+	// a canary usually receiving a fraction of the traffic.
+	Verdict string `json:"verdict"`
+}
+
+// MetricsComparison is the result of a metrics comparison query
+type MetricsComparison struct {
+	Baseline  Target             `json:"baseline"`
+	Candidate Target             `json:"candidate"`
+	Interval  string             `json:"interval"`
+	Metrics   []MetricComparison `json:"metrics"`
+	// fail when any metric fails, nodata when any has no data, pass otherwise
+	Verdict string `json:"verdict"`
+}
@@ -27,6 +27,7 @@ type Pod struct {
 	VersionLabel        bool              `json:"versionLabel"`
 	Annotations         map[string]string `json:"annotations"`
 	ProxyStatus         *ProxyStatus      `json:"proxyStatus"`
+	ServiceAccountName  string            `json:"serviceAccountName"`
 }
 
 // Reference holds some information on the pod creator
@@ -35,10 +36,17 @@ type Reference struct {
 	Kind string `json:"kind"`
 }
 
-// ContainerInfo holds container name and image
+// ContainerInfo holds container name and image, and the state of the container
 type ContainerInfo struct {
-	Name  string `json:"name"`
-	Image string `json:"image"`
+	Name         string `json:"name"`
+	Image        string `json:"image"`
+	IsReady      bool   `json:"isReady"`
+	RestartCount int32  `json:"restartCount"`
+	// Reason of a waiting container, i.e. CrashLoopBackOff
+	WaitingReason string `json:"waitingReason,omitempty"`
+	// Reason and time of the last termination of the container, i.e. OOMKilled
+	TerminationReason string `json:"terminationReason,omitempty"`
+	TerminatedAt      string `json:"terminatedAt,omitempty"`
 }
 
 // Parse extracts desired information from k8s []Pod info
@@ -66,6 +74,7 @@ func (pod *Pod) Parse(p *core_v1.Pod) {
 	pod.Labels = p.Labels
 	pod.Annotations = p.Annotations
 	pod.CreatedAt = formatTime(p.CreationTimestamp.Time)
+	pod.ServiceAccountName = p.Spec.ServiceAccountName
 	for _, ref := range p.OwnerReferences {
 		pod.CreatedBy = append(pod.CreatedBy, Reference{
 			Name: ref.Name,
@@ -105,6 +114,9 @@ func (pod *Pod) Parse(p *core_v1.Pod) {
 		}
 		pod.Containers = append(pod.Containers, &container)
 	}
+	parseContainerStatuses(pod.Containers, p.Status.ContainerStatuses)
+	parseContainerStatuses(pod.IstioContainers, p.Status.ContainerStatuses)
+	parseContainerStatuses(pod.IstioInitContainers, p.Status.InitContainerStatuses)
 	pod.Status = string(p.Status.Phase)
 	pod.StatusMessage = string(p.Status.Message)
 	pod.StatusReason = string(p.Status.Reason)
@@ -112,6 +124,29 @@ func (pod *Pod) Parse(p *core_v1.Pod) {
 	_, pod.VersionLabel = p.Labels[conf.IstioLabels.VersionLabelName]
 }
 
+func parseContainerStatuses(containers []*ContainerInfo, statuses []core_v1.ContainerStatus) {
+	for _, container := range containers {
+		for _, status := range statuses {
+			if status.Name != container.Name {
+				continue
+			}
+			container.IsReady = status.Ready
+			container.RestartCount = status.RestartCount
+			if status.State.Waiting != nil {
+				container.WaitingReason = status.State.Waiting.Reason
+			}
+			// The current state prevails over the last one, for containers which are not restarted
+			if terminated := status.State.Terminated; terminated != nil {
+				container.TerminationReason = terminated.Reason
+				container.TerminatedAt = formatTime(terminated.FinishedAt.Time)
+			} else if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				container.TerminationReason = terminated.Reason
+				container.TerminatedAt = formatTime(terminated.FinishedAt.Time)
+			}
+		}
+	}
+}
+
 func lookupImage(containerName string, containers []core_v1.Container) string {
 	for _, c := range containers {
 		if c.Name == containerName {
@@ -133,6 +168,23 @@ func (pods Pods) HasIstioSidecar() bool {
 	return true
 }
 
+// ServiceAccounts returns the distinct ServiceAccount names used by the pods
+func (pods Pods) ServiceAccounts() []string {
+	names := make([]string, 0)
+	found := map[string]bool{}
+	for _, pod := range pods {
+		name := pod.ServiceAccountName
+		if name == "" {
+			name = "default"
+		}
+		if !found[name] {
+			found[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // HasIstioSidecar returns true if the pod has an Isio proxy sidecar
 func (pod Pod) HasIstioSidecar() bool {
 	return len(pod.IstioContainers) > 0
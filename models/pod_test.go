@@ -182,3 +182,116 @@ func TestSyncedPodProxiesCount(t *testing.T) {
 	pods = append(pods, pod)
 	assert.Equal(int32(-1), pods.SyncedPodProxiesCount())
 }
+
+func TestPodsServiceAccounts(t *testing.T) {
+	assert := assert.New(t)
+
+	pods := Pods{
+		&Pod{Name: "reviews-v1-1", ServiceAccountName: "bookinfo-reviews"},
+		&Pod{Name: "reviews-v1-2", ServiceAccountName: "bookinfo-reviews"},
+		&Pod{Name: "reviews-v1-3"},
+	}
+
+	assert.Equal([]string{"bookinfo-reviews", "default"}, pods.ServiceAccounts())
+	assert.Empty(Pods{}.ServiceAccounts())
+}
+
+func TestPodParsingContainerStatuses(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	t1, _ := time.Parse(time.RFC822Z, "08 Mar 18 17:44 +0300")
+	k8sPod := core_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "details-v1-3618568057-dnkjp",
+			Annotations: map[string]string{"sidecar.istio.io/status": "{\"containers\":[\"istio-proxy\"]}"}},
+		Spec: core_v1.PodSpec{
+			Containers: []core_v1.Container{
+				{Name: "details", Image: "whatever"},
+				{Name: "istio-proxy", Image: "docker.io/istio/proxy:0.7.1"},
+			},
+		},
+		Status: core_v1.PodStatus{
+			Phase: core_v1.PodRunning,
+			ContainerStatuses: []core_v1.ContainerStatus{
+				{
+					Name:                 "details",
+					RestartCount:         3,
+					State:                core_v1.ContainerState{Waiting: &core_v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: core_v1.ContainerState{Terminated: &core_v1.ContainerStateTerminated{Reason: "OOMKilled", FinishedAt: meta_v1.NewTime(t1)}},
+				},
+				{
+					Name:  "istio-proxy",
+					Ready: true,
+					State: core_v1.ContainerState{Running: &core_v1.ContainerStateRunning{}},
+				},
+			},
+		}}
+
+	pod := Pod{}
+	pod.Parse(&k8sPod)
+	assert.Len(pod.Containers, 1)
+	assert.False(pod.Containers[0].IsReady)
+	assert.Equal(int32(3), pod.Containers[0].RestartCount)
+	assert.Equal("CrashLoopBackOff", pod.Containers[0].WaitingReason)
+	assert.Equal("OOMKilled", pod.Containers[0].TerminationReason)
+	assert.Equal("2018-03-08T14:44:00Z", pod.Containers[0].TerminatedAt)
+	assert.Len(pod.IstioContainers, 1)
+	assert.True(pod.IstioContainers[0].IsReady)
+	assert.Empty(pod.IstioContainers[0].TerminationReason)
+}
+
+func TestPodsHealth(t *testing.T) {
+	assert := assert.New(t)
+
+	since := time.Date(2018, 03, 8, 14, 0, 0, 0, time.UTC)
+	pods := Pods{
+		&Pod{
+			Name:            "reviews-v1-1",
+			Status:          "Running",
+			Containers:      []*ContainerInfo{{Name: "reviews", IsReady: true, RestartCount: 2, TerminationReason: "Error", TerminatedAt: "2018-03-08T13:00:00Z"}},
+			IstioContainers: []*ContainerInfo{{Name: "istio-proxy", IsReady: false, RestartCount: 1, TerminationReason: "Error", TerminatedAt: "2018-03-08T14:30:00Z"}},
+		},
+		&Pod{Name: "reviews-v1-2", Status: "Pending", CreatedAt: "2018-03-08T13:50:00Z"},
+		// Recently created, not stuck
+		&Pod{Name: "reviews-v1-3", Status: "Pending", CreatedAt: "2018-03-08T14:10:00Z"},
+	}
+
+	health := pods.CastPodsHealth(since, nil)
+	assert.Equal(int32(1), health.Pending)
+	assert.Equal(int32(0), health.NotReady)
+	assert.Equal(int32(1), health.ProxyNotReady)
+	assert.Equal(ContainersHealth{TotalRestarts: 2}, health.App)
+	assert.Equal(ContainersHealth{TotalRestarts: 1, RecentlyTerminated: 1, TerminationReasons: map[string]int32{"Error": 1}}, health.Proxy)
+	assert.Equal(DegradedStatus, health.Status)
+
+	pods[0].Containers[0].TerminatedAt = "2018-03-08T14:40:00Z"
+	pods[0].Containers[0].TerminationReason = "OOMKilled"
+	health = pods.CastPodsHealth(since, nil)
+	assert.Equal(map[string]int32{"OOMKilled": 1}, health.App.TerminationReasons)
+	assert.Equal(FailureStatus, health.Status)
+
+	// Completed containers, such as the ones of Job pods, don't degrade the health
+	completed := Pods{
+		&Pod{Name: "job-1", Status: "Succeeded", Containers: []*ContainerInfo{{Name: "job", TerminationReason: "Completed", TerminatedAt: "2018-03-08T14:40:00Z"}}},
+	}
+	health = completed.CastPodsHealth(since, nil)
+	assert.Equal(int32(0), health.App.RecentlyTerminated)
+	assert.Equal(HealthyStatus, health.Status)
+
+	// Restarts in the rate interval, when known, degrade the health
+	restarted := Pods{
+		&Pod{Name: "ratings-v1-1", Status: "Running", Containers: []*ContainerInfo{{Name: "ratings", IsReady: true, RestartCount: 4}}},
+	}
+	health = restarted.CastPodsHealth(since, nil)
+	assert.Nil(health.App.Restarts)
+	assert.Equal(HealthyStatus, health.Status)
+	health = restarted.CastPodsHealth(since, ContainerRestarts{})
+	assert.Equal(int32(0), *health.App.Restarts)
+	assert.Equal(HealthyStatus, health.Status)
+	health = restarted.CastPodsHealth(since, ContainerRestarts{"ratings-v1-1": {"ratings": 1}})
+	assert.Equal(int32(1), *health.App.Restarts)
+	assert.Equal(int32(4), health.App.TotalRestarts)
+	assert.Equal(DegradedStatus, health.Status)
+
+	assert.Nil(Pods{}.CastPodsHealth(since, nil))
+}
@@ -16,6 +16,16 @@ type Sidecar struct {
 	} `json:"spec"`
 }
 
+// OutboundHost is a destination host observed in the outbound traffic of a workload
+type OutboundHost struct {
+	// Host as reported in the destination_service telemetry label
+	// example: reviews.bookinfo.svc.cluster.local
+	Host string `json:"host"`
+	// Namespace of the Service or ServiceEntry defining the host
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+}
+
 func (scs *Sidecars) Parse(sidecars []kubernetes.IstioObject) {
 	for _, sc := range sidecars {
 		sidecar := Sidecar{}
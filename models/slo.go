@@ -0,0 +1,94 @@
+package models
+
+// Status of a service level objective
+const (
+	// The error budget is consumed slower than the burn rate thresholds
+	SLOHealthy = "healthy"
+	// Some burn rate exceeds its threshold on both its long and short windows
+	SLOBurning = "burning"
+	// The error budget of the window is exhausted
+	SLOBreached = "breached"
+	// No request was received in the window
+	SLONoData = "nodata"
+	// The objective is not valid, so it is not evaluated
+	SLOInvalid = "invalid"
+)
+
+// SLOStatus is the evaluation of a service level objective over its rolling window
+// swagger:model SLOStatus
+type SLOStatus struct {
+	// Identifier of the objective
+	// required: true
+	// example: availability
+	Id string `json:"id"`
+
+	// Type of the objective: availability or latency
+	// required: true
+	// example: availability
+	Type string `json:"type"`
+
+	// Percentage of good requests
+	// required: true
+	// example: 99.9
+	Objective float64 `json:"objective"`
+
+	// Milliseconds under which a request is good, for latency objectives
+	// example: 250
+	LatencyThreshold float64 `json:"latencyThreshold,omitempty"`
+
+	// Rolling window of the objective
+	// required: true
+	// example: 30d
+	Window string `json:"window"`
+
+	// Ratio of good requests over the window, missing without requests
+	// example: 0.9995
+	SLI *float64 `json:"sli,omitempty"`
+
+	// Ratio of the error budget left over the window: 1 when untouched, 0 or less when exhausted.
+	// Missing without requests.
+	// example: 0.5
+	ErrorBudgetRemaining *float64 `json:"errorBudgetRemaining,omitempty"`
+
+	// Burn rates over the multiple window pairs shorter than the objective window
+	// required: true
+	BurnRates []BurnRate `json:"burnRates"`
+
+	// Status of the objective: healthy, burning, breached, nodata or invalid
+	// required: true
+	// example: healthy
+	Status string `json:"status"`
+
+	// Why the objective is invalid, or may have no data
+	// example: objective 100 is not strictly between 0 and 100
+	Reason string `json:"reason,omitempty"`
+}
+
+// BurnRate compares how fast the error budget is consumed over a long and a short window, relative to the rate
+// exhausting it exactly at the end of the objective window. A burn rate of 1 consumes the whole budget in the window.
+type BurnRate struct {
+	// required: true
+	// example: 1h
+	LongWindow string `json:"longWindow"`
+
+	// required: true
+	// example: 5m
+	ShortWindow string `json:"shortWindow"`
+
+	// Burn rate over the long window
+	// required: true
+	Long float64 `json:"long"`
+
+	// Burn rate over the short window
+	// required: true
+	Short float64 `json:"short"`
+
+	// Burn rate consuming a fixed share of the error budget over the long window
+	// required: true
+	// example: 14.4
+	Threshold float64 `json:"threshold"`
+
+	// Both the long and the short burn rates exceed the threshold
+	// required: true
+	Alerting bool `json:"alerting"`
+}
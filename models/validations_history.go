@@ -0,0 +1,129 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// ValidationsHistory keeps track of the Istio config validations of a namespace over time
+// swagger:model ValidationsHistory
+type ValidationsHistory struct {
+	// Namespace of the validated objects
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// Number of checks found by severity on each validation run, oldest first
+	// required: true
+	Trend []ValidationsTrendPoint `json:"trend"`
+
+	// Objects that became invalid, oldest first
+	// required: true
+	Regressions []ValidationRegression `json:"regressions"`
+}
+
+// ValidationsTrendPoint is the number of checks found by severity on a validation run
+type ValidationsTrendPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Errors    int       `json:"errors"`
+	Warnings  int       `json:"warnings"`
+}
+
+// ValidationRegression describes an object that became invalid and the change that introduced it
+type ValidationRegression struct {
+	IstioValidationKey
+
+	// When the object was found invalid for the first time
+	// required: true
+	Since time.Time `json:"since"`
+
+	// When the object was found valid again, or deleted. Nil while the object is still invalid
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+
+	// ResourceVersion of the object when it was found invalid
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+
+	// Generation of the object when it was found invalid
+	Generation int64 `json:"generation,omitempty"`
+
+	// Checks found when the object became invalid
+	Checks []*IstioCheck `json:"checks"`
+}
+
+// Add records the result of a validation run at the given time. Objects of other namespaces are ignored.
+// The objectVersion function provides the resourceVersion and generation of the objects that became invalid.
+// Only the latest maxEntries trend points and regressions are kept.
+func (vh *ValidationsHistory) Add(validations IstioValidations, timestamp time.Time, maxEntries int, objectVersion func(IstioValidationKey) (string, int64)) {
+	point := ValidationsTrendPoint{Timestamp: timestamp}
+	invalid := map[IstioValidationKey]*IstioValidation{}
+	for key, validation := range validations {
+		if key.Namespace != vh.Namespace {
+			continue
+		}
+		for _, check := range validation.Checks {
+			switch check.Severity {
+			case ErrorSeverity:
+				point.Errors++
+			case WarningSeverity:
+				point.Warnings++
+			}
+		}
+		if !validation.Valid {
+			invalid[key] = validation
+		}
+	}
+	vh.Trend = append(vh.Trend, point)
+
+	// Close the regressions that are fixed and skip the ones already open
+	for i := range vh.Regressions {
+		regression := &vh.Regressions[i]
+		if regression.ResolvedAt != nil {
+			continue
+		}
+		if _, found := invalid[regression.IstioValidationKey]; found {
+			delete(invalid, regression.IstioValidationKey)
+		} else {
+			resolvedAt := timestamp
+			regression.ResolvedAt = &resolvedAt
+		}
+	}
+
+	keys := make([]IstioValidationKey, 0, len(invalid))
+	for key := range invalid {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].ObjectType != keys[j].ObjectType {
+			return keys[i].ObjectType < keys[j].ObjectType
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	for _, key := range keys {
+		validation := invalid[key]
+		regression := ValidationRegression{
+			IstioValidationKey: key,
+			Since:              timestamp,
+			Checks:             validation.Checks,
+		}
+		if objectVersion != nil {
+			regression.ResourceVersion, regression.Generation = objectVersion(key)
+		}
+		vh.Regressions = append(vh.Regressions, regression)
+	}
+
+	if maxEntries > 0 {
+		if len(vh.Trend) > maxEntries {
+			vh.Trend = vh.Trend[len(vh.Trend)-maxEntries:]
+		}
+		// Resolved regressions are discarded first, so open ones keep their original date
+		for i := 0; i < len(vh.Regressions) && len(vh.Regressions) > maxEntries; {
+			if vh.Regressions[i].ResolvedAt != nil {
+				vh.Regressions = append(vh.Regressions[:i], vh.Regressions[i+1:]...)
+			} else {
+				i++
+			}
+		}
+		if len(vh.Regressions) > maxEntries {
+			vh.Regressions = vh.Regressions[len(vh.Regressions)-maxEntries:]
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationsHistoryAdd(t *testing.T) {
+	assert := assert.New(t)
+
+	vsKey := IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}
+	drKey := IstioValidationKey{ObjectType: "destinationrule", Name: "reviews", Namespace: "bookinfo"}
+	otherKey := IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "other"}
+
+	errorCheck := Build("virtualservices.nohost.hostnotfound", "spec/http[0]/route[0]/destination/host")
+	warningCheck := Build("destinationrules.multimatch", "spec/host")
+
+	versions := func(key IstioValidationKey) (string, int64) { return "1234", 2 }
+	history := ValidationsHistory{Namespace: "bookinfo"}
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// A warning doesn't make the object invalid
+	history.Add(IstioValidations{
+		vsKey:    &IstioValidation{Name: "reviews", ObjectType: "virtualservice", Valid: true},
+		drKey:    &IstioValidation{Name: "reviews", ObjectType: "destinationrule", Valid: true, Checks: []*IstioCheck{&warningCheck}},
+		otherKey: &IstioValidation{Name: "reviews", ObjectType: "virtualservice", Valid: false, Checks: []*IstioCheck{&errorCheck}},
+	}, t0, 10, versions)
+	assert.Len(history.Trend, 1)
+	assert.Equal(0, history.Trend[0].Errors)
+	assert.Equal(1, history.Trend[0].Warnings)
+	assert.Empty(history.Regressions)
+
+	// The VirtualService becomes invalid
+	t1 := t0.Add(time.Minute)
+	history.Add(IstioValidations{
+		vsKey: &IstioValidation{Name: "reviews", ObjectType: "virtualservice", Valid: false, Checks: []*IstioCheck{&errorCheck}},
+	}, t1, 10, versions)
+	assert.Len(history.Trend, 2)
+	assert.Equal(1, history.Trend[1].Errors)
+	assert.Len(history.Regressions, 1)
+	assert.Equal(vsKey, history.Regressions[0].IstioValidationKey)
+	assert.Equal(t1, history.Regressions[0].Since)
+	assert.Equal("1234", history.Regressions[0].ResourceVersion)
+	assert.Equal(int64(2), history.Regressions[0].Generation)
+	assert.Nil(history.Regressions[0].ResolvedAt)
+
+	// It remains invalid: the regression keeps its original date
+	history.Add(IstioValidations{
+		vsKey: &IstioValidation{Name: "reviews", ObjectType: "virtualservice", Valid: false, Checks: []*IstioCheck{&errorCheck}},
+	}, t1.Add(time.Minute), 10, versions)
+	assert.Len(history.Regressions, 1)
+	assert.Equal(t1, history.Regressions[0].Since)
+
+	// It is fixed
+	t3 := t1.Add(2 * time.Minute)
+	history.Add(IstioValidations{
+		vsKey: &IstioValidation{Name: "reviews", ObjectType: "virtualservice", Valid: true},
+	}, t3, 10, versions)
+	assert.Len(history.Regressions, 1)
+	assert.Equal(t3, *history.Regressions[0].ResolvedAt)
+}
+
+func TestValidationsHistoryMaxEntries(t *testing.T) {
+	assert := assert.New(t)
+
+	errorCheck := Build("virtualservices.nohost.hostnotfound", "spec/http[0]/route[0]/destination/host")
+	openKey := IstioValidationKey{ObjectType: "virtualservice", Name: "open", Namespace: "bookinfo"}
+	history := ValidationsHistory{Namespace: "bookinfo"}
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	history.Add(IstioValidations{
+		openKey: &IstioValidation{Name: "open", ObjectType: "virtualservice", Valid: false, Checks: []*IstioCheck{&errorCheck}},
+	}, t0, 2, nil)
+	for i, name := range []string{"a", "b", "c"} {
+		key := IstioValidationKey{ObjectType: "virtualservice", Name: name, Namespace: "bookinfo"}
+		// Each object is invalid during a single run
+		history.Add(IstioValidations{
+			openKey: &IstioValidation{Name: "open", ObjectType: "virtualservice", Valid: false, Checks: []*IstioCheck{&errorCheck}},
+			key:     &IstioValidation{Name: name, ObjectType: "virtualservice", Valid: false, Checks: []*IstioCheck{&errorCheck}},
+		}, t0.Add(time.Duration(i+1)*time.Minute), 2, nil)
+	}
+
+	assert.Len(history.Trend, 2)
+	assert.Len(history.Regressions, 2)
+	assert.Equal("open", history.Regressions[0].Name)
+	assert.Equal(t0, history.Regressions[0].Since)
+	assert.Equal("c", history.Regressions[1].Name)
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// MeshValidationsReport summarizes the Istio config validations of all the namespaces of the mesh
+// swagger:model MeshValidationsReport
+type MeshValidationsReport struct {
+	// When the report was generated
+	// required: true
+	Timestamp time.Time `json:"timestamp"`
+
+	// Number of checks found by severity on each namespace
+	// required: true
+	Namespaces []NamespaceValidationsReport `json:"namespaces"`
+
+	// Number of checks found grouped by check type and severity, most frequent first
+	// required: true
+	Checks []CheckTypeReport `json:"checks"`
+
+	// Objects with the most errors and warnings, most offending first
+	// required: true
+	TopObjects []ObjectValidationsReport `json:"topObjects"`
+}
+
+// NamespaceValidationsReport is the validation summary of a namespace
+type NamespaceValidationsReport struct {
+	IstioValidationSummary
+
+	// Namespace name
+	// required: true
+	// example: bookinfo
+	Namespace string `json:"namespace"`
+
+	// Error found while validating the namespace, if any
+	Error string `json:"error,omitempty"`
+}
+
+// CheckTypeReport is the number of times a check type is found across the mesh
+type CheckTypeReport struct {
+	// Code of the check
+	// required: true
+	// example: KIA0101
+	Code string `json:"code"`
+
+	// Description of the check
+	// required: true
+	// example: KIA0101 Namespace not found for this rule
+	Message string `json:"message"`
+
+	// Severity of the check
+	// required: true
+	// example: warning
+	Severity SeverityLevel `json:"severity"`
+
+	// Number of occurrences
+	// required: true
+	Count int `json:"count"`
+
+	// Number of distinct objects where the check was found
+	// required: true
+	Objects int `json:"objects"`
+}
+
+// ObjectValidationsReport describes the checks found on an Istio object
+type ObjectValidationsReport struct {
+	IstioValidationKey
+
+	// Owner of the object, taken from its annotations or labels, or from its namespace labels
+	// example: team-reviews
+	Owner string `json:"owner,omitempty"`
+
+	// Number of checks with error severity
+	// required: true
+	Errors int `json:"errors"`
+
+	// Number of checks with warning severity
+	// required: true
+	Warnings int `json:"warnings"`
+
+	// Checks found on the object
+	// required: true
+	Checks []*IstioCheck `json:"checks"`
+}
+
+// NewMeshValidationsReport builds the report from the validations found on each namespace.
+// Only the objects of the namespace itself are taken into account, since the validations of a namespace
+// may include objects of other namespaces referenced by them.
+// The top objects list is limited to maxObjects entries, or not limited when maxObjects is not positive.
+func NewMeshValidationsReport(namespaces []NamespaceValidationsReport, validations map[string]IstioValidations, maxObjects int, timestamp time.Time) MeshValidationsReport {
+	report := MeshValidationsReport{
+		Timestamp:  timestamp,
+		Namespaces: make([]NamespaceValidationsReport, 0, len(namespaces)),
+		Checks:     []CheckTypeReport{},
+		TopObjects: []ObjectValidationsReport{},
+	}
+
+	checkTypes := map[string]*CheckTypeReport{}
+	for _, ns := range namespaces {
+		nsReport := ns
+		for key, validation := range validations[ns.Namespace] {
+			if key.Namespace != ns.Namespace {
+				continue
+			}
+			nsReport.mergeSummaries(validation.Checks)
+
+			object := ObjectValidationsReport{IstioValidationKey: key, Checks: []*IstioCheck{}}
+			seen := map[string]bool{}
+			for _, check := range validation.Checks {
+				switch check.Severity {
+				case ErrorSeverity:
+					object.Errors++
+				case WarningSeverity:
+					object.Warnings++
+				default:
+					continue
+				}
+				object.Checks = append(object.Checks, check)
+
+				code := check.Code
+				typeKey := code + "/" + string(check.Severity)
+				if code == "" {
+					typeKey = check.Message + "/" + string(check.Severity)
+				}
+				checkType, found := checkTypes[typeKey]
+				if !found {
+					checkType = &CheckTypeReport{Code: code, Message: check.Message, Severity: check.Severity}
+					checkTypes[typeKey] = checkType
+				}
+				checkType.Count++
+				if !seen[typeKey] {
+					seen[typeKey] = true
+					checkType.Objects++
+				}
+			}
+			if len(object.Checks) > 0 {
+				report.TopObjects = append(report.TopObjects, object)
+			}
+		}
+		report.Namespaces = append(report.Namespaces, nsReport)
+	}
+
+	for _, checkType := range checkTypes {
+		report.Checks = append(report.Checks, *checkType)
+	}
+	sort.Slice(report.Checks, func(i, j int) bool {
+		ci, cj := report.Checks[i], report.Checks[j]
+		if ci.Count != cj.Count {
+			return ci.Count > cj.Count
+		}
+		if ci.Code != cj.Code {
+			return ci.Code < cj.Code
+		}
+		return ci.Severity < cj.Severity
+	})
+
+	sort.Slice(report.TopObjects, func(i, j int) bool {
+		oi, oj := report.TopObjects[i], report.TopObjects[j]
+		if oi.Errors != oj.Errors {
+			return oi.Errors > oj.Errors
+		}
+		if oi.Warnings != oj.Warnings {
+			return oi.Warnings > oj.Warnings
+		}
+		if oi.Namespace != oj.Namespace {
+			return oi.Namespace < oj.Namespace
+		}
+		if oi.ObjectType != oj.ObjectType {
+			return oi.ObjectType < oj.ObjectType
+		}
+		return oi.Name < oj.Name
+	})
+	if maxObjects > 0 && len(report.TopObjects) > maxObjects {
+		report.TopObjects = report.TopObjects[:maxObjects]
+	}
+
+	return report
+}
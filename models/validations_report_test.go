@@ -0,0 +1,61 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMeshValidationsReport(t *testing.T) {
+	assert := assert.New(t)
+
+	notFound := Build("authorizationpolicy.source.namespacenotfound", "spec/rules[0]/from[0]")
+	wrongMethod := Build("authorizationpolicy.to.wrongmethod", "spec/rules[0]/to[0]")
+	noDest := Build("authorizationpolicy.nodest.matchingregistry", "spec/rules[0]/to[0]")
+	info := IstioCheck{Message: "Informative", Severity: Unknown}
+
+	validations := map[string]IstioValidations{
+		"bookinfo": {
+			BuildKey("authorizationpolicy", "ap1", "bookinfo"): {Name: "ap1", ObjectType: "authorizationpolicy", Checks: []*IstioCheck{&notFound, &wrongMethod}},
+			BuildKey("authorizationpolicy", "ap2", "bookinfo"): {Name: "ap2", ObjectType: "authorizationpolicy", Checks: []*IstioCheck{&noDest, &notFound, &notFound}},
+			BuildKey("authorizationpolicy", "ap3", "bookinfo"): {Name: "ap3", ObjectType: "authorizationpolicy", Checks: []*IstioCheck{&info}, Valid: true},
+			// Objects from other namespaces are reported in their own namespace
+			BuildKey("authorizationpolicy", "ap4", "travel"): {Name: "ap4", ObjectType: "authorizationpolicy", Checks: []*IstioCheck{&noDest}},
+		},
+		"travel": {
+			BuildKey("authorizationpolicy", "ap5", "travel"): {Name: "ap5", ObjectType: "authorizationpolicy", Checks: []*IstioCheck{&wrongMethod}},
+		},
+	}
+	namespaces := []NamespaceValidationsReport{{Namespace: "bookinfo"}, {Namespace: "travel"}, {Namespace: "forbidden", Error: "forbidden"}}
+	now := time.Now()
+
+	report := NewMeshValidationsReport(namespaces, validations, 2, now)
+	assert.Equal(now, report.Timestamp)
+
+	assert.Len(report.Namespaces, 3)
+	assert.Equal("bookinfo", report.Namespaces[0].Namespace)
+	assert.Equal(3, report.Namespaces[0].ObjectCount)
+	assert.Equal(1, report.Namespaces[0].Errors)
+	assert.Equal(4, report.Namespaces[0].Warnings)
+	assert.Equal(1, report.Namespaces[1].ObjectCount)
+	assert.Equal(1, report.Namespaces[1].Warnings)
+	assert.Equal("forbidden", report.Namespaces[2].Error)
+	assert.Zero(report.Namespaces[2].ObjectCount)
+
+	assert.Len(report.Checks, 3)
+	assert.Equal(CheckTypeReport{Code: "KIA0101", Message: notFound.Message, Severity: WarningSeverity, Count: 3, Objects: 2}, report.Checks[0])
+	assert.Equal(CheckTypeReport{Code: "KIA0102", Message: wrongMethod.Message, Severity: WarningSeverity, Count: 2, Objects: 2}, report.Checks[1])
+	assert.Equal(CheckTypeReport{Code: "KIA0104", Message: noDest.Message, Severity: ErrorSeverity, Count: 1, Objects: 1}, report.Checks[2])
+
+	assert.Len(report.TopObjects, 2)
+	assert.Equal("ap2", report.TopObjects[0].Name)
+	assert.Equal(1, report.TopObjects[0].Errors)
+	assert.Equal(2, report.TopObjects[0].Warnings)
+	assert.Len(report.TopObjects[0].Checks, 3)
+	assert.Equal("ap1", report.TopObjects[1].Name)
+
+	report = NewMeshValidationsReport(namespaces, validations, 0, now)
+	assert.Len(report.TopObjects, 3)
+	assert.Equal("ap5", report.TopObjects[2].Name)
+}
@@ -78,6 +78,10 @@ type WorkloadListItem struct {
 	// required: true
 	// example: 1
 	PodCount int `json:"podCount"`
+
+	// Names of the ServiceAccounts used by the workload pods
+	// example: bookinfo-reviews
+	ServiceAccountNames []string `json:"serviceAccountNames"`
 }
 
 type WorkloadOverviews []*WorkloadListItem
@@ -112,6 +116,9 @@ type Workload struct {
 
 	// Additional details to display, such as configured annotations
 	AdditionalDetails []AdditionalItem `json:"additionalDetails"`
+
+	// ServiceAccount of the pod template of the controller, used even when there is no pod
+	templateServiceAccountName string
 }
 
 type Workloads []*Workload
@@ -125,6 +132,7 @@ func (workload *WorkloadListItem) ParseWorkload(w *Workload) {
 	workload.IstioSidecar = w.HasIstioSidecar()
 	workload.Labels = w.Labels
 	workload.PodCount = len(w.Pods)
+	workload.ServiceAccountNames = w.ServiceAccounts()
 	workload.AdditionalDetailSample = w.AdditionalDetailSample
 
 	/** Check the labels app and version required by Istio in template Pods*/
@@ -162,6 +170,7 @@ func (workload *Workload) parseObjectMeta(meta *meta_v1.ObjectMeta, tplMeta *met
 func (workload *Workload) ParseDeployment(d *apps_v1.Deployment) {
 	workload.Type = "Deployment"
 	workload.parseObjectMeta(&d.ObjectMeta, &d.Spec.Template.ObjectMeta)
+	workload.setTemplateServiceAccountName(d.Spec.Template.Spec.ServiceAccountName)
 	if d.Spec.Replicas != nil {
 		workload.DesiredReplicas = *d.Spec.Replicas
 	}
@@ -172,6 +181,7 @@ func (workload *Workload) ParseDeployment(d *apps_v1.Deployment) {
 func (workload *Workload) ParseReplicaSet(r *apps_v1.ReplicaSet) {
 	workload.Type = "ReplicaSet"
 	workload.parseObjectMeta(&r.ObjectMeta, &r.Spec.Template.ObjectMeta)
+	workload.setTemplateServiceAccountName(r.Spec.Template.Spec.ServiceAccountName)
 	if r.Spec.Replicas != nil {
 		workload.DesiredReplicas = *r.Spec.Replicas
 	}
@@ -182,6 +192,7 @@ func (workload *Workload) ParseReplicaSet(r *apps_v1.ReplicaSet) {
 func (workload *Workload) ParseReplicationController(r *core_v1.ReplicationController) {
 	workload.Type = "ReplicationController"
 	workload.parseObjectMeta(&r.ObjectMeta, &r.Spec.Template.ObjectMeta)
+	workload.setTemplateServiceAccountName(r.Spec.Template.Spec.ServiceAccountName)
 	if r.Spec.Replicas != nil {
 		workload.DesiredReplicas = *r.Spec.Replicas
 	}
@@ -192,6 +203,7 @@ func (workload *Workload) ParseReplicationController(r *core_v1.ReplicationContr
 func (workload *Workload) ParseDeploymentConfig(dc *osapps_v1.DeploymentConfig) {
 	workload.Type = "DeploymentConfig"
 	workload.parseObjectMeta(&dc.ObjectMeta, &dc.Spec.Template.ObjectMeta)
+	workload.setTemplateServiceAccountName(dc.Spec.Template.Spec.ServiceAccountName)
 	workload.DesiredReplicas = dc.Spec.Replicas
 	workload.CurrentReplicas = dc.Status.Replicas
 	workload.AvailableReplicas = dc.Status.AvailableReplicas
@@ -200,6 +212,7 @@ func (workload *Workload) ParseDeploymentConfig(dc *osapps_v1.DeploymentConfig)
 func (workload *Workload) ParseStatefulSet(s *apps_v1.StatefulSet) {
 	workload.Type = "StatefulSet"
 	workload.parseObjectMeta(&s.ObjectMeta, &s.Spec.Template.ObjectMeta)
+	workload.setTemplateServiceAccountName(s.Spec.Template.Spec.ServiceAccountName)
 	if s.Spec.Replicas != nil {
 		workload.DesiredReplicas = *s.Spec.Replicas
 	}
@@ -234,6 +247,7 @@ func (workload *Workload) ParsePod(pod *core_v1.Pod) {
 func (workload *Workload) ParseJob(job *batch_v1.Job) {
 	workload.Type = "Job"
 	workload.parseObjectMeta(&job.ObjectMeta, &job.ObjectMeta)
+	workload.setTemplateServiceAccountName(job.Spec.Template.Spec.ServiceAccountName)
 	// Job controller does not use replica parameters as other controllers
 	// this is a workaround to use same values from Workload perspective
 	workload.DesiredReplicas = job.Status.Active + job.Status.Succeeded + job.Status.Failed
@@ -244,6 +258,7 @@ func (workload *Workload) ParseJob(job *batch_v1.Job) {
 func (workload *Workload) ParseCronJob(cnjb *batch_v1beta1.CronJob) {
 	workload.Type = "CronJob"
 	workload.parseObjectMeta(&cnjb.ObjectMeta, &cnjb.ObjectMeta)
+	workload.setTemplateServiceAccountName(cnjb.Spec.JobTemplate.Spec.Template.Spec.ServiceAccountName)
 
 	// We don't have the information of this controller
 	// We will infer the number of replicas as the number of pods without succeed state
@@ -265,6 +280,28 @@ func (workload *Workload) ParseCronJob(cnjb *batch_v1beta1.CronJob) {
 	workload.AvailableReplicas = podAvailableReplicas
 }
 
+func (workload *Workload) setTemplateServiceAccountName(name string) {
+	if name == "" {
+		name = "default"
+	}
+	workload.templateServiceAccountName = name
+}
+
+// ServiceAccounts returns the names of the ServiceAccounts used by the workload pods and by the pod template of its
+// controller, so that a workload scaled down to zero still has its ServiceAccount
+func (workload *Workload) ServiceAccounts() []string {
+	names := workload.Pods.ServiceAccounts()
+	if workload.templateServiceAccountName == "" {
+		return names
+	}
+	for _, name := range names {
+		if name == workload.templateServiceAccountName {
+			return names
+		}
+	}
+	return append(names, workload.templateServiceAccountName)
+}
+
 func (workload *Workload) ParsePods(controllerName string, controllerType string, pods []core_v1.Pod) {
 	conf := config.Get()
 	workload.Name = controllerName
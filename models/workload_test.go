@@ -320,3 +320,28 @@ func fakePod() *core_v1.Pod {
 		},
 	}
 }
+
+func TestWorkloadServiceAccounts(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	replicas := int32(0)
+	w := Workload{}
+	w.ParseDeployment(&apps_v1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1"},
+		Spec: apps_v1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: core_v1.PodTemplateSpec{Spec: core_v1.PodSpec{ServiceAccountName: "bookinfo-reviews"}},
+		},
+	})
+
+	// Scaled down to zero
+	assert.Equal([]string{"bookinfo-reviews"}, w.ServiceAccounts())
+
+	w.Pods = Pods{&Pod{Name: "reviews-v1-1", ServiceAccountName: "bookinfo-reviews"}, &Pod{Name: "reviews-v1-2"}}
+	assert.Equal([]string{"bookinfo-reviews", "default"}, w.ServiceAccounts())
+
+	item := WorkloadListItem{}
+	item.ParseWorkload(&w)
+	assert.Equal([]string{"bookinfo-reviews", "default"}, item.ServiceAccountNames)
+}
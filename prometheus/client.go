@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"time"
 
@@ -21,14 +22,20 @@ import (
 type ClientInterface interface {
 	FetchHistogramRange(metricName, labels, grouping string, q *RangeQuery) Histogram
 	FetchHistogramValues(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error)
+	FetchQueryRange(query string, q *RangeQuery) Metric
 	FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric
 	FetchRateRange(metricName string, labels []string, grouping string, q *RangeQuery) Metric
 	GetAllRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetAppRequestRates(namespace, app, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetConfiguration() (prom_v1.ConfigResult, error)
+	GetContainerRestarts(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetFlags() (prom_v1.FlagsResult, error)
+	GetLabelValuesForSeries(label string, selectors []string, start, end time.Time) ([]string, error)
 	GetNamespaceServicesRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
+	GetRequestLatencyQuantile(labels, groupBy string, quantile float64, ratesInterval string, queryTime time.Time) (model.Vector, error)
+	GetRequestSignals(labels, groupBy string, quantile float64, ratesInterval, offset string, queryTime time.Time) (RequestSignals, error)
 	GetServiceRequestRates(namespace, service, ratesInterval string, queryTime time.Time) (model.Vector, error)
+	GetSLOErrorRatios(labels string, latencyThreshold float64, windows []string, queryTime time.Time) (map[string]float64, error)
 	GetWorkloadRequestRates(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetMetricsForLabels(labels []string) ([]string, error)
 }
@@ -43,6 +50,7 @@ type Client struct {
 
 var once sync.Once
 var promCache PromCache
+var queryCache *QueryCache
 
 func initPromCache() {
 	if config.Get().ExternalServices.Prometheus.CacheEnabled {
@@ -51,6 +59,12 @@ func initPromCache() {
 	} else {
 		log.Infof("[Prom Cache] Disabled")
 	}
+	if config.Get().ExternalServices.Prometheus.QueryCacheEnabled {
+		log.Infof("[Prom Query Cache] Enabled")
+		queryCache = NewQueryCache()
+	} else {
+		log.Infof("[Prom Query Cache] Disabled")
+	}
 }
 
 // NewClient creates a new client to the Prometheus API.
@@ -91,7 +105,11 @@ func NewClientForConfig(cfg config.PrometheusConfig) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
-	client := Client{p8s: p8s, api: prom_v1.NewAPI(p8s)}
+	promAPI := prom_v1.NewAPI(p8s)
+	if queryCache != nil {
+		promAPI = queryCache.Wrap(promAPI, cfg.URL, auth)
+	}
+	client := Client{p8s: p8s, api: promAPI}
 	return &client, nil
 }
 
@@ -210,6 +228,43 @@ func (in *Client) GetWorkloadRequestRates(namespace, workload, ratesInterval str
 	return inResult, outResult, nil
 }
 
+// GetRequestLatencyQuantile queries Prometheus to fetch the latency, in milliseconds, of the requests matching the labels
+// at the given quantile. The result has a sample per value of the groupBy label, or a single one when groupBy is empty.
+// Samples are NaN when there was no request in the interval.
+func (in *Client) GetRequestLatencyQuantile(labels, groupBy string, quantile float64, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetRequestLatencyQuantile [labels: %s] [groupBy: %s] [quantile: %v] [ratesInterval: %s] [queryTime: %s]", labels, groupBy, quantile, ratesInterval, queryTime.String())
+	return getRequestLatencyQuantile(in.api, labels, groupBy, quantile, ratesInterval, queryTime)
+}
+
+// GetRequestSignals queries Prometheus to fetch the request rate, the error ratio and the latency (ms) at the given quantile
+// of the requests matching the labels, with a sample per value of the groupBy label. A non-empty offset (i.e. 1d, 1w)
+// shifts the evaluation back in time, to compare the signals with a baseline.
+func (in *Client) GetRequestSignals(labels, groupBy string, quantile float64, ratesInterval, offset string, queryTime time.Time) (RequestSignals, error) {
+	log.Tracef("GetRequestSignals [labels: %s] [groupBy: %s] [quantile: %v] [ratesInterval: %s] [offset: %s] [queryTime: %s]", labels, groupBy, quantile, ratesInterval, offset, queryTime.String())
+	return getRequestSignals(in.api, labels, groupBy, quantile, ratesInterval, offset, queryTime)
+}
+
+// GetSLOErrorRatios queries Prometheus to fetch, for each window, the ratio of bad requests received by the destination
+// matching the labels: failing requests or, when latencyThreshold (ms) is set, requests slower than the threshold.
+// Windows without requests are left out of the result.
+func (in *Client) GetSLOErrorRatios(labels string, latencyThreshold float64, windows []string, queryTime time.Time) (map[string]float64, error) {
+	log.Tracef("GetSLOErrorRatios [labels: %s] [latencyThreshold: %v] [windows: %v] [queryTime: %s]", labels, latencyThreshold, windows, queryTime.String())
+	return getSLOErrorRatios(in.api, labels, latencyThreshold, windows, queryTime)
+}
+
+// GetContainerRestarts queries Prometheus to fetch the restarts of the containers of a namespace in the interval,
+// with a sample per pod and container. It relies on kube-state-metrics: the result is empty when it isn't scraped.
+func (in *Client) GetContainerRestarts(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetContainerRestarts [namespace: %s] [ratesInterval: %s] [queryTime: %s]", namespace, ratesInterval, queryTime.String())
+	return getContainerRestarts(in.api, namespace, ratesInterval, queryTime)
+}
+
+// FetchQueryRange fetches a PromQL expression in given range
+func (in *Client) FetchQueryRange(query string, q *RangeQuery) Metric {
+	log.Tracef("FetchQueryRange [query: %s] [range: %v]", query, q.Range)
+	return fetchRange(in.api, query, q.Range)
+}
+
 // FetchRange fetches a simple metric (gauge or counter) in given range
 func (in *Client) FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric {
 	query := fmt.Sprintf("%s(%s%s)", aggregator, metricName, labels)
@@ -278,3 +333,22 @@ func (in *Client) GetMetricsForLabels(labels []string) ([]string, error) {
 	}
 	return names, nil
 }
+
+// GetLabelValuesForSeries returns the sorted distinct values of a label among the series matching the selectors
+// in the given time range
+func (in *Client) GetLabelValuesForSeries(label string, selectors []string, start, end time.Time) ([]string, error) {
+	results, err := in.api.Series(context.Background(), selectors, start, end)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	values := []string{}
+	for _, labelSet := range results {
+		if value, ok := labelSet[model.LabelName(label)]; ok && !found[string(value)] {
+			found[string(value)] = true
+			values = append(values, string(value))
+		}
+	}
+	sort.Strings(values)
+	return values, nil
+}
@@ -21,6 +21,8 @@ const (
 	labelPackage          = "package"
 	labelType             = "type"
 	labelFunction         = "function"
+	labelNamespace        = "namespace"
+	labelSeverity         = "severity"
 )
 
 // MetricsType defines all of Kiali's own internal metrics.
@@ -34,6 +36,7 @@ type MetricsType struct {
 	GoFunctionProcessingTime *prometheus.HistogramVec
 	GoFunctionFailures       *prometheus.CounterVec
 	KubernetesClients        *prometheus.GaugeVec
+	ValidationChecks         *prometheus.GaugeVec
 }
 
 // Metrics contains all of Kiali's own internal metrics.
@@ -103,6 +106,13 @@ var Metrics = MetricsType{
 		},
 		[]string{},
 	),
+	ValidationChecks: prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kiali_validation_checks",
+			Help: "The number of Istio config validation checks found by the background validator.",
+		},
+		[]string{labelNamespace, labelSeverity},
+	),
 }
 
 // SuccessOrFailureMetricType let's you capture metrics for both successes and failures,
@@ -160,6 +170,7 @@ func RegisterInternalMetrics() {
 		Metrics.GoFunctionProcessingTime,
 		Metrics.GoFunctionFailures,
 		Metrics.KubernetesClients,
+		Metrics.ValidationChecks,
 	)
 }
 
@@ -281,3 +292,16 @@ func GetGoFunctionMetric(goPkg string, goType string, goFunc string) SuccessOrFa
 func SetKubernetesClients(clientCount int) {
 	Metrics.KubernetesClients.With(prometheus.Labels{}).Set(float64(clientCount))
 }
+
+// SetValidationChecks sets the number of validation checks of a severity found in a namespace
+func SetValidationChecks(namespace string, severity string, checkCount int) {
+	Metrics.ValidationChecks.With(prometheus.Labels{
+		labelNamespace: namespace,
+		labelSeverity:  severity,
+	}).Set(float64(checkCount))
+}
+
+// DeleteValidationChecks stops exporting the number of validation checks of a severity found in a namespace
+func DeleteValidationChecks(namespace string, severity string) {
+	Metrics.ValidationChecks.DeleteLabelValues(namespace, severity)
+}
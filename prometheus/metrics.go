@@ -3,6 +3,9 @@ package prometheus
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
+	"sync"
 	"time"
 
 	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
@@ -173,6 +176,134 @@ func getRequestRatesForLabel(api prom_v1.API, time time.Time, labels, ratesInter
 	return result.(model.Vector), nil
 }
 
+// getRequestLatencyQuantile retrieves the latency, in milliseconds, of the requests matching the labels at the given quantile,
+// by groupBy label when set
+func getRequestLatencyQuantile(api prom_v1.API, labels, groupBy string, quantile float64, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	by := "le"
+	if groupBy != "" {
+		by += "," + groupBy
+	}
+	query := fmt.Sprintf("histogram_quantile(%s, sum(rate(istio_request_duration_milliseconds_bucket{%s}[%s])) by (%s))",
+		strconv.FormatFloat(quantile, 'f', -1, 64), labels, ratesInterval, by)
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetRequestLatencyQuantile")
+	result, err := api.Query(context.Background(), query, queryTime)
+	if err != nil {
+		return model.Vector{}, err
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	return result.(model.Vector), nil
+}
+
+// getRequestSignals retrieves the request rate, the error ratio and the latency (ms) at the given quantile of the requests
+// matching the labels, by groupBy label when set. When offset is set (i.e. 1w), the signals are the ones of the
+// interval ending at queryTime shifted back by the offset.
+func getRequestSignals(api prom_v1.API, labels, groupBy string, quantile float64, ratesInterval, offset string, queryTime time.Time) (RequestSignals, error) {
+	signals := RequestSignals{}
+	by := ""
+	if groupBy != "" {
+		by = fmt.Sprintf(" by (%s)", groupBy)
+	}
+	rangeSelector := fmt.Sprintf("[%s]", ratesInterval)
+	if offset != "" {
+		rangeSelector += " offset " + offset
+	}
+	rate := func(metric, labels string) string {
+		return fmt.Sprintf("sum(rate(%s{%s}%s))%s", metric, labels, rangeSelector, by)
+	}
+
+	// Same error codes than the default health tolerances. HTTP and gRPC errors are summed when both exist.
+	httpErrors := rate("istio_requests_total", labels+`,request_protocol!="grpc",response_code=~"5.."`)
+	grpcErrors := rate("istio_requests_total", labels+`,request_protocol="grpc",grpc_response_status=~"[1-9]|1[0-6]"`)
+	errors := fmt.Sprintf(`((%s + %s) or %s or %s)`, httpErrors, grpcErrors, httpErrors, grpcErrors)
+	leBy := "le"
+	if groupBy != "" {
+		leBy += "," + groupBy
+	}
+	queries := []struct {
+		query  string
+		result *model.Vector
+	}{
+		{query: rate("istio_requests_total", labels), result: &signals.Rates},
+		{query: fmt.Sprintf("%s / %s", errors, rate("istio_requests_total", labels)), result: &signals.ErrorRatios},
+		{
+			query: fmt.Sprintf("histogram_quantile(%s, sum(rate(istio_request_duration_milliseconds_bucket{%s}%s)) by (%s))",
+				strconv.FormatFloat(quantile, 'f', -1, 64), labels, rangeSelector, leBy),
+			result: &signals.Latencies,
+		},
+	}
+	for _, q := range queries {
+		promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetRequestSignals")
+		result, err := api.Query(context.Background(), q.query, queryTime)
+		if err != nil {
+			return signals, err
+		}
+		promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+		*q.result = result.(model.Vector)
+	}
+	return signals, nil
+}
+
+// getContainerRestarts retrieves the restarts of the containers of a namespace in the interval, by pod and container.
+// increase() extrapolates the counter, so the result is rounded to whole restarts.
+func getContainerRestarts(api prom_v1.API, namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	query := fmt.Sprintf(`round(sum(increase(kube_pod_container_status_restarts_total{namespace="%s"}[%s])) by (pod,container))`, namespace, ratesInterval)
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetContainerRestarts")
+	result, err := api.Query(context.Background(), query, queryTime)
+	if err != nil {
+		return model.Vector{}, err
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	return result.(model.Vector), nil
+}
+
+// getSLOErrorRatios retrieves, for each window, the ratio of bad requests received by the destination matching the labels.
+// Bad requests are the failing ones or, when latencyThreshold (ms) is set, the ones slower than the threshold.
+// Windows are queried concurrently, and windows without requests are left out.
+func getSLOErrorRatios(api prom_v1.API, labels string, latencyThreshold float64, windows []string, queryTime time.Time) (map[string]float64, error) {
+	ratios := make(map[string]float64, len(windows))
+	values := make([]model.Value, len(windows))
+	errs := make([]error, len(windows))
+	wg := sync.WaitGroup{}
+	wg.Add(len(windows))
+	for i, window := range windows {
+		go func(i int, window string) {
+			defer wg.Done()
+			query := buildSLOErrorRatioQuery(labels, latencyThreshold, window)
+			promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetSLOErrorRatios")
+			result, err := api.Query(context.Background(), query, queryTime)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+			values[i] = result
+		}(i, window)
+	}
+	wg.Wait()
+
+	for i, window := range windows {
+		if errs[i] != nil {
+			return ratios, errs[i]
+		}
+		if vector, ok := values[i].(model.Vector); ok && len(vector) > 0 && !math.IsNaN(float64(vector[0].Value)) {
+			ratios[window] = float64(vector[0].Value)
+		}
+	}
+	return ratios, nil
+}
+
+func buildSLOErrorRatioQuery(labels string, latencyThreshold float64, window string) string {
+	if latencyThreshold > 0 {
+		// Without a bucket at the threshold, the query has no sample, rather than counting all the requests as slow
+		le := strconv.FormatFloat(latencyThreshold, 'f', -1, 64)
+		return fmt.Sprintf(`1 - (sum(increase(istio_request_duration_milliseconds_bucket{%s,le="%s"}[%s])) / sum(increase(istio_request_duration_milliseconds_count{%s}[%s])))`,
+			labels, le, window, labels, window)
+	}
+	// Same error codes than the default health tolerances
+	return fmt.Sprintf(`((sum(increase(istio_requests_total{%s,request_protocol!="grpc",response_code=~"5.."}[%s])) or vector(0)) + (sum(increase(istio_requests_total{%s,request_protocol="grpc",grpc_response_status=~"[1-9]|1[0-6]"}[%s])) or vector(0))) / sum(increase(istio_requests_total{%s}[%s]))`,
+		labels, window, labels, window, labels, window)
+}
+
 // roundSignificant will output promQL that performs rounding only if the resulting value is significant, that is, higher than the requested precision
 func roundSignificant(innerQuery string, precision float64) string {
 	return fmt.Sprintf("round(%s, %f) > %f or %s", innerQuery, precision, precision, innerQuery)
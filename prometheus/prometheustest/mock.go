@@ -236,6 +236,11 @@ func (o *PromClientMock) MockAppRequestRates(namespace, app string, in, out mode
 	o.On("GetAppRequestRates", namespace, app, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(in, out, nil)
 }
 
+// MockContainerRestarts mocks GetContainerRestarts for given namespace, returning the restarts vector
+func (o *PromClientMock) MockContainerRestarts(namespace string, restarts model.Vector) {
+	o.On("GetContainerRestarts", namespace, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(restarts, nil)
+}
+
 // MockServiceRequestRates mocks GetServiceRequestRates for given namespace and service, returning in vector
 func (o *PromClientMock) MockServiceRequestRates(namespace, service string, in model.Vector) {
 	o.On("GetServiceRequestRates", namespace, service, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(in, nil)
@@ -281,6 +286,36 @@ func (o *PromClientMock) GetWorkloadRequestRates(namespace, workload, ratesInter
 	return args.Get(0).(model.Vector), args.Get(1).(model.Vector), args.Error(2)
 }
 
+func (o *PromClientMock) GetRequestLatencyQuantile(labels, groupBy string, quantile float64, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(labels, groupBy, quantile, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) GetRequestSignals(labels, groupBy string, quantile float64, ratesInterval, offset string, queryTime time.Time) (prometheus.RequestSignals, error) {
+	args := o.Called(labels, groupBy, quantile, ratesInterval, offset, queryTime)
+	return args.Get(0).(prometheus.RequestSignals), args.Error(1)
+}
+
+func (o *PromClientMock) GetContainerRestarts(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespace, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
+func (o *PromClientMock) GetSLOErrorRatios(labels string, latencyThreshold float64, windows []string, queryTime time.Time) (map[string]float64, error) {
+	args := o.Called(labels, latencyThreshold, windows, queryTime)
+	return args.Get(0).(map[string]float64), args.Error(1)
+}
+
+func (o *PromClientMock) GetLabelValuesForSeries(label string, selectors []string, start, end time.Time) ([]string, error) {
+	args := o.Called(label, selectors, start, end)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (o *PromClientMock) FetchQueryRange(query string, q *prometheus.RangeQuery) prometheus.Metric {
+	args := o.Called(query, q)
+	return args.Get(0).(prometheus.Metric)
+}
+
 func (o *PromClientMock) FetchRange(metricName, labels, grouping, aggregator string, q *prometheus.RangeQuery) prometheus.Metric {
 	args := o.Called(metricName, labels, grouping, aggregator, q)
 	return args.Get(0).(prometheus.Metric)
@@ -318,6 +353,10 @@ func (o *PromClientMock) MockMetric(name string, labels string, q *prometheus.Ra
 	o.On("FetchRateRange", name, []string{labels}, "", q).Return(fakeMetric(value))
 }
 
+func (o *PromClientMock) MockQueryRange(query string, q *prometheus.RangeQuery, value float64) {
+	o.On("FetchQueryRange", query, q).Return(fakeMetric(value))
+}
+
 func (o *PromClientMock) MockHistogram(name string, labels string, q *prometheus.RangeQuery, avg, p99 float64) {
 	o.On("FetchHistogramRange", name, labels, "", q).Return(fakeHistogram(avg, p99))
 }
@@ -0,0 +1,182 @@
+package prometheustest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/prometheus"
+)
+
+func setupQueryCache() (prom_v1.API, *PromAPIMock) {
+	conf := config.NewConfig()
+	conf.ExternalServices.Prometheus.CacheDuration = 7
+	conf.ExternalServices.Prometheus.CacheExpiration = 0
+	conf.ExternalServices.Prometheus.QuerySplitInterval = 600
+	config.Set(conf)
+	api := new(PromAPIMock)
+	return prometheus.NewQueryCache().Wrap(api, "http://prometheus:9090", config.Auth{}), api
+}
+
+func fakeRange(metric string, from time.Time, points int, step time.Duration) model.Matrix {
+	values := []model.SamplePair{}
+	for i := 0; i < points; i++ {
+		ts := from.Add(time.Duration(i) * step)
+		values = append(values, model.SamplePair{Timestamp: model.TimeFromUnixNano(ts.UnixNano()), Value: model.SampleValue(i)})
+	}
+	return model.Matrix{&model.SampleStream{Metric: model.Metric{"app": model.LabelValue(metric)}, Values: values}}
+}
+
+func TestQueryRangeSplitAndReuse(t *testing.T) {
+	assert := assert.New(t)
+	cached, api := setupQueryCache()
+
+	start := time.Unix(1600000200, 0) // aligned on 10 minutes
+	step := time.Minute
+	query := `sum(rate(istio_requests_total{destination_app="reviews"}[1m])) by (response_code)`
+	api.On("QueryRange", mock.Anything, query, prom_v1.Range{Start: start, End: start.Add(9 * time.Minute), Step: step}).Return(fakeRange("reviews", start, 10, step), nil).Once()
+	api.On("QueryRange", mock.Anything, query, prom_v1.Range{Start: start.Add(10 * time.Minute), End: start.Add(19 * time.Minute), Step: step}).Return(fakeRange("reviews", start.Add(10*time.Minute), 10, step), nil).Once()
+
+	// Unaligned range, spanning two sub-ranges
+	value, err := cached.QueryRange(context.Background(), query, prom_v1.Range{Start: start.Add(3*time.Minute + 20*time.Second), End: start.Add(19*time.Minute + 59*time.Second), Step: step})
+	assert.Nil(err)
+	matrix := value.(model.Matrix)
+	assert.Len(matrix, 1)
+	assert.Len(matrix[0].Values, 17)
+	assert.Equal(model.TimeFromUnixNano(start.Add(3*time.Minute).UnixNano()), matrix[0].Values[0].Timestamp)
+	assert.Equal(model.TimeFromUnixNano(start.Add(19*time.Minute).UnixNano()), matrix[0].Values[16].Timestamp)
+
+	// Overlapping range with differently formatted query: settled sub-ranges are reused
+	value, err = cached.QueryRange(context.Background(), `sum ( rate(istio_requests_total{destination_app="reviews"} [1m]) )  by (response_code)`, prom_v1.Range{Start: start, End: start.Add(15 * time.Minute), Step: step})
+	assert.Nil(err)
+	matrix = value.(model.Matrix)
+	assert.Len(matrix[0].Values, 16)
+	api.AssertNumberOfCalls(t, "QueryRange", 2)
+
+	// Returned results don't alter the cache
+	matrix[0].Values[0].Value = 1000
+	delete(matrix[0].Metric, "app")
+	value, _ = cached.QueryRange(context.Background(), query, prom_v1.Range{Start: start, End: start.Add(5 * time.Minute), Step: step})
+	matrix = value.(model.Matrix)
+	assert.Equal(model.SampleValue(0), matrix[0].Values[0].Value)
+	assert.Equal(model.LabelValue("reviews"), matrix[0].Metric["app"])
+	api.AssertNumberOfCalls(t, "QueryRange", 2)
+}
+
+func TestQueryRangeRecentDataIsRefreshed(t *testing.T) {
+	assert := assert.New(t)
+	cached, api := setupQueryCache()
+
+	step := 15 * time.Second
+	query := "sum(up)"
+	api.On("QueryRange", mock.Anything, query, mock.AnythingOfType("v1.Range")).Return(model.Matrix{}, nil)
+
+	now := time.Now()
+	_, err := cached.QueryRange(context.Background(), query, prom_v1.Range{Start: now.Add(-5 * time.Minute), End: now, Step: step})
+	assert.Nil(err)
+	calls := len(api.Calls)
+	// Fresh results are reused during the cache duration
+	_, err = cached.QueryRange(context.Background(), query, prom_v1.Range{Start: now.Add(-5 * time.Minute), End: now, Step: step})
+	assert.Nil(err)
+	assert.Len(api.Calls, calls)
+
+	// Sub-ranges are queried up to now, and aligned on the step
+	for _, call := range api.Calls {
+		r := call.Arguments.Get(2).(prom_v1.Range)
+		assert.Equal(int64(0), r.Start.UnixNano()%int64(step))
+		assert.Equal(int64(0), r.End.UnixNano()%int64(step))
+		assert.False(r.End.After(now))
+	}
+}
+
+func TestQueryCachedDuringCacheDuration(t *testing.T) {
+	assert := assert.New(t)
+	cached, api := setupQueryCache()
+
+	queryTime := time.Unix(1600000200, 0)
+	vector := model.Vector{&model.Sample{Metric: model.Metric{"app": "reviews"}, Value: 5}}
+	api.On("Query", mock.Anything, "sum(up)", queryTime).Return(vector, nil).Once()
+	api.On("Query", mock.Anything, "sum(up)", queryTime.Add(10*time.Second)).Return(vector, nil).Once()
+
+	value, err := cached.Query(context.Background(), "sum(up)", queryTime)
+	assert.Nil(err)
+	assert.Equal(vector, value)
+	value, err = cached.Query(context.Background(), "sum( up )", queryTime.Add(5*time.Second))
+	assert.Nil(err)
+	assert.Equal(vector, value)
+	api.AssertNumberOfCalls(t, "Query", 1)
+
+	_, err = cached.Query(context.Background(), "sum(up)", queryTime.Add(10*time.Second))
+	assert.Nil(err)
+	api.AssertNumberOfCalls(t, "Query", 2)
+}
+
+func TestQueryCacheNotSharedAcrossCredentials(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	cache := prometheus.NewQueryCache()
+
+	queryTime := time.Unix(1600000200, 0)
+	tenantA := new(PromAPIMock)
+	tenantA.On("Query", mock.Anything, "sum(up)", queryTime).Return(model.Vector{&model.Sample{Value: 1}}, nil)
+	tenantB := new(PromAPIMock)
+	tenantB.On("Query", mock.Anything, "sum(up)", queryTime).Return(model.Vector{&model.Sample{Value: 2}}, nil)
+
+	cachedA := cache.Wrap(tenantA, "http://prometheus:9090", config.Auth{Type: config.AuthTypeBearer, Token: "tenant-a"})
+	cachedB := cache.Wrap(tenantB, "http://prometheus:9090", config.Auth{Type: config.AuthTypeBearer, Token: "tenant-b"})
+
+	value, err := cachedA.Query(context.Background(), "sum(up)", queryTime)
+	assert.Nil(err)
+	assert.Equal(model.SampleValue(1), value.(model.Vector)[0].Value)
+	value, err = cachedB.Query(context.Background(), "sum(up)", queryTime)
+	assert.Nil(err)
+	assert.Equal(model.SampleValue(2), value.(model.Vector)[0].Value)
+	tenantA.AssertNumberOfCalls(t, "Query", 1)
+	tenantB.AssertNumberOfCalls(t, "Query", 1)
+}
+
+func TestQueryDeduplicatesConcurrentQueries(t *testing.T) {
+	cached, api := setupQueryCache()
+
+	queryTime := time.Unix(1600000200, 0)
+	api.On("Query", mock.Anything, "sum(up)", queryTime).After(100*time.Millisecond).Return(model.Vector{}, nil)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cached.Query(context.Background(), "sum(up)", queryTime)
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	api.AssertNumberOfCalls(t, "Query", 1)
+}
+
+func TestQueryDeduplicatesConcurrentQueriesAtCloseTimes(t *testing.T) {
+	cached, api := setupQueryCache()
+
+	// Aligned on the cache duration
+	queryTime := time.Unix(1600000199, 0)
+	api.On("Query", mock.Anything, "sum(up)", mock.AnythingOfType("time.Time")).After(100*time.Millisecond).Return(model.Vector{}, nil)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := cached.Query(context.Background(), "sum(up)", queryTime.Add(time.Duration(i)*time.Second))
+			assert.Nil(t, err)
+		}(i)
+	}
+	wg.Wait()
+	api.AssertNumberOfCalls(t, "Query", 1)
+}
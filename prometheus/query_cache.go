@@ -0,0 +1,368 @@
+package prometheus
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"golang.org/x/sync/singleflight"
+
+	kialiConfig "github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+const (
+	// Delay after which the datapoints of a range query are considered final and can be reused until the cache expiration
+	querySettleDelay = time.Minute
+	// Maximum number of sub-ranges a range query is split in; longer ranges are split in longer sub-ranges
+	queryMaxSplits = 24
+)
+
+type (
+	instantQueryResult struct {
+		queryTime time.Time
+		value     model.Value
+	}
+
+	rangeQueryResult struct {
+		start     time.Time
+		end       time.Time
+		queriedAt time.Time
+		settled   bool
+		matrix    model.Matrix
+	}
+
+	// QueryCache caches the results of the queries to Prometheus, keyed by normalized PromQL.
+	// Instant queries are reused during the cache duration after their query time, as for the request rates cache.
+	// Range queries are aligned on their step and split in sub-ranges with fixed boundaries, so that overlapping
+	// ranges, such as the refreshes of a dashboard, only query the sub-ranges that aren't cached or settled yet.
+	// Identical concurrent queries are de-duplicated.
+	QueryCache struct {
+		cacheDuration   time.Duration
+		cacheExpiration time.Duration
+		maxEntries      int
+		splitInterval   time.Duration
+		instantResults  map[string]instantQueryResult
+		rangeResults    map[string]rangeQueryResult
+		lock            sync.RWMutex
+		inflight        singleflight.Group
+	}
+
+	// cachedAPI decorates a Prometheus API with a QueryCache, for queries and range queries only
+	cachedAPI struct {
+		prom_v1.API
+		cache *QueryCache
+		// Identifies the Prometheus and the credentials of the API, which results are not shared with other APIs
+		identity string
+	}
+)
+
+func NewQueryCache() *QueryCache {
+	kConfig := kialiConfig.Get()
+
+	splitInterval := time.Duration(kConfig.ExternalServices.Prometheus.QuerySplitInterval) * time.Second
+	if splitInterval <= 0 {
+		splitInterval = 15 * time.Minute
+	}
+	cache := QueryCache{
+		cacheDuration:   time.Duration(kConfig.ExternalServices.Prometheus.CacheDuration) * time.Second,
+		cacheExpiration: time.Duration(kConfig.ExternalServices.Prometheus.CacheExpiration) * time.Second,
+		maxEntries:      kConfig.ExternalServices.Prometheus.QueryCacheMaxEntries,
+		splitInterval:   splitInterval,
+		instantResults:  make(map[string]instantQueryResult),
+		rangeResults:    make(map[string]rangeQueryResult),
+	}
+
+	if cache.cacheExpiration > 0 {
+		go cache.watchExpiration()
+	}
+
+	return &cache
+}
+
+// Wrap returns an API caching the queries and range queries of the given API, which targets the Prometheus at address
+// with the given credentials. APIs with different credentials, i.e. for different tenants, don't share their results.
+// Credentials are only kept hashed.
+func (c *QueryCache) Wrap(promAPI prom_v1.API, address string, auth kialiConfig.Auth) prom_v1.API {
+	credentials := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%s|%s|%t", auth.Type, auth.Username, auth.Password, auth.Token, auth.CAFile, auth.InsecureSkipVerify)))
+	return &cachedAPI{API: promAPI, cache: c, identity: fmt.Sprintf("%s|%x", address, credentials)}
+}
+
+func (in *cachedAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, api.Error) {
+	key := in.identity + "|" + normalizeQuery(query)
+	if value, ok := in.cache.getInstant(key, ts); ok {
+		log.Tracef("[Prom Query Cache] Query [query: %s] [queryTime: %s]", query, ts.String())
+		return value, nil
+	}
+	// Callers query at the current time: concurrent queries are de-duplicated within the cache duration
+	result, err, _ := in.cache.inflight.Do(fmt.Sprintf("%s|%d", key, alignTime(ts, in.cache.cacheDuration).UnixNano()), func() (interface{}, error) {
+		value, err := in.API.Query(ctx, query, ts)
+		if err != nil {
+			return nil, err
+		}
+		in.cache.setInstant(key, ts, value)
+		return value, nil
+	})
+	if err != nil {
+		return nil, toAPIError(err)
+	}
+	return copyValue(result.(model.Value)), nil
+}
+
+func (in *cachedAPI) QueryRange(ctx context.Context, query string, r prom_v1.Range) (model.Value, api.Error) {
+	step := r.Step
+	start, end := alignTime(r.Start, step), alignTime(r.End, step)
+	if step <= 0 || end.Before(start) {
+		return in.API.QueryRange(ctx, query, r)
+	}
+	key := in.identity + "|" + normalizeQuery(query)
+	split := in.cache.splitDuration(step, end.Sub(start))
+	now := time.Now()
+
+	result := model.Matrix{}
+	streams := make(map[model.Fingerprint]*model.SampleStream)
+	for from := alignTime(start, split); !from.After(end); from = from.Add(split) {
+		// Datapoints of this sub-range that are in the requested range
+		subStart, subEnd := from, from.Add(split-step)
+		if subStart.Before(start) {
+			subStart = start
+		}
+		if subEnd.After(end) {
+			subEnd = end
+		}
+		matrix, err := in.fetchSubRange(ctx, query, fmt.Sprintf("%s|%d|%d", key, step, from.UnixNano()), from, from.Add(split-step), step, subStart, subEnd, now)
+		if err != nil {
+			return nil, err
+		}
+		// Merge the series of the sub-ranges, which are in chronological order
+		for _, stream := range matrix {
+			fingerprint := stream.Metric.Fingerprint()
+			if merged, ok := streams[fingerprint]; ok {
+				merged.Values = append(merged.Values, stream.Values...)
+			} else {
+				streams[fingerprint] = stream
+				result = append(result, stream)
+			}
+		}
+	}
+	return result, nil
+}
+
+// fetchSubRange returns the datapoints between subStart and subEnd of the sub-range [from, to], from the cache when
+// it has them and they are either settled or fresh, or else by querying the whole sub-range up to now
+func (in *cachedAPI) fetchSubRange(ctx context.Context, query, key string, from, to time.Time, step time.Duration, subStart, subEnd, now time.Time) (model.Matrix, api.Error) {
+	if matrix, ok := in.cache.getRange(key, subStart, subEnd, now); ok {
+		log.Tracef("[Prom Query Cache] QueryRange [query: %s] [start: %s] [end: %s]", query, subStart.String(), subEnd.String())
+		return matrix, nil
+	}
+	queryEnd := alignTime(now, step)
+	if queryEnd.Before(subEnd) {
+		queryEnd = subEnd
+	}
+	if queryEnd.After(to) {
+		queryEnd = to
+	}
+	result, err, _ := in.cache.inflight.Do(fmt.Sprintf("%s|%d", key, queryEnd.UnixNano()), func() (interface{}, error) {
+		value, err := in.API.QueryRange(ctx, query, prom_v1.Range{Start: from, End: queryEnd, Step: step})
+		if err != nil {
+			return nil, err
+		}
+		matrix, ok := value.(model.Matrix)
+		if !ok {
+			return nil, fmt.Errorf("unexpected result type %s for range query", value.Type())
+		}
+		result := rangeQueryResult{
+			start:     from,
+			end:       queryEnd,
+			queriedAt: now,
+			settled:   !queryEnd.After(now.Add(-querySettleDelay)),
+			matrix:    matrix,
+		}
+		in.cache.setRange(key, result)
+		return result, nil
+	})
+	if err != nil {
+		return nil, toAPIError(err)
+	}
+	return trimMatrix(result.(rangeQueryResult).matrix, subStart, subEnd), nil
+}
+
+// splitDuration returns the duration of the sub-ranges, a multiple of the step
+func (c *QueryCache) splitDuration(step, queryRange time.Duration) time.Duration {
+	split := c.splitInterval
+	if split < step {
+		split = step
+	} else if split%step != 0 {
+		split += step - split%step
+	}
+	for queryRange/split >= queryMaxSplits {
+		split *= 2
+	}
+	return split
+}
+
+func (c *QueryCache) getInstant(key string, queryTime time.Time) (model.Value, bool) {
+	defer c.lock.RUnlock()
+	c.lock.RLock()
+
+	if result, ok := c.instantResults[key]; ok {
+		if !queryTime.Before(result.queryTime) && queryTime.Sub(result.queryTime) < c.cacheDuration {
+			return copyValue(result.value), true
+		}
+	}
+	return nil, false
+}
+
+func (c *QueryCache) setInstant(key string, queryTime time.Time, value model.Value) {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+
+	c.checkMaxEntries()
+	c.instantResults[key] = instantQueryResult{queryTime: queryTime, value: value}
+}
+
+func (c *QueryCache) getRange(key string, start, end, now time.Time) (model.Matrix, bool) {
+	defer c.lock.RUnlock()
+	c.lock.RLock()
+
+	if result, ok := c.rangeResults[key]; ok {
+		if !start.Before(result.start) && !end.After(result.end) && (result.settled || now.Sub(result.queriedAt) < c.cacheDuration) {
+			return trimMatrix(result.matrix, start, end), true
+		}
+	}
+	return nil, false
+}
+
+func (c *QueryCache) setRange(key string, result rangeQueryResult) {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+
+	c.checkMaxEntries()
+	c.rangeResults[key] = result
+}
+
+// checkMaxEntries clears the cache when it's full. Must be called with the lock held.
+func (c *QueryCache) checkMaxEntries() {
+	if c.maxEntries > 0 && len(c.instantResults)+len(c.rangeResults) >= c.maxEntries {
+		c.instantResults = make(map[string]instantQueryResult)
+		c.rangeResults = make(map[string]rangeQueryResult)
+		log.Infof("[Prom Query Cache] Cleared after reaching %d entries", c.maxEntries)
+	}
+}
+
+// Expiration is done globally, like for the request rates cache
+func (c *QueryCache) watchExpiration() {
+	for {
+		time.Sleep(c.cacheExpiration)
+		c.lock.Lock()
+		c.instantResults = make(map[string]instantQueryResult)
+		c.rangeResults = make(map[string]rangeQueryResult)
+		c.lock.Unlock()
+		log.Infof("[Prom Query Cache] Expired")
+	}
+}
+
+// alignTime rounds a time down to a multiple of the step since the epoch, as Prometheus evaluates range queries
+// at start + k * step: aligned ranges share their datapoints
+func alignTime(t time.Time, step time.Duration) time.Time {
+	if step <= 0 {
+		return t
+	}
+	nanos := t.UnixNano()
+	remainder := nanos % int64(step)
+	if remainder < 0 {
+		remainder += int64(step)
+	}
+	return time.Unix(0, nanos-remainder)
+}
+
+// normalizeQuery collapses the whitespaces of a PromQL query, outside of strings, and removes the ones around brackets
+// and commas
+func normalizeQuery(query string) string {
+	var sb strings.Builder
+	var quote, last byte
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		if quote != 0 {
+			sb.WriteByte(c)
+			if c == '\\' && quote != '`' && i+1 < len(query) {
+				i++
+				sb.WriteByte(query[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			space = true
+			continue
+		case '"', '\'', '`':
+			quote = c
+		}
+		if space && last != 0 && !strings.ContainsRune("(){}[],", rune(c)) && !strings.ContainsRune("(){}[],", rune(last)) {
+			sb.WriteByte(' ')
+		}
+		space = false
+		sb.WriteByte(c)
+		last = c
+	}
+	return sb.String()
+}
+
+// trimMatrix returns a copy of the matrix, with only the datapoints between start and end
+func trimMatrix(matrix model.Matrix, start, end time.Time) model.Matrix {
+	from, to := model.TimeFromUnixNano(start.UnixNano()), model.TimeFromUnixNano(end.UnixNano())
+	trimmed := model.Matrix{}
+	for _, stream := range matrix {
+		values := []model.SamplePair{}
+		for _, pair := range stream.Values {
+			if !pair.Timestamp.Before(from) && !pair.Timestamp.After(to) {
+				values = append(values, pair)
+			}
+		}
+		if len(values) > 0 {
+			trimmed = append(trimmed, &model.SampleStream{Metric: stream.Metric.Clone(), Values: values})
+		}
+	}
+	return trimmed
+}
+
+// copyValue returns a copy of a cached value, so that callers can't alter the cache
+func copyValue(value model.Value) model.Value {
+	switch v := value.(type) {
+	case model.Vector:
+		vector := make(model.Vector, len(v))
+		for i, sample := range v {
+			vector[i] = &model.Sample{Metric: sample.Metric.Clone(), Value: sample.Value, Timestamp: sample.Timestamp}
+		}
+		return vector
+	case model.Matrix:
+		matrix := make(model.Matrix, len(v))
+		for i, stream := range v {
+			matrix[i] = &model.SampleStream{Metric: stream.Metric.Clone(), Values: append([]model.SamplePair{}, stream.Values...)}
+		}
+		return matrix
+	case *model.Scalar:
+		scalar := *v
+		return &scalar
+	case *model.String:
+		str := *v
+		return &str
+	}
+	return value
+}
+
+func toAPIError(err error) api.Error {
+	if apiErr, ok := err.(api.Error); ok {
+		return apiErr
+	}
+	return &prom_v1.Error{Type: prom_v1.ErrBadResponse, Msg: err.Error()}
+}
@@ -41,3 +41,10 @@ type Metric struct {
 
 // Histogram contains Metric objects for several histogram-kind statistics
 type Histogram = map[string]Metric
+
+// RequestSignals holds the request rate, the error ratio and the latency of requests, with a sample per group
+type RequestSignals struct {
+	Rates       model.Vector
+	ErrorRatios model.Vector
+	Latencies   model.Vector
+}
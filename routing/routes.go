@@ -241,6 +241,30 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioConfigList,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/istio/dependencies config istioConfigDependencies
+		// ---
+		// Endpoint to get the references between the Istio objects of a namespace and the Gateways, DestinationRules,
+		// Services, ServiceEntries, WorkloadEntries and workloads they depend on.
+		// When an object is given, only the objects depending on it are returned.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioDependencyGraphResponse
+		//
+		{
+			"IstioConfigDependencies",
+			"GET",
+			"/api/namespaces/{namespace}/istio/dependencies",
+			handlers.IstioConfigDependencies,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object} config istioConfigDetails
 		// ---
 		// Endpoint to get the Istio Config of an Istio object
@@ -332,6 +356,102 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioConfigCreate,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/istio/{object_type}/preview config istioConfigCreatePreview
+		// ---
+		// Endpoint to preview, without creating it, the validations and traffic changes caused by a new Istio object
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigPreviewResponse
+		//
+		{
+			"IstioConfigCreatePreview",
+			"POST",
+			"/api/namespaces/{namespace}/istio/{object_type}/preview",
+			handlers.IstioConfigPreview,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/istio/{object_type}/{object}/preview config istioConfigUpdatePreview
+		// ---
+		// Endpoint to preview, without applying it, the validations and traffic changes caused by a Json Merge Patch of an Istio object
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigPreviewResponse
+		//
+		{
+			"IstioConfigUpdatePreview",
+			"POST",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/preview",
+			handlers.IstioConfigPreview,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/istio/{object_type}/{object}/fix config istioConfigFix
+		// ---
+		// Endpoint to apply the fix proposed for a validation check of an Istio object
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigDetailsResponse
+		//
+		{
+			"IstioConfigFix",
+			"POST",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/fix",
+			handlers.IstioConfigFix,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/sidecars/generate config sidecarsGenerate
+		// ---
+		// Endpoint to propose, for each workload, a minimal Sidecar declaring only the hosts observed in its outbound traffic
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: sidecarsGenerateResponse
+		//
+		{
+			"SidecarsGenerate",
+			"GET",
+			"/api/namespaces/{namespace}/sidecars/generate",
+			handlers.IstioConfigSidecarsGenerate,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/services services serviceList
 		// ---
 		// Endpoint to get the details of a given service
@@ -601,6 +721,27 @@ func NewRoutes() (r *Routes) {
 			handlers.WorkloadUpdate,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/configdrift workloads workloadConfigDrift
+		// ---
+		// Endpoint to compare the Istio configuration with the configuration applied in a workload proxy
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      200: typedIstioValidationsResponse
+		//
+		{
+			"WorkloadConfigDrift",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/configdrift",
+			handlers.WorkloadConfigDrift,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/apps apps appList
 		// ---
 		// Endpoint to get the list of apps for a namespace
@@ -918,6 +1059,69 @@ func NewRoutes() (r *Routes) {
 			handlers.WorkloadHealth,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/slo services serviceSLOs
+		// ---
+		// Evaluate the service level objectives of the given service: error budget and burn rates
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: sloResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"ServiceSLOs",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/slo",
+			handlers.ServiceSLOs,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/slo apps appSLOs
+		// ---
+		// Evaluate the service level objectives of the given app: error budget and burn rates
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: sloResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"AppSLOs",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/slo",
+			handlers.AppSLOs,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/slo workloads workloadSLOs
+		// ---
+		// Evaluate the service level objectives of the given workload: error budget and burn rates
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: sloResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"WorkloadSLOs",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/slo",
+			handlers.WorkloadSLOs,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/metrics namespaces namespaceMetrics
 		// ---
 		// Endpoint to fetch metrics to be displayed, related to a namespace
@@ -960,6 +1164,28 @@ func NewRoutes() (r *Routes) {
 			handlers.NamespaceHealth,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/health/events namespaces namespaceHealthEvents
+		// ---
+		// Get the health changes of the apps, services and workloads of the given namespace, detected by the background health evaluator
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: namespaceHealthEventsResponse
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"NamespaceHealthEvents",
+			"GET",
+			"/api/namespaces/{namespace}/health/events",
+			handlers.NamespaceHealthEvents,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/validations namespaces namespaceValidations
 		// ---
 		// Get validation summary for all objects in the given namespace
@@ -981,6 +1207,50 @@ func NewRoutes() (r *Routes) {
 			handlers.NamespaceValidationSummary,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/validations/history namespaces namespaceValidationsHistory
+		// ---
+		// Get the validations recorded over time by the background validator for the given namespace
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: namespaceValidationsHistoryResponse
+		//      404: notFoundError
+		//      500: internalError
+		//
+		{
+			"NamespaceValidationsHistory",
+			"GET",
+			"/api/namespaces/{namespace}/validations/history",
+			handlers.NamespaceValidationsHistory,
+			true,
+		},
+		// swagger:route GET /mesh/validations namespaces meshValidations
+		// ---
+		// Get a summary of the validations of all the namespaces, grouped by check type and severity, with the top offending objects
+		//
+		//     Produces:
+		//     - application/json
+		//     - text/csv
+		//     - text/html
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      200: meshValidationsResponse
+		//      400: badRequestError
+		//      500: internalError
+		//
+		{
+			"MeshValidationsReport",
+			"GET",
+			"/api/mesh/validations",
+			handlers.MeshValidationsReport,
+			true,
+		},
 		// swagger:route GET /mesh/tls tls meshTls
 		// ---
 		// Get TLS status for the whole mesh
@@ -1216,6 +1486,29 @@ func NewRoutes() (r *Routes) {
 			handlers.GetGrafanaInfo,
 			true,
 		},
+		// swagger:route POST /grafana/dashboards/convert integrations grafanaDashboardConvert
+		// ---
+		// Converts a Grafana dashboard JSON into a MonitoringDashboard resource, reporting the panels, targets and variables that can't be converted
+		//
+		//     Consumes:
+		//     - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      200: grafanaDashboardConversionResponse
+		//
+		{
+			"GrafanaDashboardConvert",
+			"POST",
+			"/api/grafana/dashboards/convert",
+			handlers.ConvertGrafanaDashboard,
+			true,
+		},
 		// swagger:route GET /jaeger integrations jaegerInfo
 		// ---
 		// Get the jaeger URL and other descriptors
@@ -1455,6 +1748,27 @@ func NewRoutes() (r *Routes) {
 			HandlerFunc:   handlers.MetricsStats,
 			Authenticated: true,
 		},
+		// swagger:route POST /stats/compare stats metricsComparison
+		// ---
+		// Compares the request rate, error ratio and response times of a candidate to the ones of a baseline, such as two versions of an app
+		//
+		// 		Produces:
+		//		- application/json
+		//
+		//		Schemes: http, https
+		//
+		// responses:
+		//    400: badRequestError
+		//    503: serviceUnavailableError
+		//		500: internalError
+		//		200: metricsComparisonResponse
+		{
+			Name:          "MetricsComparison",
+			Method:        "POST",
+			Pattern:       "/api/stats/compare",
+			HandlerFunc:   handlers.MetricsComparison,
+			Authenticated: true,
+		},
 	}
 
 	return
@@ -84,6 +84,12 @@ func (s *Server) Start() {
 	if conf.Server.MetricsEnabled {
 		StartMetricsServer()
 	}
+
+	// Start the background validator, if enabled
+	business.StartValidationsHistory()
+
+	// Start the background health evaluator, if enabled
+	business.StartHealthNotifications()
 }
 
 // Stop the HTTP server
@@ -41,6 +41,16 @@ var (
 	istioRCVersionExpr        = regexp.MustCompile(`(\d+\.\d+.\d+)-((?:alpha|beta|rc|RC)\.\d+)`)
 	istioSnapshotVersionExpr  = regexp.MustCompile(`istio-release-([0-9]+\.[0-9]+)(-[0-9]{8})`)
 	istioVersionExpr          = regexp.MustCompile(`([0-9]+\.[0-9]+\.[0-9]+)`)
+	istioReleaseExpr          = regexp.MustCompile(`^([0-9]+\.[0-9]+)`)
+
+	// Istio release each Maistra / OpenShift Service Mesh release is based on
+	maistraIstioReleases = map[string]string{
+		"0":   "1.0",
+		"1.0": "1.1",
+		"1.1": "1.4",
+		"2.0": "1.6",
+		"2.1": "1.9",
+	}
 )
 
 func getVersions() {
@@ -237,6 +247,36 @@ func parseIstioRawVersion(rawVersion string) (*ExternalServiceInfo, error) {
 	return &product, nil
 }
 
+// IstioRelease returns the Istio release ("major.minor") implemented by the control plane.
+// Maistra and OpenShift Service Mesh versions are translated to the Istio release they are based on.
+// The release is empty when the implementation or its version are not recognized.
+func IstioRelease() (string, error) {
+	product, err := istioVersion()
+	if err != nil {
+		return "", err
+	}
+	return istioRelease(*product), nil
+}
+
+func istioRelease(product ExternalServiceInfo) string {
+	switch product.Name {
+	case "Maistra", "Maistra Project", "OpenShift Service Mesh":
+		parts := strings.Split(product.Version, ".")
+		if len(parts) < 2 {
+			return ""
+		}
+		if release, found := maistraIstioReleases[parts[0]+"."+parts[1]]; found {
+			return release
+		}
+		return maistraIstioReleases[parts[0]]
+	case "Istio", "Istio Snapshot", "Istio RC", "Istio Dev":
+		if release := istioReleaseExpr.FindStringSubmatch(product.Version); release != nil {
+			return release[1]
+		}
+	}
+	return ""
+}
+
 type p8sResponseVersion struct {
 	Version  string `json:"version"`
 	Revision string `json:"revision"`
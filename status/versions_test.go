@@ -187,6 +187,27 @@ func TestParseIstioRawVersion(t *testing.T) {
 	}
 }
 
+func TestIstioRelease(t *testing.T) {
+	releasesToTest := map[string]string{
+		"redhat@redhat-docker.io/maistra-0.7.0-1-3a13-unknown":                                                        "1.0",
+		"Maistra_1.1.0-291c5419cf19d2b015e7e5dee970c458fb8f1982-Clean":                                                "1.4",
+		"OSSM_2.0.1-291c5419cf19d2b015e7e5dee970c458fb8f1982-Clean":                                                   "1.6",
+		"OSSM_9.0.0-291c5419cf19d2b015e7e5dee970c458fb8f1982-Clean":                                                   "",
+		"root@f72e3d3ef3c2-docker.io/istio-release-1.0-20180927-21-10-cbe9c05c470ec1924f7bcf02334b183e7e6175cb-Clean": "1.0",
+		"root@f72e3d3ef3c2-docker.io/1.7.0-alpha.1-cd46a166947eac363380c3aa3523b26a8c391f98-dirty-Modified":           "1.7",
+		"1.5-alpha.dbd2aca8887fb42c2bb358417621a78de372f906-dbd2aca8887fb42c2bb358417621a78de372f906-Clean":           "1.5",
+		"1.6.8":                       "1.6",
+		"some-unknown-version-string": "",
+	}
+
+	for rawVersion, expected := range releasesToTest {
+		p, _ := parseIstioRawVersion(rawVersion)
+		if release := istioRelease(*p); release != expected {
+			t.Errorf("Istio release of [%s] is incorrect, got [%s], want [%s]", rawVersion, release, expected)
+		}
+	}
+}
+
 func TestValidateVersion(t *testing.T) {
 	result := validateVersion(">= 0.7.1", "0.7.1")
 
@@ -40,3 +40,25 @@ func CreateAuthorizationPolicy(sourceNamespaces, operationMethods, operationHost
 		},
 	}).DeepCopyIstioObject()
 }
+
+func CreateAuthorizationPolicyWithRules(name, action string, selector map[string]interface{}, rules []interface{}) kubernetes.IstioObject {
+	ap := (&kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        name,
+			Namespace:   "bookinfo",
+			ClusterName: "svc.cluster.local",
+		},
+		Spec: map[string]interface{}{
+			"action": action,
+		},
+	}).DeepCopyIstioObject()
+	if selector != nil {
+		ap.GetSpec()["selector"] = map[string]interface{}{
+			"matchLabels": selector,
+		}
+	}
+	if rules != nil {
+		ap.GetSpec()["rules"] = rules
+	}
+	return ap
+}
@@ -0,0 +1,25 @@
+package data
+
+import (
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func CreateRequestAuthentication(name, namespace string, selector map[string]interface{}, jwtRules []interface{}) kubernetes.IstioObject {
+	ra := (&kubernetes.GenericIstioObject{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: map[string]interface{}{
+			"jwtRules": jwtRules,
+		},
+	}).DeepCopyIstioObject()
+	if selector != nil {
+		ra.GetSpec()["selector"] = map[string]interface{}{
+			"matchLabels": selector,
+		}
+	}
+	return ra
+}
@@ -1,6 +1,7 @@
 package httputil
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
@@ -44,6 +45,31 @@ func HttpGet(url string, auth *config.Auth, timeout time.Duration) ([]byte, int,
 	return body, resp.StatusCode, err
 }
 
+// HttpPost posts the body with the given content type, and returns the response body and status code
+func HttpPost(url string, auth *config.Auth, body []byte, contentType string, timeout time.Duration) ([]byte, int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	client := http.Client{Timeout: timeout}
+	if auth != nil {
+		transport, err := AuthTransport(auth, &http.Transport{})
+		if err != nil {
+			return nil, 0, err
+		}
+		client.Transport = transport
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	return respBody, resp.StatusCode, err
+}
+
 type authRoundTripper struct {
 	auth       string
 	originalRT http.RoundTripper
@@ -12,3 +12,27 @@ func RemoveNilValues(root interface{}) {
 		}
 	}
 }
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) over target and returns the result.
+// Null values in the patch remove the keys from the target; maps are merged recursively
+// and any other value replaces the target one.
+func MergePatch(target, patch interface{}) interface{} {
+	mPatch, isMap := patch.(map[string]interface{})
+	if !isMap {
+		return patch
+	}
+
+	mTarget, isMap := target.(map[string]interface{})
+	if !isMap {
+		mTarget = map[string]interface{}{}
+	}
+
+	for k, v := range mPatch {
+		if v == nil {
+			delete(mTarget, k)
+		} else {
+			mTarget[k] = MergePatch(mTarget[k], v)
+		}
+	}
+	return mTarget
+}
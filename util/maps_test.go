@@ -39,3 +39,34 @@ func TestRemoveNilValues(t *testing.T) {
 	assert.True(t, k3k1)
 	assert.True(t, k3k3k1)
 }
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"k1": "v1",
+		"k2": "v2",
+		"k3": map[string]interface{}{
+			"k3k1": "k3v1",
+			"k3k2": "k3v2",
+		},
+		"k4": []interface{}{"a", "b"},
+	}
+	patch := map[string]interface{}{
+		"k1": "new",
+		"k2": nil,
+		"k3": map[string]interface{}{
+			"k3k2": nil,
+			"k3k3": "k3v3",
+		},
+		"k4": []interface{}{"c"},
+		"k5": map[string]interface{}{"k5k1": "k5v1"},
+	}
+
+	result := MergePatch(target, patch).(map[string]interface{})
+
+	assert.Equal(t, "new", result["k1"])
+	_, k2 := result["k2"]
+	assert.False(t, k2)
+	assert.Equal(t, map[string]interface{}{"k3k1": "k3v1", "k3k3": "k3v3"}, result["k3"])
+	assert.Equal(t, []interface{}{"c"}, result["k4"])
+	assert.Equal(t, map[string]interface{}{"k5k1": "k5v1"}, result["k5"])
+}